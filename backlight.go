@@ -0,0 +1,121 @@
+package display
+
+import (
+	"sync"
+	"time"
+
+	"periph.io/x/conn/v3/gpio"
+	"periph.io/x/conn/v3/physic"
+)
+
+// Backlight PWM frequencies.
+const (
+	// BacklightFrequency is the frequency for pins with hardware PWM.
+	BacklightFrequency = 2 * physic.KiloHertz
+
+	// SoftwareBacklightFrequency is the frequency for pins without hardware PWM, which are switched by a
+	// goroutine.
+	SoftwareBacklightFrequency = 200 * physic.Hertz
+)
+
+// Backlight controls the brightness of a backlight connected to a GPIO pin.
+//
+// The brightness is set with hardware PWM if the pin supports it. Other pins are switched on and off in
+// software, which costs some CPU time and may flicker on a busy system.
+type Backlight struct {
+	pin   gpio.PinOut
+	mu    sync.Mutex
+	level uint8
+	soft  bool          // pin doesn't support hardware PWM
+	stop  chan struct{} // stops the software PWM
+	done  chan struct{} // closed when the software PWM has stopped
+}
+
+// NewBacklight returns a backlight on pin, the brightness is not changed until calling SetBrightness.
+func NewBacklight(pin gpio.PinOut) *Backlight {
+	return &Backlight{pin: pin}
+}
+
+func (b *Backlight) String() string {
+	return "backlight on " + b.pin.String()
+}
+
+// Brightness returns the brightness level.
+func (b *Backlight) Brightness() uint8 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.level
+}
+
+// SetBrightness sets the brightness level, 0 switches the backlight off and 0xFF switches it fully on.
+func (b *Backlight) SetBrightness(level uint8) (err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.stopSoftware()
+	b.level = level
+	switch level {
+	case 0x00:
+		return b.pin.Out(gpio.Low)
+	case 0xFF:
+		return b.pin.Out(gpio.High)
+	}
+
+	if !b.soft {
+		duty := gpio.Duty(uint64(gpio.DutyMax) * uint64(level) / 0xFF)
+		if err = b.pin.PWM(duty, BacklightFrequency); err == nil {
+			return
+		}
+		b.soft = true
+	}
+
+	b.stop, b.done = make(chan struct{}), make(chan struct{})
+	go b.software(level, b.stop, b.done)
+	return nil
+}
+
+// Close stops the software PWM and switches the backlight off.
+func (b *Backlight) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.stopSoftware()
+	b.level = 0
+	return b.pin.Out(gpio.Low)
+}
+
+// stopSoftware stops the software PWM, if it is running.
+func (b *Backlight) stopSoftware() {
+	if b.stop != nil {
+		close(b.stop)
+		<-b.done
+		b.stop, b.done = nil, nil
+	}
+}
+
+// software switches the pin with the duty cycle for level, until stop is closed.
+func (b *Backlight) software(level uint8, stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+
+	var (
+		period = SoftwareBacklightFrequency.Period()
+		on     = period * time.Duration(level) / 0xFF
+		timer  = time.NewTimer(0)
+	)
+	defer timer.Stop()
+
+	for high := true; ; high = !high {
+		select {
+		case <-stop:
+			return
+		case <-timer.C:
+		}
+		if high {
+			_ = b.pin.Out(gpio.High)
+			timer.Reset(on)
+		} else {
+			_ = b.pin.Out(gpio.Low)
+			timer.Reset(period - on)
+		}
+	}
+}
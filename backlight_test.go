@@ -0,0 +1,138 @@
+package display
+
+import (
+	"testing"
+	"time"
+
+	"periph.io/x/conn/v3/gpio"
+
+	"github.com/BeatGlow/display/conntest"
+)
+
+func TestBacklight(t *testing.T) {
+	p := conntest.NewPin("BL")
+	b := NewBacklight(p)
+
+	for _, test := range []struct {
+		Level uint8
+		Want  conntest.PinEvent
+	}{
+		{0x00, conntest.PinEvent{Level: gpio.Low}},
+		{0xFF, conntest.PinEvent{Level: gpio.High}},
+		{0x80, conntest.PinEvent{Level: gpio.High, Duty: gpio.Duty(uint64(gpio.DutyMax) * 0x80 / 0xFF), Frequency: BacklightFrequency}},
+	} {
+		p.Clear()
+		if err := b.SetBrightness(test.Level); err != nil {
+			t.Fatal(err)
+		}
+		events := p.Events()
+		if len(events) != 1 {
+			t.Fatalf("level %d: expected 1 pin event, got %d", test.Level, len(events))
+		}
+		events[0].Time = time.Time{}
+		if events[0] != test.Want {
+			t.Errorf("level %d: expected %+v, got %+v", test.Level, test.Want, events[0])
+		}
+		if v := b.Brightness(); v != test.Level {
+			t.Errorf("expected brightness %d, got %d", test.Level, v)
+		}
+	}
+
+	if err := b.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if p.Read() != gpio.Low {
+		t.Error("expected backlight to be switched off after close")
+	}
+}
+
+func TestBacklightSoftware(t *testing.T) {
+	p := conntest.NewPin("BL")
+	p.NoPWM = true
+	b := NewBacklight(p)
+
+	if err := b.SetBrightness(0x40); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(50 * time.Millisecond)
+
+	var high, low int
+	for _, event := range p.Events() {
+		if event.Duty != 0 {
+			t.Fatalf("expected no hardware PWM, got %+v", event)
+		}
+		if event.Level {
+			high++
+		} else {
+			low++
+		}
+	}
+	if high < 2 || low < 2 {
+		t.Errorf("expected the pin to be switched in software, got %d high and %d low events", high, low)
+	}
+
+	// Full brightness stops the software PWM.
+	if err := b.SetBrightness(0xFF); err != nil {
+		t.Fatal(err)
+	}
+	p.Clear()
+	time.Sleep(20 * time.Millisecond)
+	if events := p.Events(); len(events) != 0 {
+		t.Errorf("expected software PWM to stop, got %d events", len(events))
+	}
+	if p.Read() != gpio.High {
+		t.Error("expected backlight to be on")
+	}
+
+	if err := b.SetBrightness(0x80); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Close(); err != nil {
+		t.Fatal(err)
+	}
+	p.Clear()
+	time.Sleep(20 * time.Millisecond)
+	if events := p.Events(); len(events) != 0 || p.Read() != gpio.Low {
+		t.Errorf("expected backlight to be off after close, got %d events", len(events))
+	}
+}
+
+func TestLCDContrast(t *testing.T) {
+	for _, driver := range []string{"st7735", "st7789"} {
+		t.Run(driver, func(it *testing.T) {
+			var (
+				c = conntest.NewSPIRecorder()
+				p = conntest.NewPin("BL")
+			)
+			d, err := Open(driver, c, &Config{Backlight: p})
+			if err != nil {
+				it.Fatal(err)
+			}
+			if p.Read() != gpio.High {
+				it.Error("expected backlight to be on after init")
+			}
+
+			c.Clear()
+			if err = d.SetContrast(0x40); err != nil {
+				it.Fatal(err)
+			}
+			events := p.Events()
+			if e := events[len(events)-1]; e.Duty != gpio.Duty(uint64(gpio.DutyMax)*0x40/0xFF) {
+				it.Errorf("expected backlight duty for level 0x40, got %+v", e)
+			}
+			if driver == "st7789" {
+				calls := c.Filter(conntest.OpCommand)
+				if len(calls) != 1 || calls[0].Command != st7789WRDISBV {
+					it.Errorf("expected WRDISBV command, got %v", calls)
+				}
+			}
+
+			if err = d.Close(); err != nil {
+				it.Fatal(err)
+			}
+			if p.Read() != gpio.Low {
+				it.Error("expected backlight to be off after close")
+			}
+		})
+	}
+}
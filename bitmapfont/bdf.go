@@ -0,0 +1,246 @@
+package bitmapfont
+
+import (
+	"bufio"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ParseBDF parses a font in the Glyph Bitmap Distribution Format. Glyphs are mapped to runes with the
+// CHARSET_REGISTRY and CHARSET_ENCODING properties of the font, unencoded glyphs are skipped.
+func ParseBDF(r io.Reader) (*Face, error) {
+	p := &bdfParser{
+		s: bufio.NewScanner(r),
+		font: parsedFont{
+			props:       make(map[string]string),
+			defaultChar: -1,
+		},
+	}
+	if err := p.parse(); err != nil {
+		if p.line > 0 {
+			return nil, fmt.Errorf("bitmapfont: bdf line %d: %w", p.line, err)
+		}
+		return nil, fmt.Errorf("bitmapfont: bdf: %w", err)
+	}
+	return newFace(&p.font)
+}
+
+type bdfParser struct {
+	s    *bufio.Scanner
+	line int
+	font parsedFont
+
+	// Font wide defaults for glyphs.
+	bounds  image.Rectangle
+	advance int
+}
+
+// next returns the keyword and arguments of the next line that isn't empty or a comment.
+func (p *bdfParser) next() (keyword string, args []string, err error) {
+	for p.s.Scan() {
+		p.line++
+		fields := strings.Fields(p.s.Text())
+		if len(fields) == 0 || fields[0] == "COMMENT" {
+			continue
+		}
+		return fields[0], fields[1:], nil
+	}
+	if err := p.s.Err(); err != nil {
+		return "", nil, err
+	}
+	return "", nil, io.ErrUnexpectedEOF
+}
+
+// ints parses the arguments as n integers.
+func ints(args []string, n int) ([]int, error) {
+	if len(args) < n {
+		return nil, fmt.Errorf("expected %d values, got %d", n, len(args))
+	}
+	v := make([]int, n)
+	for i := range v {
+		var err error
+		if v[i], err = strconv.Atoi(args[i]); err != nil {
+			return nil, err
+		}
+	}
+	return v, nil
+}
+
+// maxGlyphSize is the maximum width and height of a glyph, in pixels.
+const maxGlyphSize = 1024
+
+// bbx returns the glyph bounds relative to the dot for the BBX or FONTBOUNDINGBOX arguments.
+func bbx(args []string) (image.Rectangle, error) {
+	v, err := ints(args, 4)
+	if err != nil {
+		return image.Rectangle{}, err
+	}
+	if v[0] < 0 || v[1] < 0 || v[0] > maxGlyphSize || v[1] > maxGlyphSize {
+		return image.Rectangle{}, fmt.Errorf("invalid bounding box size %dx%d", v[0], v[1])
+	}
+	// The offset is to the bottom left corner, with y pointing up.
+	return image.Rect(v[2], -v[3]-v[1], v[2]+v[0], -v[3]), nil
+}
+
+func (p *bdfParser) parse() error {
+	keyword, _, err := p.next()
+	if err != nil {
+		return err
+	}
+	if keyword != "STARTFONT" {
+		return errors.New("not a BDF font")
+	}
+
+	for {
+		keyword, args, err := p.next()
+		if err != nil {
+			return err
+		}
+		switch keyword {
+		case "FONT":
+			p.font.props["FONT"] = strings.Join(args, " ")
+		case "FONTBOUNDINGBOX":
+			if p.bounds, err = bbx(args); err != nil {
+				return err
+			}
+			p.advance = p.bounds.Dx()
+		case "DWIDTH":
+			v, err := ints(args, 1)
+			if err != nil {
+				return err
+			}
+			p.advance = v[0]
+		case "STARTPROPERTIES":
+			if err := p.properties(); err != nil {
+				return err
+			}
+		case "CHARS":
+			// Glyphs follow.
+		case "STARTCHAR":
+			if err := p.char(); err != nil {
+				return err
+			}
+		case "ENDFONT":
+			p.finish()
+			return nil
+		}
+	}
+}
+
+// finish sets the font metrics from the properties, or else from the font bounding box.
+func (p *bdfParser) finish() {
+	p.font.ascent, p.font.descent = -p.bounds.Min.Y, p.bounds.Max.Y
+	if v, err := strconv.Atoi(p.font.props["FONT_ASCENT"]); err == nil {
+		p.font.ascent = v
+	}
+	if v, err := strconv.Atoi(p.font.props["FONT_DESCENT"]); err == nil {
+		p.font.descent = v
+	}
+	if v, err := strconv.Atoi(p.font.props["DEFAULT_CHAR"]); err == nil {
+		p.font.defaultChar = v
+	}
+}
+
+// properties parses the properties up to ENDPROPERTIES.
+func (p *bdfParser) properties() error {
+	for {
+		if !p.s.Scan() {
+			if err := p.s.Err(); err != nil {
+				return err
+			}
+			return io.ErrUnexpectedEOF
+		}
+		p.line++
+		line := strings.TrimSpace(p.s.Text())
+		name, value, _ := strings.Cut(line, " ")
+		switch name {
+		case "ENDPROPERTIES":
+			return nil
+		case "", "COMMENT":
+			continue
+		}
+		value = strings.TrimSpace(value)
+		if strings.HasPrefix(value, `"`) {
+			// Quotes in strings are doubled.
+			value = strings.ReplaceAll(strings.Trim(value, `"`), `""`, `"`)
+		}
+		p.font.props[name] = value
+	}
+}
+
+// char parses a glyph up to ENDCHAR.
+func (p *bdfParser) char() error {
+	g := parsedGlyph{
+		code:    -1,
+		bounds:  p.bounds,
+		advance: p.advance,
+	}
+	for {
+		keyword, args, err := p.next()
+		if err != nil {
+			return err
+		}
+		switch keyword {
+		case "ENCODING":
+			v, err := ints(args, 1)
+			if err != nil {
+				return err
+			}
+			g.code = v[0]
+		case "DWIDTH":
+			v, err := ints(args, 1)
+			if err != nil {
+				return err
+			}
+			g.advance = v[0]
+		case "BBX":
+			if g.bounds, err = bbx(args); err != nil {
+				return err
+			}
+		case "BITMAP":
+			if g.pix, err = p.bitmap(g.bounds.Dx(), g.bounds.Dy()); err != nil {
+				return err
+			}
+		case "ENDCHAR":
+			if g.pix == nil {
+				g.pix = make([]uint8, g.bounds.Dx()*g.bounds.Dy())
+			} else if len(g.pix) != g.bounds.Dx()*g.bounds.Dy() {
+				return fmt.Errorf("bitmap doesn't match bounding box %dx%d", g.bounds.Dx(), g.bounds.Dy())
+			}
+			if g.code >= 0 {
+				p.font.glyphs = append(p.font.glyphs, g)
+			}
+			return nil
+		}
+	}
+}
+
+// bitmap parses the hexadecimal rows of a glyph of w×h pixels, with the leftmost pixel in the most
+// significant bit.
+func (p *bdfParser) bitmap(w, h int) ([]uint8, error) {
+	pix := make([]uint8, w*h)
+	for y := 0; y < h; y++ {
+		keyword, _, err := p.next()
+		if err != nil {
+			return nil, err
+		}
+		row, err := hex.DecodeString(keyword)
+		if err != nil {
+			return nil, fmt.Errorf("invalid bitmap row: %w", err)
+		}
+		if len(row) < (w+7)/8 {
+			return nil, fmt.Errorf("bitmap row %q is shorter than %d pixels", keyword, w)
+		}
+		for x := 0; x < w; x++ {
+			if row[x/8]&(0x80>>(x%8)) != 0 {
+				pix[y*w+x] = 0xff
+			}
+		}
+	}
+	return pix, nil
+}
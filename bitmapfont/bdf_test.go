@@ -0,0 +1,237 @@
+package bitmapfont
+
+import (
+	"image"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+
+	"golang.org/x/image/math/fixed"
+
+	"github.com/BeatGlow/display/draw"
+	"github.com/BeatGlow/display/pixel"
+)
+
+func loadBDF(t *testing.T) *Face {
+	t.Helper()
+	f, err := os.Open("testdata/test.bdf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	face, err := ParseBDF(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return face
+}
+
+// art returns the pixels of r in i, with '#' for pixels that are on.
+func art(i image.Image, r image.Rectangle) []string {
+	var rows []string
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		var row strings.Builder
+		for x := r.Min.X; x < r.Max.X; x++ {
+			if i.At(x, y) == pixel.On {
+				row.WriteByte('#')
+			} else {
+				row.WriteByte('.')
+			}
+		}
+		rows = append(rows, row.String())
+	}
+	return rows
+}
+
+// glyphTests are the glyphs of testdata/test.bdf.
+var glyphTests = []struct {
+	Rune    rune
+	Bounds  image.Rectangle // relative to the dot
+	Advance int
+}{
+	{' ', image.Rect(0, 0, 0, 0), 4},
+	{'?', image.Rect(0, -7, 4, 0), 5},
+	{'A', image.Rect(0, -7, 5, 0), 6},
+	{'g', image.Rect(0, -5, 4, 2), 5},
+	{'i', image.Rect(0, -7, 1, 0), 2},
+	{'é', image.Rect(0, -8, 4, 0), 5},
+	{'☃', image.Rect(0, -5, 9, -2), 10},
+}
+
+// checkFace checks a face parsed from testdata/test.bdf.
+func checkFace(t *testing.T, face *Face) {
+	t.Helper()
+	if want := "-test-fixed-medium-r-normal--9-90-75-75-p-50-iso10646-1"; face.Name != want {
+		t.Errorf("expected name %q, got %q", want, face.Name)
+	}
+	if face.Ascent != 7 || face.Descent != 2 {
+		t.Errorf("expected ascent 7 and descent 2, got %d and %d", face.Ascent, face.Descent)
+	}
+	if m := face.Metrics(); m.Height != fixed.I(9) || m.CapHeight != fixed.I(7) || m.XHeight != fixed.I(7) {
+		t.Errorf("unexpected metrics %+v", m)
+	}
+	if face.Fallback != '?' {
+		t.Errorf("expected the default character as fallback, got %q", face.Fallback)
+	}
+
+	for _, test := range glyphTests {
+		dot := fixed.P(10, 20)
+		dr, mask, _, advance, ok := face.Glyph(dot, test.Rune)
+		if !ok || mask == nil {
+			t.Errorf("%q: missing glyph", test.Rune)
+			continue
+		}
+		if want := test.Bounds.Add(image.Pt(10, 20)); dr != want {
+			t.Errorf("%q: expected bounds %v, got %v", test.Rune, want, dr)
+		}
+		if advance != fixed.I(test.Advance) {
+			t.Errorf("%q: expected advance %d, got %v", test.Rune, test.Advance, advance)
+		}
+		bounds, advance, ok := face.GlyphBounds(test.Rune)
+		if b := test.Bounds; !ok || bounds != fixed.R(b.Min.X, b.Min.Y, b.Max.X, b.Max.Y) || advance != fixed.I(test.Advance) {
+			t.Errorf("%q: unexpected glyph bounds %v and advance %v", test.Rune, bounds, advance)
+		}
+	}
+
+	// Missing runes are drawn with the fallback glyph.
+	_, mask, mp, advance, ok := face.Glyph(fixed.P(0, 0), 'Z')
+	_, fallback, fallbackp, _, _ := face.Glyph(fixed.P(0, 0), '?')
+	if ok || mask != fallback || mp != fallbackp || advance != fixed.I(5) {
+		t.Error("expected the fallback glyph for a missing rune")
+	}
+	if advance, ok := face.GlyphAdvance('Z'); ok || advance != fixed.I(5) {
+		t.Errorf("expected the advance of the fallback glyph, got %v", advance)
+	}
+	if k := face.Kern('A', 'g'); k != 0 {
+		t.Errorf("expected no kerning, got %v", k)
+	}
+
+	img := pixel.NewMonoImage(34, 11)
+	if dot := draw.Text(img, image.Pt(1, 8), face, pixel.On, "Agi é☃"); dot != image.Pt(33, 8) {
+		t.Errorf("expected the dot at 33,8, got %v", dot)
+	}
+	want := []string{
+		"....................#.............",
+		"..###.......#......#..............",
+		".#...#............................",
+		".#...#..###.#......##..#.......#..",
+		".#####.#..#.#.....#..#..#.....#...",
+		".#...#.#..#.#.....####...#####....",
+		".#...#..###.#.....#...............",
+		".#...#....#.#......###............",
+		".......#..#.......................",
+		"........##........................",
+		"..................................",
+	}
+	for y, row := range art(img, img.Bounds()) {
+		if row != want[y] {
+			t.Errorf("row %d: expected %s, got %s", y, want[y], row)
+		}
+	}
+}
+
+func TestParseBDF(t *testing.T) {
+	checkFace(t, loadBDF(t))
+}
+
+// bdf returns a BDF font with the properties and a glyph of 1×1 pixels for every code.
+func bdf(props []string, codes ...int) string {
+	var s strings.Builder
+	s.WriteString("STARTFONT 2.1\nFONT test\nFONTBOUNDINGBOX 1 1 0 0\n")
+	s.WriteString("STARTPROPERTIES " + strconv.Itoa(len(props)) + "\n")
+	for _, p := range props {
+		s.WriteString(p + "\n")
+	}
+	s.WriteString("ENDPROPERTIES\nCHARS " + strconv.Itoa(len(codes)) + "\n")
+	for _, code := range codes {
+		s.WriteString("STARTCHAR c\nENCODING " + strconv.Itoa(code) + "\nDWIDTH 2 0\nBBX 1 1 0 0\nBITMAP\n80\nENDCHAR\n")
+	}
+	s.WriteString("ENDFONT\n")
+	return s.String()
+}
+
+func TestParseBDFCharset(t *testing.T) {
+	for _, test := range []struct {
+		Props []string
+		Codes []int
+		Want  []rune
+	}{
+		{nil, []int{0x41, 0x2603}, []rune{'A', '☃'}},
+		{[]string{`CHARSET_REGISTRY "ISO8859"`, `CHARSET_ENCODING "1"`}, []int{0x41, 0xe9, 0x2603}, []rune{'A', 'é'}},
+		{[]string{`CHARSET_REGISTRY "ISO8859"`, `CHARSET_ENCODING "2"`}, []int{0xb1, 0xe8}, []rune{'ą', 'č'}},
+		{[]string{`CHARSET_REGISTRY "KOI8"`, `CHARSET_ENCODING "R"`}, []int{0xc1}, []rune{'а'}},
+		{[]string{`CHARSET_REGISTRY "FontSpecific"`, `CHARSET_ENCODING "0"`}, []int{0xe000}, []rune{'\ue000'}},
+	} {
+		face, err := ParseBDF(strings.NewReader(bdf(test.Props, test.Codes...)))
+		if err != nil {
+			t.Errorf("%v: %v", test.Props, err)
+			continue
+		}
+		if len(face.glyphs) != len(test.Want) {
+			t.Errorf("%v: expected %d glyphs, got %d", test.Props, len(test.Want), len(face.glyphs))
+		}
+		for _, r := range test.Want {
+			if _, ok := face.GlyphAdvance(r); !ok {
+				t.Errorf("%v: missing glyph %q", test.Props, r)
+			}
+		}
+	}
+
+	_, err := ParseBDF(strings.NewReader(bdf([]string{`CHARSET_REGISTRY "JISX0208.1983"`, `CHARSET_ENCODING "0"`}, 0x2121)))
+	if err == nil || !strings.Contains(err.Error(), "unsupported charset") {
+		t.Errorf("expected an unsupported charset error, got %v", err)
+	}
+}
+
+func TestParseBDFFallback(t *testing.T) {
+	for _, test := range []struct {
+		Props []string
+		Codes []int
+		Want  rune
+	}{
+		{nil, []int{'A', '?', 0xfffd}, '\ufffd'},
+		{nil, []int{'A', '?'}, '?'},
+		{[]string{"DEFAULT_CHAR 65"}, []int{'A', '?', 0xfffd}, 'A'},
+		{[]string{"DEFAULT_CHAR 66"}, []int{'A', '?', 0xfffd}, '\ufffd'}, // missing default character
+	} {
+		face, err := ParseBDF(strings.NewReader(bdf(test.Props, test.Codes...)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if face.Fallback != test.Want {
+			t.Errorf("%v %q: expected fallback %q, got %q", test.Props, test.Codes, test.Want, face.Fallback)
+		}
+	}
+
+	// Without a fallback glyph, missing glyphs are not drawn.
+	face, err := ParseBDF(strings.NewReader(bdf(nil, 'A')))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, mask, _, _, ok := face.Glyph(fixed.P(0, 0), 'B'); ok || mask != nil {
+		t.Error("expected no glyph")
+	}
+}
+
+func TestParseBDFErrors(t *testing.T) {
+	valid := bdf(nil, 'A')
+	for _, test := range []struct {
+		Name, Data, Want string
+	}{
+		{"empty", "", "unexpected EOF"},
+		{"not bdf", "hello\n", "not a BDF font"},
+		{"truncated", valid[:len(valid)-20], "unexpected EOF"},
+		{"bad bbx", strings.Replace(valid, "BBX 1 1 0 0", "BBX 1 x 0 0", 1), "line 10"},
+		{"negative bbx", strings.Replace(valid, "BBX 1 1 0 0", "BBX -1 1 0 0", 1), "invalid bounding box"},
+		{"huge bbx", strings.Replace(valid, "BBX 1 1 0 0", "BBX 2000000000 2000000000 0 0", 1), "invalid bounding box"},
+		{"bbx after bitmap", strings.Replace(valid, "80\nENDCHAR", "80\nBBX 2 2 0 0\nENDCHAR", 1), "doesn't match bounding box 2x2"},
+		{"bad bitmap", strings.Replace(valid, "BITMAP\n80", "BITMAP\nzz", 1), "invalid bitmap row"},
+		{"short bitmap", strings.Replace(valid, "BBX 1 1 0 0", "BBX 9 1 0 0", 1), "shorter than 9 pixels"},
+	} {
+		_, err := ParseBDF(strings.NewReader(test.Data))
+		if err == nil || !strings.Contains(err.Error(), test.Want) || !strings.HasPrefix(err.Error(), "bitmapfont: ") {
+			t.Errorf("%s: expected error %q, got %v", test.Name, test.Want, err)
+		}
+	}
+}
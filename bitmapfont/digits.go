@@ -0,0 +1,312 @@
+package bitmapfont
+
+import "golang.org/x/image/font/basicfont"
+
+// FaceDigits12x20 is a fixed width face with large 12×20 pixel digits for clocks and readouts, with a 14
+// pixel advance and 24 pixel line height. It has the glyphs " +-.0123456789:".
+var FaceDigits12x20 = fixedFace(basicfont.Face{
+	Advance: 14,
+	Width:   12,
+	Height:  24,
+	Ascent:  20,
+}, 0, glyphsDigits12x20)
+
+var glyphsDigits12x20 = glyphs{
+	' ': ``,
+	'+': `
+		............
+		............
+		............
+		............
+		....###.....
+		....###.....
+		....###.....
+		....###.....
+		############
+		############
+		############
+		....###.....
+		....###.....
+		....###.....
+		....###.....`,
+	'-': `
+		............
+		............
+		............
+		............
+		............
+		............
+		............
+		............
+		############
+		############
+		############`,
+	'.': `
+		............
+		............
+		............
+		............
+		............
+		............
+		............
+		............
+		............
+		............
+		............
+		............
+		............
+		............
+		............
+		............
+		............
+		....###.....
+		....###.....
+		....###.....`,
+	'0': `
+		...######...
+		..########..
+		.###....###.
+		###......###
+		###......###
+		###......###
+		###......###
+		###......###
+		###......###
+		###......###
+		###......###
+		###......###
+		###......###
+		###......###
+		###......###
+		###......###
+		###......###
+		.###....###.
+		..########..
+		...######...`,
+	'1': `
+		.....###....
+		....####....
+		...#####....
+		..######....
+		.....###....
+		.....###....
+		.....###....
+		.....###....
+		.....###....
+		.....###....
+		.....###....
+		.....###....
+		.....###....
+		.....###....
+		.....###....
+		.....###....
+		.....###....
+		.....###....
+		..#########.
+		..#########.`,
+	'2': `
+		..########..
+		.##########.
+		###......###
+		.........###
+		.........###
+		.........###
+		.........###
+		........###.
+		.......###..
+		......###...
+		.....###....
+		....###.....
+		...###......
+		..###.......
+		.###........
+		###.........
+		###.........
+		###.........
+		############
+		############`,
+	'3': `
+		..########..
+		.##########.
+		###......###
+		.........###
+		.........###
+		.........###
+		.........###
+		.........###
+		....#######.
+		....#######.
+		.........###
+		.........###
+		.........###
+		.........###
+		.........###
+		.........###
+		.........###
+		###......###
+		.##########.
+		..########..`,
+	'4': `
+		###......###
+		###......###
+		###......###
+		###......###
+		###......###
+		###......###
+		###......###
+		###......###
+		###......###
+		############
+		############
+		.........###
+		.........###
+		.........###
+		.........###
+		.........###
+		.........###
+		.........###
+		.........###
+		.........###`,
+	'5': `
+		############
+		############
+		###.........
+		###.........
+		###.........
+		###.........
+		###.........
+		###.........
+		##########..
+		###########.
+		.........###
+		.........###
+		.........###
+		.........###
+		.........###
+		.........###
+		.........###
+		###......###
+		.##########.
+		..########..`,
+	'6': `
+		..########..
+		.##########.
+		###......###
+		###.........
+		###.........
+		###.........
+		###.........
+		###.........
+		##########..
+		###########.
+		###......###
+		###......###
+		###......###
+		###......###
+		###......###
+		###......###
+		###......###
+		###......###
+		.##########.
+		..########..`,
+	'7': `
+		############
+		############
+		.........###
+		.........###
+		........###.
+		........###.
+		.......###..
+		.......###..
+		......###...
+		......###...
+		.....###....
+		.....###....
+		....###.....
+		....###.....
+		....###.....
+		....###.....
+		....###.....
+		....###.....
+		....###.....
+		....###.....`,
+	'8': `
+		..########..
+		.##########.
+		###......###
+		###......###
+		###......###
+		###......###
+		###......###
+		###......###
+		.##########.
+		.##########.
+		###......###
+		###......###
+		###......###
+		###......###
+		###......###
+		###......###
+		###......###
+		###......###
+		.##########.
+		..########..`,
+	'9': `
+		..########..
+		.##########.
+		###......###
+		###......###
+		###......###
+		###......###
+		###......###
+		###......###
+		.###########
+		..##########
+		.........###
+		.........###
+		.........###
+		.........###
+		.........###
+		.........###
+		.........###
+		###......###
+		.##########.
+		..########..`,
+	':': `
+		............
+		............
+		............
+		............
+		............
+		....###.....
+		....###.....
+		....###.....
+		............
+		............
+		............
+		............
+		....###.....
+		....###.....
+		....###.....`,
+	'\ufffd': `
+		############
+		#..........#
+		#..........#
+		#..........#
+		#..........#
+		#..........#
+		#..........#
+		#..........#
+		#..........#
+		#..........#
+		#..........#
+		#..........#
+		#..........#
+		#..........#
+		#..........#
+		#..........#
+		#..........#
+		#..........#
+		#..........#
+		############`,
+}
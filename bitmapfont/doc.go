@@ -0,0 +1,13 @@
+// Package bitmapfont provides pixel fonts that render crisply on monochrome displays.
+//
+// The glyphs are drawn pixel by pixel without anti-aliasing, so they don't depend on the gray levels
+// of the display. The faces implement [golang.org/x/image/font.Face] and are drawn with the text
+// functions of the draw package:
+//
+//	draw.Text(img, image.Pt(0, 7), bitmapfont.Face5x7, pixel.On, "Hello")
+//
+// Other fonts, such as Terminus or Spleen, are loaded from BDF or PCF files with [Load]. Glyphs are mapped
+// to Unicode with the charset of the font:
+//
+//	face, err := bitmapfont.Load("/usr/share/fonts/X11/misc/6x13.pcf.gz")
+package bitmapfont
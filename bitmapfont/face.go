@@ -0,0 +1,263 @@
+package bitmapfont
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"image"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/math/fixed"
+	"golang.org/x/text/encoding/charmap"
+)
+
+// Face is a bitmap font face with per-glyph metrics, parsed from a BDF or PCF font. It implements
+// [font.Face] without kerning.
+type Face struct {
+	// Name is the name of the font, usually an X logical font description.
+	Name string
+
+	// Ascent and Descent are the distances above and below the baseline of the font, in pixels.
+	Ascent, Descent int
+
+	// Fallback is the rune drawn for runes missing from the face, if the face has a glyph for it. It
+	// defaults to the default character of the font, or else U+FFFD or '?'.
+	Fallback rune
+
+	capHeight int
+	xHeight   int
+	glyphs    map[rune]*glyph
+	mask      *image.Alpha
+}
+
+type glyph struct {
+	bounds  image.Rectangle // relative to the dot
+	advance int
+	y       int // first row in the mask
+}
+
+// parsedFont is a parsed BDF or PCF font, with glyphs in the encoding of the font.
+type parsedFont struct {
+	props       map[string]string
+	ascent      int
+	descent     int
+	defaultChar int // -1 if none
+	glyphs      []parsedGlyph
+}
+
+type parsedGlyph struct {
+	code    int
+	bounds  image.Rectangle // relative to the dot
+	advance int
+	pix     []uint8 // alpha values, bounds.Dx() per row
+}
+
+// Load reads a BDF or PCF font file, which may be gzip compressed.
+func Load(name string) (*Face, error) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	if bytes.HasPrefix(data, []byte{0x1f, 0x8b}) {
+		z, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("bitmapfont: %s: %w", name, err)
+		}
+		if data, err = io.ReadAll(z); err != nil {
+			return nil, fmt.Errorf("bitmapfont: %s: %w", name, err)
+		}
+	}
+	if bytes.HasPrefix(data, []byte(pcfMagic)) {
+		return parsePCF(data)
+	}
+	return ParseBDF(bytes.NewReader(data))
+}
+
+// newFace maps the glyphs of p to runes and returns the face.
+func newFace(p *parsedFont) (*Face, error) {
+	decode, err := charset(p.props["CHARSET_REGISTRY"], p.props["CHARSET_ENCODING"])
+	if err != nil {
+		return nil, err
+	}
+
+	f := &Face{
+		Name:     p.props["FONT"],
+		Ascent:   p.ascent,
+		Descent:  p.descent,
+		Fallback: '\ufffd',
+		glyphs:   make(map[rune]*glyph, len(p.glyphs)),
+	}
+	var (
+		width  int
+		height int
+		pix    = make([]*parsedGlyph, 0, len(p.glyphs))
+	)
+	for i := range p.glyphs {
+		g := &p.glyphs[i]
+		r, ok := decode(g.code)
+		if !ok {
+			continue
+		}
+		if _, dup := f.glyphs[r]; dup {
+			continue
+		}
+		f.glyphs[r] = &glyph{bounds: g.bounds, advance: g.advance, y: height}
+		width = max(width, g.bounds.Dx())
+		height += g.bounds.Dy()
+		pix = append(pix, g)
+	}
+
+	// The glyphs are stacked in a single mask.
+	f.mask = image.NewAlpha(image.Rect(0, 0, width, height))
+	y := 0
+	for _, g := range pix {
+		w := g.bounds.Dx()
+		for i := 0; i < g.bounds.Dy(); i, y = i+1, y+1 {
+			copy(f.mask.Pix[y*f.mask.Stride:], g.pix[i*w:(i+1)*w])
+		}
+	}
+
+	if r, ok := decode(p.defaultChar); ok && f.glyphs[r] != nil {
+		f.Fallback = r
+	} else if f.glyphs['\ufffd'] == nil && f.glyphs['?'] != nil {
+		f.Fallback = '?'
+	}
+	f.capHeight = f.height(p.props["CAP_HEIGHT"], 'H')
+	f.xHeight = f.height(p.props["X_HEIGHT"], 'x')
+	return f, nil
+}
+
+// height returns the height from the property value, or else the height of r above the baseline, or else
+// the ascent.
+func (f *Face) height(prop string, r rune) int {
+	if v, err := strconv.Atoi(prop); err == nil {
+		return v
+	}
+	if g := f.glyphs[r]; g != nil {
+		return -g.bounds.Min.Y
+	}
+	return f.Ascent
+}
+
+// charmaps are the supported 8 bit charsets, by registry and encoding.
+var charmaps = map[string]*charmap.Charmap{
+	"ISO8859-1":        charmap.ISO8859_1,
+	"ISO8859-2":        charmap.ISO8859_2,
+	"ISO8859-3":        charmap.ISO8859_3,
+	"ISO8859-4":        charmap.ISO8859_4,
+	"ISO8859-5":        charmap.ISO8859_5,
+	"ISO8859-6":        charmap.ISO8859_6,
+	"ISO8859-7":        charmap.ISO8859_7,
+	"ISO8859-8":        charmap.ISO8859_8,
+	"ISO8859-9":        charmap.ISO8859_9,
+	"ISO8859-10":       charmap.ISO8859_10,
+	"ISO8859-13":       charmap.ISO8859_13,
+	"ISO8859-14":       charmap.ISO8859_14,
+	"ISO8859-15":       charmap.ISO8859_15,
+	"ISO8859-16":       charmap.ISO8859_16,
+	"KOI8-R":           charmap.KOI8R,
+	"KOI8-U":           charmap.KOI8U,
+	"IBM-CP437":        charmap.CodePage437,
+	"IBM-CP850":        charmap.CodePage850,
+	"IBM-CP866":        charmap.CodePage866,
+	"MICROSOFT-CP1250": charmap.Windows1250,
+	"MICROSOFT-CP1251": charmap.Windows1251,
+	"MICROSOFT-CP1252": charmap.Windows1252,
+	"MICROSOFT-CP1253": charmap.Windows1253,
+	"MICROSOFT-CP1254": charmap.Windows1254,
+	"MICROSOFT-CP1255": charmap.Windows1255,
+	"MICROSOFT-CP1256": charmap.Windows1256,
+	"MICROSOFT-CP1257": charmap.Windows1257,
+	"MICROSOFT-CP1258": charmap.Windows1258,
+}
+
+// charset returns the function mapping character codes of the charset to runes. Fonts without a charset and
+// font specific charsets, such as icon fonts, map codes to the runes with the same value.
+func charset(registry, encoding string) (func(code int) (rune, bool), error) {
+	name := strings.ToUpper(registry + "-" + encoding)
+	switch {
+	case registry == "", strings.HasPrefix(name, "ISO10646-"), strings.Contains(name, "FONTSPECIFIC"):
+		return func(code int) (rune, bool) {
+			return rune(code), code >= 0 && code <= 0x10ffff
+		}, nil
+	case charmaps[name] != nil:
+		m := charmaps[name]
+		return func(code int) (rune, bool) {
+			if code < 0 || code > 0xff {
+				return 0, false
+			}
+			r := m.DecodeByte(byte(code))
+			return r, r != '\ufffd'
+		}, nil
+	default:
+		return nil, fmt.Errorf("bitmapfont: unsupported charset %s-%s", registry, encoding)
+	}
+}
+
+// Close does nothing.
+func (f *Face) Close() error {
+	return nil
+}
+
+// glyph returns the glyph for r, or the fallback glyph with ok false if the face has no glyph for r.
+func (f *Face) glyph(r rune) (g *glyph, ok bool) {
+	if g = f.glyphs[r]; g != nil {
+		return g, true
+	}
+	return f.glyphs[f.Fallback], false
+}
+
+// Glyph returns the glyph for r at dot, which is rounded to whole pixels.
+func (f *Face) Glyph(dot fixed.Point26_6, r rune) (dr image.Rectangle, mask image.Image, maskp image.Point, advance fixed.Int26_6, ok bool) {
+	g, ok := f.glyph(r)
+	if g == nil {
+		return image.Rectangle{}, nil, image.Point{}, 0, false
+	}
+	p := image.Pt(dot.X.Round(), dot.Y.Round())
+	return g.bounds.Add(p), f.mask, image.Pt(0, g.y), fixed.I(g.advance), ok
+}
+
+// GlyphBounds returns the bounding box of r, relative to the dot.
+func (f *Face) GlyphBounds(r rune) (bounds fixed.Rectangle26_6, advance fixed.Int26_6, ok bool) {
+	g, ok := f.glyph(r)
+	if g == nil {
+		return fixed.Rectangle26_6{}, 0, false
+	}
+	b := g.bounds
+	return fixed.R(b.Min.X, b.Min.Y, b.Max.X, b.Max.Y), fixed.I(g.advance), ok
+}
+
+// GlyphAdvance returns the advance width of r.
+func (f *Face) GlyphAdvance(r rune) (advance fixed.Int26_6, ok bool) {
+	g, ok := f.glyph(r)
+	if g == nil {
+		return 0, false
+	}
+	return fixed.I(g.advance), ok
+}
+
+// Kern returns 0, bitmap fonts have no kerning.
+func (f *Face) Kern(r0, r1 rune) fixed.Int26_6 {
+	return 0
+}
+
+// Metrics returns the metrics of the face.
+func (f *Face) Metrics() font.Metrics {
+	return font.Metrics{
+		Height:     fixed.I(f.Ascent + f.Descent),
+		Ascent:     fixed.I(f.Ascent),
+		Descent:    fixed.I(f.Descent),
+		XHeight:    fixed.I(f.xHeight),
+		CapHeight:  fixed.I(f.capHeight),
+		CaretSlope: image.Point{X: 0, Y: 1},
+	}
+}
+
+// Interface checks
+var (
+	_ font.Face = (*Face)(nil)
+)
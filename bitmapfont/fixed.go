@@ -0,0 +1,64 @@
+package bitmapfont
+
+import (
+	"fmt"
+	"image"
+	"slices"
+	"strings"
+
+	"golang.org/x/image/font/basicfont"
+)
+
+// glyphs maps runes to glyph images, with '#' for set and '.' for clear pixels and one line per row.
+// White space around the rows is ignored.
+type glyphs map[rune]string
+
+// with returns a copy of g with the glyphs of other added or replaced.
+func (g glyphs) with(other glyphs) glyphs {
+	out := make(glyphs, len(g)+len(other))
+	for r, s := range g {
+		out[r] = s
+	}
+	for r, s := range other {
+		out[r] = s
+	}
+	return out
+}
+
+// fixedFace returns f with the glyphs in its mask, each glyph drawn top rows below the top of its cell. The
+// glyphs must be f.Width pixels wide and fit in the cell of f.Ascent+f.Descent rows.
+func fixedFace(f basicfont.Face, top int, g glyphs) *basicfont.Face {
+	var (
+		runes  = make([]rune, 0, len(g))
+		height = f.Ascent + f.Descent
+	)
+	for r := range g {
+		runes = append(runes, r)
+	}
+	slices.Sort(runes)
+
+	mask := image.NewAlpha(image.Rect(0, 0, f.Width, height*len(runes)))
+	for i, r := range runes {
+		y := i*height + top
+		for _, row := range strings.Fields(g[r]) {
+			if len(row) != f.Width || y >= (i+1)*height {
+				panic(fmt.Sprintf("bitmapfont: glyph %q doesn't fit in %dx%d pixels", r, f.Width, height-top))
+			}
+			for x, c := range row {
+				if c == '#' {
+					mask.Pix[mask.PixOffset(x, y)] = 0xff
+				}
+			}
+			y++
+		}
+
+		// Consecutive runes share a range.
+		if n := len(f.Ranges); n > 0 && f.Ranges[n-1].High == r {
+			f.Ranges[n-1].High++
+		} else {
+			f.Ranges = append(f.Ranges, basicfont.Range{Low: r, High: r + 1, Offset: i})
+		}
+	}
+	f.Mask = mask
+	return &f
+}
@@ -0,0 +1,111 @@
+package bitmapfont
+
+import (
+	"image"
+	"strings"
+	"testing"
+
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+
+	"github.com/BeatGlow/display/draw"
+	"github.com/BeatGlow/display/pixel"
+)
+
+var faces = []struct {
+	Name  string
+	Face  *basicfont.Face
+	Runes string
+}{
+	{"5x7", Face5x7, printable()},
+	{"6x8", Face6x8, printable()},
+	{"8x16", Face8x16, printable()},
+	{"digits12x20", FaceDigits12x20, " +-.0123456789:"},
+}
+
+// printable returns the printable ASCII characters.
+func printable() string {
+	var s strings.Builder
+	for r := ' '; r <= '~'; r++ {
+		s.WriteRune(r)
+	}
+	return s.String()
+}
+
+func TestFaces(t *testing.T) {
+	for _, test := range faces {
+		f := test.Face
+		for _, r := range test.Runes {
+			dr, mask, mp, advance, ok := f.Glyph(fixed.P(0, f.Ascent), r)
+			if !ok || mask == nil {
+				t.Errorf("%s: missing glyph %q", test.Name, r)
+				continue
+			}
+			if want := image.Rect(0, 0, f.Width, f.Ascent+f.Descent); dr != want {
+				t.Errorf("%s: glyph %q: expected bounds %v, got %v", test.Name, r, want, dr)
+			}
+			if advance != fixed.I(f.Advance) {
+				t.Errorf("%s: glyph %q: expected advance %d, got %v", test.Name, r, f.Advance, advance)
+			}
+
+			// Glyphs are not anti-aliased.
+			for y := 0; y < dr.Dy(); y++ {
+				for x := 0; x < dr.Dx(); x++ {
+					if a := mask.(*image.Alpha).AlphaAt(mp.X+x, mp.Y+y).A; a != 0 && a != 0xff {
+						t.Fatalf("%s: glyph %q: pixel %d,%d has alpha %#02x", test.Name, r, x, y, a)
+					}
+				}
+			}
+		}
+
+		// Missing glyphs fall back to U+FFFD.
+		_, mask, mp, _, ok := f.Glyph(fixed.P(0, f.Ascent), '€')
+		if _, want, wantp, _, _ := f.Glyph(fixed.P(0, f.Ascent), '\ufffd'); ok || mask != want || mp != wantp {
+			t.Errorf("%s: expected the fallback glyph for a missing rune", test.Name)
+		}
+	}
+}
+
+func TestDescenders(t *testing.T) {
+	// Face6x8 has descenders below the baseline, Face5x7 doesn't.
+	for _, test := range []struct {
+		Face *basicfont.Face
+		Want bool
+	}{
+		{Face5x7, false},
+		{Face6x8, true},
+	} {
+		img := pixel.NewMonoImage(6, 8)
+		draw.Text(img, image.Pt(0, 7), test.Face, pixel.On, "g")
+		var below bool
+		for x := 0; x < 6; x++ {
+			below = below || img.At(x, 7) == pixel.On
+		}
+		if below != test.Want {
+			t.Errorf("%d pixel descent: expected pixels below the baseline %t, got %t", test.Face.Descent, test.Want, below)
+		}
+	}
+}
+
+func TestText(t *testing.T) {
+	img := pixel.NewMonoImage(18, 7)
+	if dot := draw.Text(img, image.Pt(0, 7), Face5x7, pixel.On, "Hi!"); dot != image.Pt(18, 7) {
+		t.Errorf("expected the dot at 18,7, got %v", dot)
+	}
+	want := []string{
+		"#...#...#.....#...",
+		"#...#.........#...",
+		"#...#..##.....#...",
+		"#####...#.....#...",
+		"#...#...#.....#...",
+		"#...#...#.........",
+		"#...#..###....#...",
+	}
+	for y, row := range want {
+		for x, c := range row {
+			if got := img.At(x, y) == pixel.On; got != (c == '#') {
+				t.Fatalf("pixel %d,%d: expected %c", x, y, c)
+			}
+		}
+	}
+}
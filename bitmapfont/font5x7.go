@@ -0,0 +1,819 @@
+package bitmapfont
+
+import "golang.org/x/image/font/basicfont"
+
+// Face5x7 is a fixed width face with 5×7 pixel glyphs for printable ASCII, with a 6 pixel advance and
+// 8 pixel line height. Lowercase letters have no descenders, so all glyphs are above the baseline.
+var Face5x7 = fixedFace(basicfont.Face{
+	Advance: 6,
+	Width:   5,
+	Height:  8,
+	Ascent:  7,
+}, 0, glyphs5x7)
+
+// Face6x8 is Face5x7 in cells of 6×8 pixels, with lowercase descenders in the bottom row. Lines of text
+// match the 8 pixel pages of page addressed displays.
+var Face6x8 = fixedFace(basicfont.Face{
+	Advance: 6,
+	Width:   5,
+	Height:  8,
+	Ascent:  7,
+	Descent: 1,
+}, 0, glyphs5x7.with(descenders6x8))
+
+var glyphs5x7 = glyphs{
+	' ': `
+		.....
+		.....
+		.....
+		.....
+		.....
+		.....
+		.....`,
+	'!': `
+		..#..
+		..#..
+		..#..
+		..#..
+		..#..
+		.....
+		..#..`,
+	'"': `
+		.#.#.
+		.#.#.
+		.#.#.`,
+	'#': `
+		.#.#.
+		.#.#.
+		#####
+		.#.#.
+		#####
+		.#.#.
+		.#.#.`,
+	'$': `
+		..#..
+		.####
+		#.#..
+		.###.
+		..#.#
+		####.
+		..#..`,
+	'%': `
+		##...
+		##..#
+		...#.
+		..#..
+		.#...
+		#..##
+		...##`,
+	'&': `
+		.##..
+		#..#.
+		#.#..
+		.#...
+		#.#.#
+		#..#.
+		.##.#`,
+	'\'': `
+		..#..
+		..#..
+		.#...`,
+	'(': `
+		...#.
+		..#..
+		.#...
+		.#...
+		.#...
+		..#..
+		...#.`,
+	')': `
+		.#...
+		..#..
+		...#.
+		...#.
+		...#.
+		..#..
+		.#...`,
+	'*': `
+		.....
+		..#..
+		#.#.#
+		.###.
+		#.#.#
+		..#..
+		.....`,
+	'+': `
+		.....
+		..#..
+		..#..
+		#####
+		..#..
+		..#..
+		.....`,
+	',': `
+		.....
+		.....
+		.....
+		.....
+		.##..
+		..#..
+		.#...`,
+	'-': `
+		.....
+		.....
+		.....
+		#####`,
+	'.': `
+		.....
+		.....
+		.....
+		.....
+		.....
+		.##..
+		.##..`,
+	'/': `
+		.....
+		....#
+		...#.
+		..#..
+		.#...
+		#....
+		.....`,
+	'0': `
+		.###.
+		#...#
+		#..##
+		#.#.#
+		##..#
+		#...#
+		.###.`,
+	'1': `
+		..#..
+		.##..
+		..#..
+		..#..
+		..#..
+		..#..
+		.###.`,
+	'2': `
+		.###.
+		#...#
+		....#
+		...#.
+		..#..
+		.#...
+		#####`,
+	'3': `
+		#####
+		...#.
+		..#..
+		...#.
+		....#
+		#...#
+		.###.`,
+	'4': `
+		...#.
+		..##.
+		.#.#.
+		#..#.
+		#####
+		...#.
+		...#.`,
+	'5': `
+		#####
+		#....
+		####.
+		....#
+		....#
+		#...#
+		.###.`,
+	'6': `
+		..##.
+		.#...
+		#....
+		####.
+		#...#
+		#...#
+		.###.`,
+	'7': `
+		#####
+		....#
+		...#.
+		..#..
+		.#...
+		.#...
+		.#...`,
+	'8': `
+		.###.
+		#...#
+		#...#
+		.###.
+		#...#
+		#...#
+		.###.`,
+	'9': `
+		.###.
+		#...#
+		#...#
+		.####
+		....#
+		...#.
+		.##..`,
+	':': `
+		.....
+		.##..
+		.##..
+		.....
+		.##..
+		.##..
+		.....`,
+	';': `
+		.....
+		.##..
+		.##..
+		.....
+		.##..
+		..#..
+		.#...`,
+	'<': `
+		...#.
+		..#..
+		.#...
+		#....
+		.#...
+		..#..
+		...#.`,
+	'=': `
+		.....
+		.....
+		#####
+		.....
+		#####`,
+	'>': `
+		.#...
+		..#..
+		...#.
+		....#
+		...#.
+		..#..
+		.#...`,
+	'?': `
+		.###.
+		#...#
+		....#
+		...#.
+		..#..
+		.....
+		..#..`,
+	'@': `
+		.###.
+		#...#
+		....#
+		.##.#
+		#.#.#
+		#.#.#
+		.###.`,
+	'A': `
+		.###.
+		#...#
+		#...#
+		#####
+		#...#
+		#...#
+		#...#`,
+	'B': `
+		####.
+		#...#
+		#...#
+		####.
+		#...#
+		#...#
+		####.`,
+	'C': `
+		.###.
+		#...#
+		#....
+		#....
+		#....
+		#...#
+		.###.`,
+	'D': `
+		###..
+		#..#.
+		#...#
+		#...#
+		#...#
+		#..#.
+		###..`,
+	'E': `
+		#####
+		#....
+		#....
+		####.
+		#....
+		#....
+		#####`,
+	'F': `
+		#####
+		#....
+		#....
+		####.
+		#....
+		#....
+		#....`,
+	'G': `
+		.###.
+		#...#
+		#....
+		#.###
+		#...#
+		#...#
+		.####`,
+	'H': `
+		#...#
+		#...#
+		#...#
+		#####
+		#...#
+		#...#
+		#...#`,
+	'I': `
+		.###.
+		..#..
+		..#..
+		..#..
+		..#..
+		..#..
+		.###.`,
+	'J': `
+		..###
+		...#.
+		...#.
+		...#.
+		...#.
+		#..#.
+		.##..`,
+	'K': `
+		#...#
+		#..#.
+		#.#..
+		##...
+		#.#..
+		#..#.
+		#...#`,
+	'L': `
+		#....
+		#....
+		#....
+		#....
+		#....
+		#....
+		#####`,
+	'M': `
+		#...#
+		##.##
+		#.#.#
+		#.#.#
+		#...#
+		#...#
+		#...#`,
+	'N': `
+		#...#
+		#...#
+		##..#
+		#.#.#
+		#..##
+		#...#
+		#...#`,
+	'O': `
+		.###.
+		#...#
+		#...#
+		#...#
+		#...#
+		#...#
+		.###.`,
+	'P': `
+		####.
+		#...#
+		#...#
+		####.
+		#....
+		#....
+		#....`,
+	'Q': `
+		.###.
+		#...#
+		#...#
+		#...#
+		#.#.#
+		#..#.
+		.##.#`,
+	'R': `
+		####.
+		#...#
+		#...#
+		####.
+		#.#..
+		#..#.
+		#...#`,
+	'S': `
+		.####
+		#....
+		#....
+		.###.
+		....#
+		....#
+		####.`,
+	'T': `
+		#####
+		..#..
+		..#..
+		..#..
+		..#..
+		..#..
+		..#..`,
+	'U': `
+		#...#
+		#...#
+		#...#
+		#...#
+		#...#
+		#...#
+		.###.`,
+	'V': `
+		#...#
+		#...#
+		#...#
+		#...#
+		#...#
+		.#.#.
+		..#..`,
+	'W': `
+		#...#
+		#...#
+		#...#
+		#.#.#
+		#.#.#
+		#.#.#
+		.#.#.`,
+	'X': `
+		#...#
+		#...#
+		.#.#.
+		..#..
+		.#.#.
+		#...#
+		#...#`,
+	'Y': `
+		#...#
+		#...#
+		#...#
+		.#.#.
+		..#..
+		..#..
+		..#..`,
+	'Z': `
+		#####
+		....#
+		...#.
+		..#..
+		.#...
+		#....
+		#####`,
+	'[': `
+		.###.
+		.#...
+		.#...
+		.#...
+		.#...
+		.#...
+		.###.`,
+	'\\': `
+		.....
+		#....
+		.#...
+		..#..
+		...#.
+		....#
+		.....`,
+	']': `
+		.###.
+		...#.
+		...#.
+		...#.
+		...#.
+		...#.
+		.###.`,
+	'^': `
+		..#..
+		.#.#.
+		#...#`,
+	'_': `
+		.....
+		.....
+		.....
+		.....
+		.....
+		.....
+		#####`,
+	'`': `
+		.#...
+		..#..
+		...#.`,
+	'a': `
+		.....
+		.....
+		.###.
+		....#
+		.####
+		#...#
+		.####`,
+	'b': `
+		#....
+		#....
+		#.##.
+		##..#
+		#...#
+		#...#
+		####.`,
+	'c': `
+		.....
+		.....
+		.###.
+		#....
+		#....
+		#...#
+		.###.`,
+	'd': `
+		....#
+		....#
+		.##.#
+		#..##
+		#...#
+		#...#
+		.####`,
+	'e': `
+		.....
+		.....
+		.###.
+		#...#
+		#####
+		#....
+		.###.`,
+	'f': `
+		..##.
+		.#..#
+		.#...
+		###..
+		.#...
+		.#...
+		.#...`,
+	'g': `
+		.....
+		.####
+		#...#
+		#...#
+		.####
+		....#
+		.###.`,
+	'h': `
+		#....
+		#....
+		#.##.
+		##..#
+		#...#
+		#...#
+		#...#`,
+	'i': `
+		..#..
+		.....
+		.##..
+		..#..
+		..#..
+		..#..
+		.###.`,
+	'j': `
+		...#.
+		.....
+		..##.
+		...#.
+		...#.
+		#..#.
+		.##..`,
+	'k': `
+		#....
+		#....
+		#..#.
+		#.#..
+		##...
+		#.#..
+		#..#.`,
+	'l': `
+		.##..
+		..#..
+		..#..
+		..#..
+		..#..
+		..#..
+		.###.`,
+	'm': `
+		.....
+		.....
+		##.#.
+		#.#.#
+		#.#.#
+		#...#
+		#...#`,
+	'n': `
+		.....
+		.....
+		#.##.
+		##..#
+		#...#
+		#...#
+		#...#`,
+	'o': `
+		.....
+		.....
+		.###.
+		#...#
+		#...#
+		#...#
+		.###.`,
+	'p': `
+		.....
+		.....
+		####.
+		#...#
+		####.
+		#....
+		#....`,
+	'q': `
+		.....
+		.....
+		.##.#
+		#..##
+		.####
+		....#
+		....#`,
+	'r': `
+		.....
+		.....
+		#.##.
+		##..#
+		#....
+		#....
+		#....`,
+	's': `
+		.....
+		.....
+		.###.
+		#....
+		.###.
+		....#
+		####.`,
+	't': `
+		.#...
+		.#...
+		###..
+		.#...
+		.#...
+		.#..#
+		..##.`,
+	'u': `
+		.....
+		.....
+		#...#
+		#...#
+		#...#
+		#..##
+		.##.#`,
+	'v': `
+		.....
+		.....
+		#...#
+		#...#
+		#...#
+		.#.#.
+		..#..`,
+	'w': `
+		.....
+		.....
+		#...#
+		#...#
+		#.#.#
+		#.#.#
+		.#.#.`,
+	'x': `
+		.....
+		.....
+		#...#
+		.#.#.
+		..#..
+		.#.#.
+		#...#`,
+	'y': `
+		.....
+		.....
+		#...#
+		#...#
+		.####
+		....#
+		.###.`,
+	'z': `
+		.....
+		.....
+		#####
+		...#.
+		..#..
+		.#...
+		#####`,
+	'{': `
+		...#.
+		..#..
+		..#..
+		.#...
+		..#..
+		..#..
+		...#.`,
+	'|': `
+		..#..
+		..#..
+		..#..
+		..#..
+		..#..
+		..#..
+		..#..`,
+	'}': `
+		.#...
+		..#..
+		..#..
+		...#.
+		..#..
+		..#..
+		.#...`,
+	'~': `
+		.....
+		.....
+		.#...
+		#.#.#
+		...#.`,
+	'\ufffd': `
+		#####
+		#...#
+		#...#
+		#...#
+		#...#
+		#...#
+		#####`,
+}
+
+// descenders6x8 replaces the lowercase glyphs of glyphs5x7 that have descenders.
+var descenders6x8 = glyphs{
+	'g': `
+		.....
+		.....
+		.####
+		#...#
+		#...#
+		.####
+		....#
+		.###.`,
+	'j': `
+		...#.
+		.....
+		..##.
+		...#.
+		...#.
+		...#.
+		#..#.
+		.##..`,
+	'p': `
+		.....
+		.....
+		####.
+		#...#
+		#...#
+		####.
+		#....
+		#....`,
+	'q': `
+		.....
+		.....
+		.####
+		#...#
+		#...#
+		.####
+		....#
+		....#`,
+	'y': `
+		.....
+		.....
+		#...#
+		#...#
+		#...#
+		.####
+		....#
+		.###.`,
+}
@@ -0,0 +1,1036 @@
+package bitmapfont
+
+import "golang.org/x/image/font/basicfont"
+
+// Face8x16 is a fixed width face with 7×13 pixel glyphs for printable ASCII in cells of 8×16 pixels, with
+// 10 pixel capitals, 3 pixel descenders and two pixel wide vertical strokes.
+var Face8x16 = fixedFace(basicfont.Face{
+	Advance: 8,
+	Width:   7,
+	Height:  16,
+	Ascent:  12,
+	Descent: 4,
+}, 2, glyphs8x16)
+
+var glyphs8x16 = glyphs{
+	' ': ``,
+	'!': `
+		..##...
+		.####..
+		.####..
+		.####..
+		..##...
+		..##...
+		..##...
+		.......
+		..##...
+		..##...`,
+	'"': `
+		##..##.
+		##..##.
+		##..##.
+		.#..#..`,
+	'#': `
+		.......
+		.##.##.
+		.##.##.
+		#######
+		.##.##.
+		.##.##.
+		#######
+		.##.##.
+		.##.##.`,
+	'$': `
+		..##...
+		.#####.
+		##...##
+		##.....
+		.#####.
+		.....##
+		##...##
+		.#####.
+		..##...
+		..##...`,
+	'%': `
+		.......
+		##....#
+		##...##
+		....##.
+		...##..
+		..##...
+		.##....
+		##...##
+		#....##`,
+	'&': `
+		..###..
+		.##.##.
+		.##.##.
+		..###..
+		.###.##
+		##.###.
+		##..##.
+		##..##.
+		##..##.
+		.###.##`,
+	'\'': `
+		..##...
+		..##...
+		..##...
+		.##....`,
+	'(': `
+		....##.
+		...##..
+		..##...
+		..##...
+		..##...
+		..##...
+		..##...
+		..##...
+		...##..
+		....##.`,
+	')': `
+		.##....
+		..##...
+		...##..
+		...##..
+		...##..
+		...##..
+		...##..
+		...##..
+		..##...
+		.##....`,
+	'*': `
+		.......
+		.......
+		.......
+		.##.##.
+		..###..
+		#######
+		..###..
+		.##.##.`,
+	'+': `
+		.......
+		.......
+		.......
+		..##...
+		..##...
+		######.
+		..##...
+		..##...`,
+	',': `
+		.......
+		.......
+		.......
+		.......
+		.......
+		.......
+		.......
+		..##...
+		..##...
+		..##...
+		.##....`,
+	'-': `
+		.......
+		.......
+		.......
+		.......
+		.......
+		######.`,
+	'.': `
+		.......
+		.......
+		.......
+		.......
+		.......
+		.......
+		.......
+		.......
+		..##...
+		..##...`,
+	'/': `
+		.......
+		......#
+		.....##
+		....##.
+		...##..
+		..##...
+		.##....
+		##.....
+		#......`,
+	'0': `
+		..###..
+		.##.##.
+		##...##
+		##..###
+		##.####
+		####.##
+		###..##
+		##...##
+		.##.##.
+		..###..`,
+	'1': `
+		...##..
+		..###..
+		.####..
+		...##..
+		...##..
+		...##..
+		...##..
+		...##..
+		...##..
+		.######`,
+	'2': `
+		.#####.
+		##...##
+		.....##
+		....##.
+		...##..
+		..##...
+		.##....
+		##.....
+		##...##
+		#######`,
+	'3': `
+		.#####.
+		##...##
+		.....##
+		.....##
+		..####.
+		.....##
+		.....##
+		.....##
+		##...##
+		.#####.`,
+	'4': `
+		....##.
+		...###.
+		..####.
+		.##.##.
+		##..##.
+		#######
+		....##.
+		....##.
+		....##.
+		...####`,
+	'5': `
+		#######
+		##.....
+		##.....
+		##.....
+		######.
+		.....##
+		.....##
+		.....##
+		##...##
+		.#####.`,
+	'6': `
+		..###..
+		.##....
+		##.....
+		##.....
+		######.
+		##...##
+		##...##
+		##...##
+		##...##
+		.#####.`,
+	'7': `
+		#######
+		##...##
+		.....##
+		....##.
+		...##..
+		..##...
+		..##...
+		..##...
+		..##...
+		..##...`,
+	'8': `
+		.#####.
+		##...##
+		##...##
+		##...##
+		.#####.
+		##...##
+		##...##
+		##...##
+		##...##
+		.#####.`,
+	'9': `
+		.#####.
+		##...##
+		##...##
+		##...##
+		.######
+		.....##
+		.....##
+		.....##
+		....##.
+		.####..`,
+	':': `
+		.......
+		.......
+		..##...
+		..##...
+		.......
+		.......
+		.......
+		..##...
+		..##...`,
+	';': `
+		.......
+		.......
+		..##...
+		..##...
+		.......
+		.......
+		.......
+		..##...
+		..##...
+		..##...
+		.##....`,
+	'<': `
+		.......
+		.....##
+		....##.
+		...##..
+		..##...
+		.##....
+		..##...
+		...##..
+		....##.
+		.....##`,
+	'=': `
+		.......
+		.......
+		.......
+		.......
+		#######
+		.......
+		#######`,
+	'>': `
+		.......
+		##.....
+		.##....
+		..##...
+		...##..
+		....##.
+		...##..
+		..##...
+		.##....
+		##.....`,
+	'?': `
+		.#####.
+		##...##
+		##...##
+		....##.
+		...##..
+		...##..
+		...##..
+		.......
+		...##..
+		...##..`,
+	'@': `
+		.......
+		.#####.
+		##...##
+		##...##
+		##.####
+		##.####
+		##.####
+		##.###.
+		##.....
+		.#####.`,
+	'A': `
+		...#...
+		..###..
+		.##.##.
+		##...##
+		##...##
+		#######
+		##...##
+		##...##
+		##...##
+		##...##`,
+	'B': `
+		######.
+		.##..##
+		.##..##
+		.##..##
+		.#####.
+		.##..##
+		.##..##
+		.##..##
+		.##..##
+		######.`,
+	'C': `
+		..####.
+		.##..##
+		##....#
+		##.....
+		##.....
+		##.....
+		##.....
+		##....#
+		.##..##
+		..####.`,
+	'D': `
+		#####..
+		.##.##.
+		.##..##
+		.##..##
+		.##..##
+		.##..##
+		.##..##
+		.##..##
+		.##.##.
+		#####..`,
+	'E': `
+		#######
+		.##..##
+		.##...#
+		.##.#..
+		.####..
+		.##.#..
+		.##....
+		.##...#
+		.##..##
+		#######`,
+	'F': `
+		#######
+		.##..##
+		.##...#
+		.##.#..
+		.####..
+		.##.#..
+		.##....
+		.##....
+		.##....
+		####...`,
+	'G': `
+		..####.
+		.##..##
+		##....#
+		##.....
+		##.....
+		##.####
+		##...##
+		##...##
+		.##..##
+		..###.#`,
+	'H': `
+		##...##
+		##...##
+		##...##
+		##...##
+		#######
+		##...##
+		##...##
+		##...##
+		##...##
+		##...##`,
+	'I': `
+		.####..
+		..##...
+		..##...
+		..##...
+		..##...
+		..##...
+		..##...
+		..##...
+		..##...
+		.####..`,
+	'J': `
+		...####
+		....##.
+		....##.
+		....##.
+		....##.
+		....##.
+		##..##.
+		##..##.
+		##..##.
+		.####..`,
+	'K': `
+		###..##
+		.##..##
+		.##.##.
+		.##.##.
+		.####..
+		.####..
+		.##.##.
+		.##..##
+		.##..##
+		###..##`,
+	'L': `
+		####...
+		.##....
+		.##....
+		.##....
+		.##....
+		.##....
+		.##....
+		.##...#
+		.##..##
+		#######`,
+	'M': `
+		##...##
+		###.###
+		#######
+		#######
+		##.#.##
+		##...##
+		##...##
+		##...##
+		##...##
+		##...##`,
+	'N': `
+		##...##
+		###..##
+		####.##
+		#######
+		##.####
+		##..###
+		##...##
+		##...##
+		##...##
+		##...##`,
+	'O': `
+		.#####.
+		##...##
+		##...##
+		##...##
+		##...##
+		##...##
+		##...##
+		##...##
+		##...##
+		.#####.`,
+	'P': `
+		######.
+		.##..##
+		.##..##
+		.##..##
+		.#####.
+		.##....
+		.##....
+		.##....
+		.##....
+		####...`,
+	'Q': `
+		.#####.
+		##...##
+		##...##
+		##...##
+		##...##
+		##...##
+		##...##
+		##.#.##
+		##.####
+		.#####.
+		....##.
+		.....##`,
+	'R': `
+		######.
+		.##..##
+		.##..##
+		.##..##
+		.#####.
+		.##.##.
+		.##..##
+		.##..##
+		.##..##
+		###..##`,
+	'S': `
+		.#####.
+		##...##
+		##...##
+		.##....
+		..###..
+		....##.
+		.....##
+		##...##
+		##...##
+		.#####.`,
+	'T': `
+		######.
+		#.##.#.
+		..##...
+		..##...
+		..##...
+		..##...
+		..##...
+		..##...
+		..##...
+		.####..`,
+	'U': `
+		##...##
+		##...##
+		##...##
+		##...##
+		##...##
+		##...##
+		##...##
+		##...##
+		##...##
+		.#####.`,
+	'V': `
+		##...##
+		##...##
+		##...##
+		##...##
+		##...##
+		##...##
+		##...##
+		.##.##.
+		..###..
+		...#...`,
+	'W': `
+		##...##
+		##...##
+		##...##
+		##...##
+		##.#.##
+		##.#.##
+		##.#.##
+		#######
+		###.###
+		.##.##.`,
+	'X': `
+		##...##
+		##...##
+		.##.##.
+		.#####.
+		..###..
+		..###..
+		.#####.
+		.##.##.
+		##...##
+		##...##`,
+	'Y': `
+		##..##.
+		##..##.
+		##..##.
+		##..##.
+		.####..
+		..##...
+		..##...
+		..##...
+		..##...
+		.####..`,
+	'Z': `
+		#######
+		##...##
+		#....##
+		....##.
+		...##..
+		..##...
+		.##....
+		##....#
+		##...##
+		#######`,
+	'[': `
+		.####..
+		.##....
+		.##....
+		.##....
+		.##....
+		.##....
+		.##....
+		.##....
+		.##....
+		.####..`,
+	'\\': `
+		.......
+		#......
+		##.....
+		.##....
+		..##...
+		...##..
+		....##.
+		.....##
+		......#`,
+	']': `
+		.####..
+		...##..
+		...##..
+		...##..
+		...##..
+		...##..
+		...##..
+		...##..
+		...##..
+		.####..`,
+	'^': `
+		...#...
+		..###..
+		.##.##.
+		##...##`,
+	'_': `
+		.......
+		.......
+		.......
+		.......
+		.......
+		.......
+		.......
+		.......
+		.......
+		.......
+		.......
+		#######`,
+	'`': `
+		..##...
+		..##...
+		...##..`,
+	'a': `
+		.......
+		.......
+		.......
+		.####..
+		....##.
+		.#####.
+		##..##.
+		##..##.
+		##..##.
+		.###.##`,
+	'b': `
+		###....
+		.##....
+		.##....
+		.####..
+		.##.##.
+		.##..##
+		.##..##
+		.##..##
+		.##..##
+		.#####.`,
+	'c': `
+		.......
+		.......
+		.......
+		.#####.
+		##...##
+		##.....
+		##.....
+		##.....
+		##...##
+		.#####.`,
+	'd': `
+		...###.
+		....##.
+		....##.
+		..####.
+		.##.##.
+		##..##.
+		##..##.
+		##..##.
+		##..##.
+		.###.##`,
+	'e': `
+		.......
+		.......
+		.......
+		.#####.
+		##...##
+		#######
+		##.....
+		##.....
+		##...##
+		.#####.`,
+	'f': `
+		..###..
+		.##.##.
+		.##..#.
+		.##....
+		####...
+		.##....
+		.##....
+		.##....
+		.##....
+		####...`,
+	'g': `
+		.......
+		.......
+		.......
+		.###.##
+		##..##.
+		##..##.
+		##..##.
+		##..##.
+		##..##.
+		.#####.
+		....##.
+		##..##.
+		.####..`,
+	'h': `
+		###....
+		.##....
+		.##....
+		.##.##.
+		.###.##
+		.##..##
+		.##..##
+		.##..##
+		.##..##
+		###..##`,
+	'i': `
+		.......
+		..##...
+		..##...
+		.......
+		.###...
+		..##...
+		..##...
+		..##...
+		..##...
+		.####..`,
+	'j': `
+		.......
+		....##.
+		....##.
+		.......
+		...###.
+		....##.
+		....##.
+		....##.
+		....##.
+		....##.
+		##..##.
+		##..##.
+		.####..`,
+	'k': `
+		###....
+		.##....
+		.##....
+		.##..##
+		.##.##.
+		.####..
+		.####..
+		.##.##.
+		.##..##
+		###..##`,
+	'l': `
+		.###...
+		..##...
+		..##...
+		..##...
+		..##...
+		..##...
+		..##...
+		..##...
+		..##...
+		.####..`,
+	'm': `
+		.......
+		.......
+		.......
+		###.##.
+		#######
+		##.#.##
+		##.#.##
+		##.#.##
+		##.#.##
+		##...##`,
+	'n': `
+		.......
+		.......
+		.......
+		##.###.
+		.##..##
+		.##..##
+		.##..##
+		.##..##
+		.##..##
+		.##..##`,
+	'o': `
+		.......
+		.......
+		.......
+		.#####.
+		##...##
+		##...##
+		##...##
+		##...##
+		##...##
+		.#####.`,
+	'p': `
+		.......
+		.......
+		.......
+		##.###.
+		.##..##
+		.##..##
+		.##..##
+		.##..##
+		.##..##
+		.#####.
+		.##....
+		.##....
+		####...`,
+	'q': `
+		.......
+		.......
+		.......
+		.###.##
+		##..##.
+		##..##.
+		##..##.
+		##..##.
+		##..##.
+		.#####.
+		....##.
+		....##.
+		...####`,
+	'r': `
+		.......
+		.......
+		.......
+		##.###.
+		.###.##
+		.##..##
+		.##....
+		.##....
+		.##....
+		####...`,
+	's': `
+		.......
+		.......
+		.......
+		.#####.
+		##...##
+		.##....
+		..###..
+		....##.
+		##...##
+		.#####.`,
+	't': `
+		...#...
+		..##...
+		..##...
+		######.
+		..##...
+		..##...
+		..##...
+		..##...
+		..##.##
+		...###.`,
+	'u': `
+		.......
+		.......
+		.......
+		##..##.
+		##..##.
+		##..##.
+		##..##.
+		##..##.
+		##..##.
+		.###.##`,
+	'v': `
+		.......
+		.......
+		.......
+		##...##
+		##...##
+		##...##
+		##...##
+		.##.##.
+		..###..
+		...#...`,
+	'w': `
+		.......
+		.......
+		.......
+		##...##
+		##...##
+		##.#.##
+		##.#.##
+		##.#.##
+		#######
+		.##.##.`,
+	'x': `
+		.......
+		.......
+		.......
+		##...##
+		.##.##.
+		..###..
+		..###..
+		..###..
+		.##.##.
+		##...##`,
+	'y': `
+		.......
+		.......
+		.......
+		##...##
+		##...##
+		##...##
+		##...##
+		##...##
+		##...##
+		.######
+		.....##
+		....##.
+		#####..`,
+	'z': `
+		.......
+		.......
+		.......
+		#######
+		##..##.
+		...##..
+		..##...
+		.##....
+		##...##
+		#######`,
+	'{': `
+		....###
+		...##..
+		...##..
+		...##..
+		.###...
+		...##..
+		...##..
+		...##..
+		...##..
+		....###`,
+	'|': `
+		..##...
+		..##...
+		..##...
+		..##...
+		..##...
+		..##...
+		..##...
+		..##...
+		..##...
+		..##...
+		..##...
+		..##...`,
+	'}': `
+		###....
+		..##...
+		..##...
+		..##...
+		...###.
+		..##...
+		..##...
+		..##...
+		..##...
+		###....`,
+	'~': `
+		.###.##
+		##.###.`,
+	'\ufffd': `
+		#######
+		#.....#
+		#.....#
+		#.....#
+		#.....#
+		#.....#
+		#.....#
+		#.....#
+		#.....#
+		#######`,
+}
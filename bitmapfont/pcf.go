@@ -0,0 +1,434 @@
+package bitmapfont
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"strconv"
+)
+
+// pcfMagic is the file header of PCF fonts.
+const pcfMagic = "\x01fcp"
+
+// PCF table types.
+const (
+	pcfProperties      = 1 << 0
+	pcfAccelerators    = 1 << 1
+	pcfMetrics         = 1 << 2
+	pcfBitmaps         = 1 << 3
+	pcfBDFEncodings    = 1 << 5
+	pcfBDFAccelerators = 1 << 8
+)
+
+// PCF table format flags.
+const (
+	pcfGlyphPadMask      = 3 << 0 // log2 of the row padding in bytes
+	pcfByteMask          = 1 << 2 // most significant byte first
+	pcfBitMask           = 1 << 3 // most significant bit first
+	pcfScanUnitMask      = 3 << 4 // log2 of the bitmap unit in bytes
+	pcfCompressedMetrics = 1 << 8
+)
+
+// ParsePCF parses a font in the X11 Portable Compiled Format. Glyphs are mapped to runes with the
+// CHARSET_REGISTRY and CHARSET_ENCODING properties of the font.
+func ParsePCF(r io.Reader) (*Face, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return parsePCF(data)
+}
+
+func parsePCF(data []byte) (*Face, error) {
+	p, err := pcfParse(data)
+	if err != nil {
+		return nil, fmt.Errorf("bitmapfont: pcf: %w", err)
+	}
+	return newFace(p)
+}
+
+// pcfFile is a PCF font file and its table of contents.
+type pcfFile struct {
+	data   []byte
+	tables map[uint32]pcfTable
+}
+
+type pcfTable struct {
+	format uint32
+	size   uint32
+	offset uint32
+}
+
+// pcfReader reads the values of a table in its byte order. Reads past the end of the table return zero
+// and set err.
+type pcfReader struct {
+	data   []byte
+	order  binary.ByteOrder
+	format uint32
+	err    error
+}
+
+// table returns a reader for the table of type t, or nil if the font has no such table.
+func (f *pcfFile) table(t uint32) (*pcfReader, error) {
+	table, ok := f.tables[t]
+	if !ok {
+		return nil, nil
+	}
+	if uint64(table.offset)+uint64(table.size) > uint64(len(f.data)) || table.size < 4 {
+		return nil, fmt.Errorf("table %#x out of bounds", t)
+	}
+	r := &pcfReader{data: f.data[table.offset : table.offset+table.size]}
+
+	// The format is repeated in the table, always least significant byte first.
+	r.format = r.u32le()
+	if r.format != table.format {
+		return nil, fmt.Errorf("table %#x has format %#x, expected %#x", t, r.format, table.format)
+	}
+	r.order = binary.LittleEndian
+	if r.format&pcfByteMask != 0 {
+		r.order = binary.BigEndian
+	}
+	return r, nil
+}
+
+func (r *pcfReader) bytes(n int) []byte {
+	if n < 0 || n > len(r.data) {
+		if r.err == nil {
+			r.err = io.ErrUnexpectedEOF
+		}
+		r.data = nil
+		return nil
+	}
+	b := r.data[:n]
+	r.data = r.data[n:]
+	return b
+}
+
+func (r *pcfReader) u32le() uint32 {
+	if b := r.bytes(4); b != nil {
+		return binary.LittleEndian.Uint32(b)
+	}
+	return 0
+}
+
+func (r *pcfReader) u8() uint8 {
+	if b := r.bytes(1); b != nil {
+		return b[0]
+	}
+	return 0
+}
+
+func (r *pcfReader) i16() int {
+	if b := r.bytes(2); b != nil {
+		return int(int16(r.order.Uint16(b)))
+	}
+	return 0
+}
+
+func (r *pcfReader) u16() int {
+	if b := r.bytes(2); b != nil {
+		return int(r.order.Uint16(b))
+	}
+	return 0
+}
+
+func (r *pcfReader) i32() int {
+	if b := r.bytes(4); b != nil {
+		return int(int32(r.order.Uint32(b)))
+	}
+	return 0
+}
+
+// pcfMetric is the metric of a glyph.
+type pcfMetric struct {
+	left, right     int // bearings
+	width           int // advance
+	ascent, descent int
+}
+
+func (r *pcfReader) metric(compressed bool) (m pcfMetric) {
+	if compressed {
+		m.left = int(r.u8()) - 0x80
+		m.right = int(r.u8()) - 0x80
+		m.width = int(r.u8()) - 0x80
+		m.ascent = int(r.u8()) - 0x80
+		m.descent = int(r.u8()) - 0x80
+		return
+	}
+	m.left = r.i16()
+	m.right = r.i16()
+	m.width = r.i16()
+	m.ascent = r.i16()
+	m.descent = r.i16()
+	r.u16() // attributes
+	return
+}
+
+func pcfParse(data []byte) (*parsedFont, error) {
+	if len(data) < 8 || string(data[:4]) != pcfMagic {
+		return nil, errors.New("not a PCF font")
+	}
+	var (
+		count = binary.LittleEndian.Uint32(data[4:])
+		f     = &pcfFile{data: data, tables: make(map[uint32]pcfTable)}
+	)
+	if uint64(len(data)) < 8+16*uint64(count) {
+		return nil, io.ErrUnexpectedEOF
+	}
+	for i := uint32(0); i < count; i++ {
+		b := data[8+16*i:]
+		f.tables[binary.LittleEndian.Uint32(b)] = pcfTable{
+			format: binary.LittleEndian.Uint32(b[4:]),
+			size:   binary.LittleEndian.Uint32(b[8:]),
+			offset: binary.LittleEndian.Uint32(b[12:]),
+		}
+	}
+
+	p := &parsedFont{defaultChar: -1}
+	var err error
+	if p.props, err = f.properties(); err != nil {
+		return nil, err
+	}
+	if err = f.accelerators(p); err != nil {
+		return nil, err
+	}
+	metrics, err := f.metrics()
+	if err != nil {
+		return nil, err
+	}
+	if p.glyphs, err = f.bitmaps(metrics); err != nil {
+		return nil, err
+	}
+	if err = f.encodings(p); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// properties reads the properties, integer values are formatted as decimal numbers.
+func (f *pcfFile) properties() (map[string]string, error) {
+	props := make(map[string]string)
+	r, err := f.table(pcfProperties)
+	if r == nil || err != nil {
+		return props, err
+	}
+
+	type prop struct {
+		name     int
+		isString bool
+		value    int
+	}
+	n := r.i32()
+	if n < 0 || n > len(r.data)/9 {
+		return nil, fmt.Errorf("invalid property count %d", n)
+	}
+	list := make([]prop, n)
+	for i := range list {
+		list[i] = prop{name: r.i32(), isString: r.u8() != 0, value: r.i32()}
+	}
+	if n&3 != 0 {
+		r.bytes(4 - n&3)
+	}
+	pool := r.bytes(r.i32())
+	if r.err != nil {
+		return nil, fmt.Errorf("properties: %w", r.err)
+	}
+
+	str := func(off int) (string, error) {
+		if off < 0 || off >= len(pool) {
+			return "", fmt.Errorf("property string offset %d out of bounds", off)
+		}
+		s := pool[off:]
+		for i, c := range s {
+			if c == 0 {
+				return string(s[:i]), nil
+			}
+		}
+		return string(s), nil
+	}
+	for _, p := range list {
+		name, err := str(p.name)
+		if err != nil {
+			return nil, err
+		}
+		if p.isString {
+			if props[name], err = str(p.value); err != nil {
+				return nil, err
+			}
+		} else {
+			props[name] = strconv.Itoa(p.value)
+		}
+	}
+	return props, nil
+}
+
+// accelerators reads the font ascent and descent.
+func (f *pcfFile) accelerators(p *parsedFont) error {
+	r, err := f.table(pcfBDFAccelerators)
+	if r == nil && err == nil {
+		r, err = f.table(pcfAccelerators)
+	}
+	if err != nil {
+		return err
+	}
+	if r == nil {
+		return errors.New("missing accelerators")
+	}
+	r.bytes(8) // flags
+	p.ascent = r.i32()
+	p.descent = r.i32()
+	if r.err != nil {
+		return fmt.Errorf("accelerators: %w", r.err)
+	}
+	return nil
+}
+
+// metrics reads the glyph metrics.
+func (f *pcfFile) metrics() ([]pcfMetric, error) {
+	r, err := f.table(pcfMetrics)
+	if err != nil {
+		return nil, err
+	}
+	if r == nil {
+		return nil, errors.New("missing metrics")
+	}
+	var (
+		compressed = r.format&pcfCompressedMetrics != 0
+		n          int
+		size       = 12
+	)
+	if compressed {
+		n, size = r.u16(), 5
+	} else {
+		n = r.i32()
+	}
+	if n < 0 || n > len(r.data)/size {
+		return nil, fmt.Errorf("invalid metrics count %d", n)
+	}
+	metrics := make([]pcfMetric, n)
+	for i := range metrics {
+		metrics[i] = r.metric(compressed)
+	}
+	return metrics, nil
+}
+
+// bitmaps reads the glyph bitmaps, and returns the glyphs without codes.
+func (f *pcfFile) bitmaps(metrics []pcfMetric) ([]parsedGlyph, error) {
+	r, err := f.table(pcfBitmaps)
+	if err != nil {
+		return nil, err
+	}
+	if r == nil {
+		return nil, errors.New("missing bitmaps")
+	}
+	n := r.i32()
+	if n != len(metrics) {
+		return nil, fmt.Errorf("%d bitmaps for %d metrics", n, len(metrics))
+	}
+	offsets := make([]int, n)
+	for i := range offsets {
+		offsets[i] = r.i32()
+	}
+	var sizes [4]int
+	for i := range sizes {
+		sizes[i] = r.i32()
+	}
+	var (
+		pad    = 1 << (r.format & pcfGlyphPadMask)
+		unit   = 1 << ((r.format & pcfScanUnitMask) >> 4)
+		msbBit = r.format&pcfBitMask != 0
+		swap   = 0
+		bits   = r.bytes(sizes[r.format&pcfGlyphPadMask])
+	)
+	if r.err != nil {
+		return nil, fmt.Errorf("bitmaps: %w", r.err)
+	}
+	if unit > pad {
+		// Swapped bytes would be outside of the padded rows.
+		return nil, fmt.Errorf("bitmap scan unit %d is larger than the row padding %d", unit, pad)
+	}
+	if msbBit != (r.format&pcfByteMask != 0) {
+		// Bytes are swapped in every unit.
+		swap = unit - 1
+	}
+
+	glyphs := make([]parsedGlyph, n)
+	for i, m := range metrics {
+		var (
+			bounds = image.Rect(m.left, -m.ascent, m.right, m.descent)
+			w, h   = bounds.Dx(), bounds.Dy()
+		)
+		if w < 0 || h < 0 {
+			return nil, fmt.Errorf("glyph %d has invalid bounds %v", i, bounds)
+		}
+		stride := ((w+7)/8 + pad - 1) &^ (pad - 1)
+		if offsets[i] < 0 || offsets[i]+stride*h > len(bits) {
+			return nil, fmt.Errorf("glyph %d bitmap out of bounds", i)
+		}
+
+		pix := make([]uint8, w*h)
+		for y := 0; y < h; y++ {
+			row := bits[offsets[i]+y*stride:]
+			for x := 0; x < w; x++ {
+				var (
+					b   = row[(x/8)^swap]
+					bit = uint8(1) << (x % 8)
+				)
+				if msbBit {
+					bit = 0x80 >> (x % 8)
+				}
+				if b&bit != 0 {
+					pix[y*w+x] = 0xff
+				}
+			}
+		}
+		glyphs[i] = parsedGlyph{bounds: bounds, advance: m.width, pix: pix}
+	}
+	return glyphs, nil
+}
+
+// encodings replaces the glyphs with a glyph for every encoded character, and sets the default character.
+func (f *pcfFile) encodings(p *parsedFont) error {
+	r, err := f.table(pcfBDFEncodings)
+	if err != nil {
+		return err
+	}
+	if r == nil {
+		return errors.New("missing encodings")
+	}
+	var (
+		min2 = r.i16()
+		max2 = r.i16()
+		min1 = r.i16()
+		max1 = r.i16()
+		def  = r.u16()
+	)
+	if min2 < 0 || max2 < min2 || max2 > 0xff || min1 < 0 || max1 < min1 || max1 > 0xff {
+		return fmt.Errorf("invalid encoding range %d-%d, %d-%d", min1, max1, min2, max2)
+	}
+
+	// Glyphs can be used by several characters, the bitmaps are shared.
+	glyphs := make([]parsedGlyph, 0, len(p.glyphs))
+	for b1 := min1; b1 <= max1; b1++ {
+		for b2 := min2; b2 <= max2; b2++ {
+			i := r.u16()
+			if r.err != nil {
+				return fmt.Errorf("encodings: %w", r.err)
+			}
+			if i == 0xffff {
+				continue
+			}
+			if i >= len(p.glyphs) {
+				return fmt.Errorf("glyph index %d out of range", i)
+			}
+			g := p.glyphs[i]
+			g.code = b1<<8 | b2
+			glyphs = append(glyphs, g)
+		}
+	}
+	p.glyphs = glyphs
+	p.defaultChar = def
+	return nil
+}
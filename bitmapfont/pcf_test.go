@@ -0,0 +1,308 @@
+package bitmapfont
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// pcfWriter writes the values of a PCF table in its byte order.
+type pcfWriter struct {
+	bytes.Buffer
+	order binary.AppendByteOrder
+}
+
+func newPCFWriter(format uint32) *pcfWriter {
+	w := &pcfWriter{order: binary.LittleEndian}
+	if format&pcfByteMask != 0 {
+		w.order = binary.BigEndian
+	}
+	w.Write(binary.LittleEndian.AppendUint32(nil, format))
+	return w
+}
+
+func (w *pcfWriter) u8(v int)  { w.WriteByte(byte(v)) }
+func (w *pcfWriter) i16(v int) { w.Write(w.order.AppendUint16(nil, uint16(v))) }
+func (w *pcfWriter) i32(v int) { w.Write(w.order.AppendUint32(nil, uint32(v))) }
+
+// encodePCF encodes p as a PCF font, with the byte order, bit order, padding and scan unit of format, and
+// optionally compressed metrics.
+func encodePCF(p *parsedFont, format uint32, compressed bool) []byte {
+	order := format & pcfByteMask
+
+	props := newPCFWriter(order)
+	var (
+		names = slices.Sorted(maps.Keys(p.props))
+		pool  bytes.Buffer
+	)
+	str := func(s string) int {
+		off := pool.Len()
+		pool.WriteString(s)
+		pool.WriteByte(0)
+		return off
+	}
+	props.i32(len(names))
+	for _, name := range names {
+		props.i32(str(name))
+		if v, err := strconv.Atoi(p.props[name]); err == nil {
+			props.u8(0)
+			props.i32(v)
+		} else {
+			props.u8(1)
+			props.i32(str(p.props[name]))
+		}
+	}
+	if len(names)&3 != 0 {
+		props.Write(make([]byte, 4-len(names)&3))
+	}
+	props.i32(pool.Len())
+	props.Write(pool.Bytes())
+
+	accel := newPCFWriter(order)
+	accel.Write(make([]byte, 8))
+	accel.i32(p.ascent)
+	accel.i32(p.descent)
+	accel.i32(0)                  // max overlap
+	accel.Write(make([]byte, 24)) // min and max bounds
+
+	metricsFormat := order
+	if compressed {
+		metricsFormat |= pcfCompressedMetrics
+	}
+	metrics := newPCFWriter(metricsFormat)
+	if compressed {
+		metrics.i16(len(p.glyphs))
+	} else {
+		metrics.i32(len(p.glyphs))
+	}
+	for _, g := range p.glyphs {
+		for _, v := range []int{g.bounds.Min.X, g.bounds.Max.X, g.advance, -g.bounds.Min.Y, g.bounds.Max.Y} {
+			if compressed {
+				metrics.u8(v + 0x80)
+			} else {
+				metrics.i16(v)
+			}
+		}
+		if !compressed {
+			metrics.i16(0)
+		}
+	}
+
+	var (
+		bitmaps = newPCFWriter(format)
+		pad     = 1 << (format & pcfGlyphPadMask)
+		unit    = 1 << ((format & pcfScanUnitMask) >> 4)
+		msbBit  = format&pcfBitMask != 0
+		swap    = 0
+		data    []byte
+		offsets []int
+		sizes   [4]int
+	)
+	if msbBit != (format&pcfByteMask != 0) {
+		swap = unit - 1
+	}
+	for _, g := range p.glyphs {
+		w, h := g.bounds.Dx(), g.bounds.Dy()
+		for i := range sizes {
+			sizes[i] += ((w+7)/8 + 1<<i - 1) &^ (1<<i - 1) * h
+		}
+		stride := ((w+7)/8 + pad - 1) &^ (pad - 1)
+		offsets = append(offsets, len(data))
+		glyph := make([]byte, stride*h)
+		for y := 0; y < h; y++ {
+			for x := 0; x < w; x++ {
+				if g.pix[y*w+x] == 0 {
+					continue
+				}
+				bit := byte(1) << (x % 8)
+				if msbBit {
+					bit = 0x80 >> (x % 8)
+				}
+				glyph[y*stride+(x/8)^swap] |= bit
+			}
+		}
+		data = append(data, glyph...)
+	}
+	bitmaps.i32(len(p.glyphs))
+	for _, off := range offsets {
+		bitmaps.i32(off)
+	}
+	for _, size := range sizes {
+		bitmaps.i32(size)
+	}
+	bitmaps.Write(data)
+
+	var (
+		index = make(map[int]int)
+		max1  int
+	)
+	for i, g := range p.glyphs {
+		index[g.code] = i
+		max1 = max(max1, g.code>>8)
+	}
+	encodings := newPCFWriter(order)
+	for _, v := range []int{0, 0xff, 0, max1, p.defaultChar} {
+		encodings.i16(v)
+	}
+	for code := 0; code < (max1+1)<<8; code++ {
+		if i, ok := index[code]; ok {
+			encodings.i16(i)
+		} else {
+			encodings.i16(0xffff)
+		}
+	}
+
+	tables := []struct {
+		Type uint32
+		W    *pcfWriter
+	}{
+		{pcfProperties, props},
+		{pcfBDFAccelerators, accel},
+		{pcfMetrics, metrics},
+		{pcfBitmaps, bitmaps},
+		{pcfBDFEncodings, encodings},
+	}
+	var out bytes.Buffer
+	out.WriteString(pcfMagic)
+	out.Write(binary.LittleEndian.AppendUint32(nil, uint32(len(tables))))
+	offset := 8 + 16*len(tables)
+	for _, t := range tables {
+		format := binary.LittleEndian.Uint32(t.W.Bytes())
+		for _, v := range []uint32{t.Type, format, uint32(t.W.Len()), uint32(offset)} {
+			out.Write(binary.LittleEndian.AppendUint32(nil, v))
+		}
+		offset += (t.W.Len() + 3) &^ 3
+	}
+	for _, t := range tables {
+		out.Write(t.W.Bytes())
+		out.Write(make([]byte, (4-t.W.Len()&3)&3))
+	}
+	return out.Bytes()
+}
+
+// parseTestBDF returns testdata/test.bdf before its glyphs are mapped to runes.
+func parseTestBDF(t *testing.T) *parsedFont {
+	t.Helper()
+	f, err := os.Open("testdata/test.bdf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	p := &bdfParser{
+		s:    bufio.NewScanner(f),
+		font: parsedFont{props: make(map[string]string), defaultChar: -1},
+	}
+	if err := p.parse(); err != nil {
+		t.Fatal(err)
+	}
+	return &p.font
+}
+
+func TestParsePCF(t *testing.T) {
+	font := parseTestBDF(t)
+	for _, byteOrder := range []uint32{0, pcfByteMask} {
+		for _, bitOrder := range []uint32{0, pcfBitMask} {
+			for pad := uint32(0); pad < 4; pad++ {
+				for unit := uint32(0); unit <= min(pad, 2); unit++ {
+					for _, compressed := range []bool{false, true} {
+						format := byteOrder | bitOrder | pad | unit<<4
+						t.Run(fmt.Sprintf("format %#x compressed %t", format, compressed), func(t *testing.T) {
+							face, err := ParsePCF(bytes.NewReader(encodePCF(font, format, compressed)))
+							if err != nil {
+								t.Fatal(err)
+							}
+							checkFace(t, face)
+						})
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestParsePCFErrors(t *testing.T) {
+	data := encodePCF(parseTestBDF(t), pcfByteMask|pcfBitMask|2, true)
+
+	// Truncated fonts return an error, without panicking. The last table may be followed by padding.
+	var (
+		count = binary.LittleEndian.Uint32(data[4:])
+		last  = data[8+16*(count-1):]
+		end   = int(binary.LittleEndian.Uint32(last[12:]) + binary.LittleEndian.Uint32(last[8:]))
+	)
+	for n := 0; n < end; n++ {
+		if _, err := ParsePCF(bytes.NewReader(data[:n])); err == nil {
+			t.Fatalf("%d bytes: expected an error for a truncated font", n)
+		} else if !strings.HasPrefix(err.Error(), "bitmapfont: pcf: ") {
+			t.Fatalf("%d bytes: unexpected error %v", n, err)
+		}
+	}
+
+	// A table format that doesn't match the table of contents.
+	bad := slices.Clone(data)
+	bad[8+4]++
+	if _, err := ParsePCF(bytes.NewReader(bad)); err == nil || !strings.Contains(err.Error(), "format") {
+		t.Errorf("expected a format error, got %v", err)
+	}
+
+	// A scan unit of 4 bytes with rows padded to 1 byte, and a bit order that swaps bytes.
+	bad = encodePCF(parseTestBDF(t), pcfByteMask, false)
+	var (
+		entry  = bad[8+16*3:]
+		offset = binary.LittleEndian.Uint32(entry[12:])
+		format = uint32(pcfByteMask | 2<<4)
+	)
+	if binary.LittleEndian.Uint32(entry) != pcfBitmaps {
+		t.Fatal("expected the fourth table to be the bitmaps")
+	}
+	binary.LittleEndian.PutUint32(entry[4:], format)
+	binary.LittleEndian.PutUint32(bad[offset:], format)
+	if _, err := ParsePCF(bytes.NewReader(bad)); err == nil || !strings.Contains(err.Error(), "scan unit") {
+		t.Errorf("expected a scan unit error, got %v", err)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	var (
+		dir  = t.TempDir()
+		font = parseTestBDF(t)
+		pcf  = encodePCF(font, pcfByteMask|pcfBitMask|2, false)
+		gz   bytes.Buffer
+	)
+	z := gzip.NewWriter(&gz)
+	z.Write(pcf)
+	z.Close()
+	bdf, err := os.ReadFile("testdata/test.bdf")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, data := range map[string][]byte{
+		"test.bdf":    bdf,
+		"test.pcf":    pcf,
+		"test.pcf.gz": gz.Bytes(),
+	} {
+		name = filepath.Join(dir, name)
+		if err := os.WriteFile(name, data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		face, err := Load(name)
+		if err != nil {
+			t.Errorf("%s: %v", filepath.Base(name), err)
+			continue
+		}
+		checkFace(t, face)
+	}
+
+	if _, err := Load(filepath.Join(dir, "missing.bdf")); !os.IsNotExist(err) {
+		t.Errorf("expected a not exist error, got %v", err)
+	}
+}
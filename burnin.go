@@ -0,0 +1,301 @@
+package display
+
+import (
+	"context"
+	"errors"
+	"hash/fnv"
+	"image"
+	"image/color"
+	"image/draw"
+	"slices"
+	"time"
+)
+
+// Burn-in protection defaults.
+const (
+	DefaultBurnInRadius        = 2
+	DefaultBurnInShiftInterval = time.Minute
+	DefaultBurnInDimLevel      = 0x40
+)
+
+// burnInTileSize is the size of the square tiles in which static content is detected.
+const burnInTileSize = 8
+
+// BurnInConfig is the burn-in protection configuration.
+type BurnInConfig struct {
+	// Radius is the maximum shift in pixels from the original position, zero disables shifting.
+	Radius int
+
+	// ShiftInterval is the time between shifts by one pixel, zero selects DefaultBurnInShiftInterval.
+	ShiftInterval time.Duration
+
+	// InvertInterval is the time after which the display toggles between normal and inverted pixels, zero
+	// disables inversion.
+	InvertInterval time.Duration
+
+	// DimAfter is the time after which regions that didn't change are dimmed, zero disables dimming.
+	DimAfter time.Duration
+
+	// DimLevel is the brightness of dimmed regions, from 0 (off) to 0xFF (not dimmed); zero selects
+	// DefaultBurnInDimLevel. Monochrome displays dim by switching off every other pixel.
+	DimLevel uint8
+}
+
+// ProtectBurnIn returns a Display that mitigates OLED burn-in of static content.
+//
+// The picture orbits around its original position, moving by one pixel every shift interval. Offsets are set
+// in hardware on displays that are an [Offsetter], without redrawing; other displays, and horizontal shifts
+// on controllers that can only shift vertically, are shifted in software while refreshing, wrapping around
+// like the hardware offset. Optionally the whole display is inverted on a schedule, using [Inverter] if
+// available, and regions that didn't change for a while are dimmed in software.
+//
+// The schedule is evaluated on every refresh, so applications that don't redraw should call Refresh at
+// least once per shift interval. A nil config shifts by up to DefaultBurnInRadius pixels.
+func ProtectBurnIn(d Display, config *BurnInConfig) Display {
+	if config == nil {
+		config = &BurnInConfig{Radius: DefaultBurnInRadius}
+	}
+	p := &burnInProtector{
+		Display: d,
+		config:  *config,
+		now:     time.Now,
+		orbit:   burnInOrbit(config.Radius),
+	}
+	if p.config.ShiftInterval <= 0 {
+		p.config.ShiftInterval = DefaultBurnInShiftInterval
+	}
+	if p.config.DimLevel == 0 {
+		p.config.DimLevel = DefaultBurnInDimLevel
+	}
+	// Panel columns are display rows if the display is rotated in software by 90° or 270°.
+	p.swapped = d.Bounds().Dx() != DisplayInfo(d).Width
+	return p
+}
+
+type burnInProtector struct {
+	Display
+	config  BurnInConfig
+	now     func() time.Time
+	orbit   []image.Point
+	swapped bool
+
+	start      time.Time
+	offset     image.Point // hardware offset
+	vertical   bool        // hardware only shifts vertically
+	inverted   bool        // hardware inversion
+	tiles      []uint64    // tile content hashes
+	tileChange []time.Time // time of the last tile change
+}
+
+// burnInState is the protection applied to a frame.
+type burnInState struct {
+	shift  image.Point // software shift, in display coordinates
+	invert bool        // software inversion
+	dim    []bool      // dimmed tiles
+}
+
+func (p *burnInProtector) Refresh() error {
+	return p.refresh(p.Bounds(), true)
+}
+
+// RefreshRect redraws the part of the display inside r, the whole display is redrawn while the picture is
+// transformed in software.
+func (p *burnInProtector) RefreshRect(r image.Rectangle) error {
+	return p.refresh(r, false)
+}
+
+func (p *burnInProtector) refresh(r image.Rectangle, all bool) (err error) {
+	var (
+		b     = p.Bounds()
+		frame *image.RGBA
+	)
+	if p.config.DimAfter > 0 {
+		frame = image.NewRGBA(b)
+		draw.Draw(frame, b, p.Display, b.Min, draw.Src)
+	}
+
+	state, err := p.update(frame)
+	if err != nil {
+		return
+	}
+	if state.shift == (image.Point{}) && !state.invert && !slices.Contains(state.dim, true) {
+		if all {
+			return p.Display.Refresh()
+		}
+		return RefreshRect(p.Display, r)
+	}
+
+	if frame == nil {
+		frame = image.NewRGBA(b)
+		draw.Draw(frame, b, p.Display, b.Min, draw.Src)
+	}
+	defer saveFrame(p.Display)()
+	p.transform(frame, state)
+	return p.Display.Refresh()
+}
+
+// update applies the hardware protection for the current time, and returns the protection to apply in
+// software. Static regions are detected in frame, if not nil.
+func (p *burnInProtector) update(frame *image.RGBA) (state burnInState, err error) {
+	now := p.now()
+	if p.start.IsZero() {
+		p.start = now
+	}
+	elapsed := now.Sub(p.start)
+
+	// Orbit back and forth, starting from the origin in the middle of the orbit. An orbit of a single point
+	// doesn't shift.
+	var shift image.Point
+	if n := len(p.orbit); n > 1 {
+		step := (int(elapsed/p.config.ShiftInterval) + n/2) % (2*n - 2)
+		if step >= n {
+			step = 2*n - 2 - step
+		}
+		shift = p.orbit[step]
+	}
+
+	if o, ok := As[Offsetter](p.Display); ok {
+		want := shift
+		if p.vertical {
+			want.X = 0
+		}
+		if want != p.offset {
+			if err = o.SetOffset(want.X, want.Y); errors.Is(err, errors.ErrUnsupported) {
+				p.vertical, want.X = true, 0
+				err = o.SetOffset(want.X, want.Y)
+			}
+			if err != nil {
+				return
+			}
+			p.offset = want
+		}
+		// Shift the remainder in software.
+		shift = image.Pt(shift.X-want.X, 0)
+		if p.swapped {
+			shift = image.Pt(0, shift.X)
+		}
+	}
+	state.shift = shift
+
+	if p.config.InvertInterval > 0 {
+		invert := (elapsed/p.config.InvertInterval)%2 == 1
+		if i, ok := As[Inverter](p.Display); ok {
+			if invert != p.inverted {
+				if err = i.SetInvert(invert); err != nil {
+					return
+				}
+				p.inverted = invert
+			}
+		} else {
+			state.invert = invert
+		}
+	}
+
+	if frame != nil {
+		state.dim = p.static(frame, now)
+	}
+	return
+}
+
+// static returns which tiles of frame didn't change for the DimAfter duration.
+func (p *burnInProtector) static(frame *image.RGBA, now time.Time) []bool {
+	var (
+		b      = frame.Rect
+		cols   = (b.Dx() + burnInTileSize - 1) / burnInTileSize
+		rows   = (b.Dy() + burnInTileSize - 1) / burnInTileSize
+		static = make([]bool, cols*rows)
+		h      = fnv.New64a()
+	)
+	if len(p.tiles) != len(static) {
+		p.tiles = make([]uint64, len(static))
+		p.tileChange = make([]time.Time, len(static))
+	}
+	for i := range static {
+		var (
+			x0   = (i % cols) * burnInTileSize
+			y0   = (i / cols) * burnInTileSize
+			tile = image.Rect(x0, y0, x0+burnInTileSize, y0+burnInTileSize).Add(b.Min).Intersect(b)
+		)
+		h.Reset()
+		for y := tile.Min.Y; y < tile.Max.Y; y++ {
+			off := frame.PixOffset(tile.Min.X, y)
+			h.Write(frame.Pix[off : off+tile.Dx()*4])
+		}
+		if hash := h.Sum64(); hash != p.tiles[i] || p.tileChange[i].IsZero() {
+			p.tiles[i], p.tileChange[i] = hash, now
+		}
+		static[i] = now.Sub(p.tileChange[i]) >= p.config.DimAfter
+	}
+	return static
+}
+
+// transform draws frame to the display, with the software protection applied.
+func (p *burnInProtector) transform(frame *image.RGBA, state burnInState) {
+	var (
+		b    = frame.Rect
+		w, h = b.Dx(), b.Dy()
+		cols = (w + burnInTileSize - 1) / burnInTileSize
+		mono = Depth(p.ColorModel()) == 1
+	)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			var (
+				sx = ((x-state.shift.X)%w + w) % w
+				sy = ((y-state.shift.Y)%h + h) % h
+				c  = frame.RGBAAt(b.Min.X+sx, b.Min.Y+sy)
+			)
+			if state.dim != nil && state.dim[(sy/burnInTileSize)*cols+sx/burnInTileSize] {
+				if mono {
+					if (x+y)&1 == 1 {
+						c = color.RGBA{A: 0xFF}
+					}
+				} else {
+					level := uint16(p.config.DimLevel)
+					c.R = uint8(uint16(c.R) * level / 0xFF)
+					c.G = uint8(uint16(c.G) * level / 0xFF)
+					c.B = uint8(uint16(c.B) * level / 0xFF)
+				}
+			}
+			if state.invert {
+				c.R, c.G, c.B = 0xFF-c.R, 0xFF-c.G, 0xFF-c.B
+			}
+			p.Display.Set(b.Min.X+x, b.Min.Y+y, c)
+		}
+	}
+}
+
+// burnInOrbit returns the offsets within radius of the origin, in serpentine order so that consecutive
+// offsets are one pixel apart.
+func burnInOrbit(radius int) []image.Point {
+	radius = max(radius, 0)
+	orbit := make([]image.Point, 0, (2*radius+1)*(2*radius+1))
+	for y := -radius; y <= radius; y++ {
+		for i := 0; i <= 2*radius; i++ {
+			x := i - radius
+			if (y+radius)%2 == 1 {
+				x = radius - i
+			}
+			orbit = append(orbit, image.Pt(x, y))
+		}
+	}
+	return orbit
+}
+
+// Unwrap returns the protected display.
+func (p *burnInProtector) Unwrap() Display {
+	return p.Display
+}
+
+func (p *burnInProtector) bindContext(ctx context.Context) func() {
+	if b, ok := p.Display.(contextBinder); ok {
+		return b.bindContext(ctx)
+	}
+	return func() {}
+}
+
+// Interface checks
+var (
+	_ WindowRefresher = (*burnInProtector)(nil)
+	_ contextBinder   = (*burnInProtector)(nil)
+	_ Wrapper         = (*burnInProtector)(nil)
+)
@@ -0,0 +1,196 @@
+package display
+
+import (
+	"image"
+	"testing"
+	"time"
+
+	"github.com/BeatGlow/display/conntest"
+	"github.com/BeatGlow/display/pixel"
+)
+
+// frameDisplay records a copy of the frame buffer on every refresh.
+type frameDisplay struct {
+	*testDisplay
+	frames []*pixel.Gray4Image
+}
+
+func (d *frameDisplay) Refresh() error {
+	i := *d.Image.(*pixel.Gray4Image)
+	i.Pix = append([]byte(nil), i.Pix...)
+	d.frames = append(d.frames, &i)
+	return d.testDisplay.Refresh()
+}
+
+// testClock is a manual clock for burn-in protection tests.
+type testClock struct {
+	t time.Time
+}
+
+func (c *testClock) now() time.Time { return c.t }
+
+func protectBurnIn(d Display, config *BurnInConfig) (*burnInProtector, *testClock) {
+	var (
+		p     = ProtectBurnIn(d, config).(*burnInProtector)
+		clock = &testClock{t: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	)
+	p.now = clock.now
+	return p, clock
+}
+
+func TestBurnInOrbit(t *testing.T) {
+	for radius := 0; radius <= 3; radius++ {
+		orbit := burnInOrbit(radius)
+		if n := (2*radius + 1) * (2*radius + 1); len(orbit) != n {
+			t.Fatalf("radius %d: expected %d offsets, got %d", radius, n, len(orbit))
+		}
+		if o := orbit[len(orbit)/2]; o != (image.Point{}) {
+			t.Errorf("radius %d: expected origin in the middle of the orbit, got %s", radius, o)
+		}
+		for i := 1; i < len(orbit); i++ {
+			if d := orbit[i].Sub(orbit[i-1]); abs(d.X)+abs(d.Y) != 1 {
+				t.Errorf("radius %d: expected steps of one pixel, got %s to %s", radius, orbit[i-1], orbit[i])
+			}
+		}
+	}
+}
+
+func TestProtectBurnInSoftware(t *testing.T) {
+	var (
+		white = pixel.Gray4{Y: 15}
+		d     = &frameDisplay{testDisplay: &testDisplay{baseDisplay: baseDisplay{Image: pixel.NewGray4Image(32, 16)}}}
+	)
+	p, clock := protectBurnIn(d, &BurnInConfig{Radius: 1, ShiftInterval: time.Second})
+	p.Set(5, 5, white)
+
+	for i, want := range []image.Point{
+		{5, 5}, // (0, 0)
+		{4, 5}, // (-1, 0)
+		{4, 6}, // (-1, 1)
+		{5, 6}, // (0, 1)
+		{6, 6}, // (1, 1)
+		{5, 6}, // (0, 1), back along the orbit
+	} {
+		if err := p.Refresh(); err != nil {
+			t.Fatal(err)
+		}
+		if frame := d.frames[len(d.frames)-1]; frame.At(want.X, want.Y) != white {
+			t.Errorf("step %d: expected pixel to be shifted to %s", i, want)
+		}
+		if p.At(5, 5) != white || want != image.Pt(5, 5) && p.At(want.X, want.Y) == white {
+			t.Errorf("step %d: expected the frame buffer to be restored", i)
+		}
+		clock.t = clock.t.Add(time.Second)
+	}
+}
+
+func TestProtectBurnInNoShift(t *testing.T) {
+	var (
+		white = pixel.Gray4{Y: 15}
+		d     = &frameDisplay{testDisplay: &testDisplay{baseDisplay: baseDisplay{Image: pixel.NewGray4Image(32, 16)}}}
+	)
+	p, clock := protectBurnIn(d, &BurnInConfig{Radius: 0, ShiftInterval: time.Second})
+	p.Set(5, 5, white)
+
+	for i := 0; i < 5; i++ {
+		if err := p.Refresh(); err != nil {
+			t.Fatal(err)
+		}
+		if frame := d.frames[len(d.frames)-1]; frame.At(5, 5) != white {
+			t.Errorf("step %d: expected the pixel not to be shifted", i)
+		}
+		clock.t = clock.t.Add(time.Second)
+	}
+}
+
+func TestProtectBurnInHardware(t *testing.T) {
+	t.Run("driver", func(t *testing.T) { testProtectBurnInHardware(t, func(d Display) Display { return d }) })
+	t.Run("damage tracker", func(t *testing.T) { testProtectBurnInHardware(t, TrackDamage) })
+}
+
+func testProtectBurnInHardware(t *testing.T, wrap func(Display) Display) {
+	c := conntest.NewRecorder()
+	d, err := Open("ssd1306", c, &Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, clock := protectBurnIn(wrap(d), &BurnInConfig{Radius: 1, ShiftInterval: time.Second, InvertInterval: 2 * time.Second})
+
+	for i, want := range []struct {
+		Offset   []byte
+		Inverted bool
+	}{
+		{nil, false},          // (0, 0)
+		{[]byte{0x00}, false}, // (-1, 0), horizontal offsets are not supported
+		{[]byte{0x01}, true},  // (-1, 1)
+		{nil, true},           // (0, 1), horizontal shift in software
+		{nil, false},          // (1, 1)
+		{nil, false},          // (0, 1), back along the orbit
+		{nil, true},           // (-1, 1)
+		{[]byte{0x00}, true},  // (-1, 0)
+	} {
+		c.Clear()
+		if err = p.Refresh(); err != nil {
+			t.Fatal(err)
+		}
+
+		var (
+			offset []byte
+			invert []byte
+		)
+		for _, call := range c.Filter(conntest.OpCommand) {
+			switch call.Command {
+			case ssd1xxxSetDisplayOffset:
+				offset = call.Data
+			case ssd1xxxSetInvertDisplay, ssd1xxxSetNormalDisplay:
+				invert = append(invert, call.Command)
+			}
+		}
+		if string(offset) != string(want.Offset) {
+			t.Errorf("step %d: expected display offset %v, got %v", i, want.Offset, offset)
+		}
+		if p.inverted != want.Inverted {
+			t.Errorf("step %d: expected inverted %t", i, want.Inverted)
+		}
+		if len(invert) > 1 {
+			t.Errorf("step %d: expected inversion to be set once, got %d commands", i, len(invert))
+		}
+		clock.t = clock.t.Add(time.Second)
+	}
+}
+
+func TestProtectBurnInDim(t *testing.T) {
+	var (
+		white = pixel.Gray4{Y: 15}
+		d     = &frameDisplay{testDisplay: &testDisplay{baseDisplay: baseDisplay{Image: pixel.NewGray4Image(32, 16)}}}
+	)
+	p, clock := protectBurnIn(d, &BurnInConfig{DimAfter: 10 * time.Second})
+	p.Fill(white)
+
+	if err := p.Refresh(); err != nil {
+		t.Fatal(err)
+	}
+	clock.t = clock.t.Add(5 * time.Second)
+	p.Set(8, 0, pixel.Gray4{Y: 14})
+	if err := p.Refresh(); err != nil {
+		t.Fatal(err)
+	}
+	if frame := d.frames[len(d.frames)-1]; frame.At(0, 0) != white {
+		t.Error("expected no dimming before DimAfter")
+	}
+
+	clock.t = clock.t.Add(5 * time.Second)
+	if err := p.Refresh(); err != nil {
+		t.Fatal(err)
+	}
+	frame := d.frames[len(d.frames)-1]
+	if c := frame.At(0, 0).(pixel.Gray4); c.Y >= white.Y || c.Y == 0 {
+		t.Errorf("expected static tile to be dimmed, got %+v", c)
+	}
+	if c := frame.At(8, 0).(pixel.Gray4); c.Y != 14 {
+		t.Errorf("expected changed tile not to be dimmed, got %+v", c)
+	}
+	if p.At(0, 0) != white {
+		t.Error("expected the frame buffer to be restored")
+	}
+}
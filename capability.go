@@ -0,0 +1,329 @@
+package display
+
+import (
+	"errors"
+	"fmt"
+	"image/color"
+	"strings"
+
+	"github.com/BeatGlow/display/pixel"
+)
+
+// Inverter is a Display that can invert all pixels in the controller, without redrawing.
+type Inverter interface {
+	Display
+
+	// SetInvert toggles inverted pixels on or off.
+	SetInvert(invert bool) error
+}
+
+// Scroller is a Display that can scroll vertically in hardware.
+type Scroller interface {
+	Display
+
+	// Scroll sets the controller RAM row shown on the first row of the panel, in native panel rows
+	// regardless of the rotation. The RAM wraps around, so rows scrolled out at the top reappear at the
+	// bottom. Scroll(0) restores the default.
+	Scroll(line int) error
+}
+
+// GrayscaleTabler is a Display with a programmable grayscale table.
+type GrayscaleTabler interface {
+	Display
+
+	// SetGrayscaleTable sets the pulse widths for gray levels 1 to 15, level 0 is always off. A nil table
+	// restores the default linear table.
+	SetGrayscaleTable(table []uint8) error
+}
+
+// Sleeper is a Display with a low power sleep mode. Unlike Show, sleeping also stops the controller's
+// internal oscillator and booster.
+type Sleeper interface {
+	Display
+
+	// SetSleep toggles the sleep mode on or off.
+	SetSleep(sleep bool) error
+}
+
+// ScrollDirection is the direction of continuous hardware scrolling.
+type ScrollDirection uint8
+
+// Supported scroll directions.
+const (
+	ScrollRight         ScrollDirection = iota // Scroll horizontally to the right
+	ScrollLeft                                 // Scroll horizontally to the left
+	ScrollDiagonalRight                        // Scroll vertically and to the right
+	ScrollDiagonalLeft                         // Scroll vertically and to the left
+)
+
+func (d ScrollDirection) String() string {
+	switch d {
+	case ScrollRight:
+		return "right"
+	case ScrollLeft:
+		return "left"
+	case ScrollDiagonalRight:
+		return "diagonal right"
+	case ScrollDiagonalLeft:
+		return "diagonal left"
+	default:
+		return fmt.Sprintf("direction(%d)", d)
+	}
+}
+
+// HardwareScroll configures continuous hardware scrolling. Pages and rows are in native panel
+// orientation, regardless of the rotation.
+type HardwareScroll struct {
+	// Direction of scrolling.
+	Direction ScrollDirection
+
+	// StartPage is the first page (of 8 rows) that scrolls horizontally.
+	StartPage int
+
+	// EndPage is the last page that scrolls horizontally.
+	EndPage int
+
+	// Interval is the number of frames between scroll steps. Controllers round it to the nearest
+	// supported interval.
+	Interval int
+
+	// VerticalOffset is the number of rows scrolled per step, for diagonal scrolling.
+	VerticalOffset int
+
+	// Top is the first row of the vertically scrolling area, for diagonal scrolling.
+	Top int
+
+	// Rows is the number of rows in the vertically scrolling area, zero selects all rows below Top.
+	Rows int
+}
+
+// HardwareScroller is a Display that scrolls continuously in hardware, without any bus traffic.
+type HardwareScroller interface {
+	Display
+
+	// StartScroll starts continuous scrolling of the RAM contents. Any scrolling in progress is stopped
+	// first.
+	StartScroll(HardwareScroll) error
+
+	// StopScroll stops scrolling and redraws the display, because the RAM contents are scrambled by
+	// scrolling.
+	StopScroll() error
+}
+
+// FadeMode is a hardware fade effect.
+type FadeMode uint8
+
+// Supported fade modes.
+const (
+	FadeOff FadeMode = iota // Disable fading
+	FadeOut                 // Fade out once, until the panel is off
+	Blink                   // Fade out and in continuously
+)
+
+func (m FadeMode) String() string {
+	switch m {
+	case FadeOff:
+		return "off"
+	case FadeOut:
+		return "fade out"
+	case Blink:
+		return "blink"
+	default:
+		return fmt.Sprintf("fade(%d)", m)
+	}
+}
+
+// Fader is a Display that fades out or blinks in hardware, by stepping the contrast.
+type Fader interface {
+	Display
+
+	// SetFade sets the fade mode, with the number of frames per contrast step. Controllers round the
+	// interval to the nearest supported interval.
+	SetFade(mode FadeMode, interval int) error
+}
+
+// Zoomer is a Display that can zoom in vertically in hardware, showing the top half of the RAM at
+// twice the height.
+type Zoomer interface {
+	Display
+
+	// SetZoom toggles zooming on or off.
+	SetZoom(zoom bool) error
+}
+
+// Offsetter is a Display that can shift the picture on the panel in hardware, without redrawing.
+type Offsetter interface {
+	Display
+
+	// SetOffset shifts the picture by x columns and y rows, in native panel orientation regardless of the
+	// rotation. The RAM wraps around. Controllers that only shift vertically return an error wrapping
+	// [errors.ErrUnsupported] if x is not zero, without changing the offset. SetOffset(0, 0) restores the
+	// default.
+	SetOffset(x, y int) error
+}
+
+// Describer is a Display that describes its controller.
+type Describer interface {
+	Display
+
+	// Info describes the controller and its capabilities.
+	Info() Info
+}
+
+// Capability is a set of optional display features.
+type Capability uint16
+
+// Supported capabilities, each corresponds to an optional interface.
+const (
+	CanInvert            Capability = 1 << iota // Inverter
+	CanScroll                                   // Scroller
+	CanRefreshRect                              // WindowRefresher
+	CanSetGrayscaleTable                        // GrayscaleTabler
+	CanSleep                                    // Sleeper
+	CanHardwareScroll                           // HardwareScroller
+	CanFade                                     // Fader
+	CanZoom                                     // Zoomer
+	CanOffset                                   // Offsetter
+)
+
+var capabilityNames = []string{
+	"invert", "scroll", "refresh-rect", "grayscale-table", "sleep", "hardware-scroll", "fade", "zoom", "offset",
+}
+
+func (c Capability) String() string {
+	var names []string
+	for i, name := range capabilityNames {
+		if c&(1<<i) != 0 {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return "none"
+	}
+	return strings.Join(names, "|")
+}
+
+// Capabilities returns the optional interfaces implemented by d, or by the displays it wraps.
+func Capabilities(d Display) (c Capability) {
+	if _, ok := As[Inverter](d); ok {
+		c |= CanInvert
+	}
+	if _, ok := As[Scroller](d); ok {
+		c |= CanScroll
+	}
+	if _, ok := As[WindowRefresher](d); ok {
+		c |= CanRefreshRect
+	}
+	if _, ok := As[GrayscaleTabler](d); ok {
+		c |= CanSetGrayscaleTable
+	}
+	if _, ok := As[Sleeper](d); ok {
+		c |= CanSleep
+	}
+	if _, ok := As[HardwareScroller](d); ok {
+		c |= CanHardwareScroll
+	}
+	if _, ok := As[Fader](d); ok {
+		c |= CanFade
+	}
+	if _, ok := As[Zoomer](d); ok {
+		c |= CanZoom
+	}
+	if _, ok := As[Offsetter](d); ok {
+		c |= CanOffset
+	}
+	return
+}
+
+// Info describes a display controller.
+type Info struct {
+	// Controller name, such as "SSD1306".
+	Controller string
+
+	// Width of the panel in pixels, before rotation.
+	Width int
+
+	// Height of the panel in pixels, before rotation.
+	Height int
+
+	// Depth is the number of bits per pixel.
+	Depth int
+
+	// Capabilities are the supported optional features.
+	Capabilities Capability
+}
+
+func (i Info) String() string {
+	controller := i.Controller
+	if controller == "" {
+		controller = "unknown"
+	}
+	return fmt.Sprintf("%s %dx%d %d-bit (%s)", controller, i.Width, i.Height, i.Depth, i.Capabilities)
+}
+
+// DisplayInfo describes d, or the first display it wraps that is a Describer. Other displays are described by
+// their bounds and color model, without a controller name.
+func DisplayInfo(d Display) Info {
+	if i, ok := As[Describer](d); ok {
+		return i.Info()
+	}
+	b := d.Bounds()
+	return Info{
+		Width:        b.Dx(),
+		Height:       b.Dy(),
+		Depth:        Depth(d.ColorModel()),
+		Capabilities: Capabilities(d),
+	}
+}
+
+// Depth returns the number of bits per pixel of a color model, color models not defined by package pixel
+// are assumed to be 24-bit RGB.
+func Depth(m color.Model) int {
+	switch m {
+	case pixel.MonoModel:
+		return 1
+	case pixel.Gray2Model:
+		return 2
+	case pixel.Gray4Model:
+		return 4
+	case pixel.CBGR15Model, pixel.CRGB15Model:
+		return 15
+	case pixel.CBGR16Model, pixel.CRGB16Model:
+		return 16
+	case color.GrayModel:
+		return 8
+	case color.Gray16Model:
+		return 16
+	default:
+		return 24
+	}
+}
+
+// describe returns the Info for display d, which embeds this baseDisplay.
+func (d *baseDisplay) describe(controller string, display Display) Info {
+	var (
+		b    = d.Image.Bounds()
+		w, h = b.Dx(), b.Dy()
+	)
+	// Controllers rotating in hardware by 90° or 270° have a frame buffer in the rotated layout.
+	if (d.rotation == Rotate90 || d.rotation == Rotate270) && d.transform != Rotate90 && d.transform != Rotate270 {
+		w, h = h, w
+	}
+	return Info{
+		Controller:   controller,
+		Width:        w,
+		Height:       h,
+		Depth:        Depth(d.ColorModel()),
+		Capabilities: Capabilities(display),
+	}
+}
+
+// errHorizontalOffset is returned by controllers that can only shift the picture vertically.
+func errHorizontalOffset(controller string) error {
+	return fmt.Errorf("display: %s horizontal offset: %w", controller, errors.ErrUnsupported)
+}
+
+// startLine returns line wrapped to a RAM with the provided number of rows.
+func startLine(line, rows int) byte {
+	return byte((line%rows + rows) % rows)
+}
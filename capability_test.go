@@ -0,0 +1,143 @@
+package display
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/BeatGlow/display/conntest"
+)
+
+func TestDisplayInfo(t *testing.T) {
+	tests := []struct {
+		Driver   string
+		Rotation Rotation
+		Want     Info
+	}{
+		{"ssd1306", NoRotation, Info{"SSD1306", 128, 64, 1, CanInvert | CanScroll | CanRefreshRect | CanHardwareScroll | CanFade | CanZoom | CanOffset}},
+		{"ssd1306", Rotate90, Info{"SSD1306", 128, 64, 1, CanInvert | CanScroll | CanRefreshRect | CanHardwareScroll | CanFade | CanZoom | CanOffset}},
+		{"sh1106", NoRotation, Info{"SH1106", 128, 64, 1, CanInvert | CanScroll | CanRefreshRect | CanOffset}},
+		{"ssd1305", NoRotation, Info{"SSD1305", 128, 32, 1, CanInvert | CanScroll | CanRefreshRect | CanOffset}},
+		{"ssd1322", NoRotation, Info{"SSD1322", 256, 64, 4, CanInvert | CanScroll | CanRefreshRect | CanSetGrayscaleTable | CanOffset}},
+		{"sh1122", Rotate270, Info{"SH1122", 256, 64, 4, CanInvert | CanScroll | CanRefreshRect | CanOffset}},
+		{"st7735", NoRotation, Info{"ST7735", 128, 160, 16, CanInvert | CanRefreshRect | CanSleep}},
+		{"st7735", Rotate90, Info{"ST7735", 128, 160, 16, CanInvert | CanRefreshRect | CanSleep}},
+		{"st7789", Rotate270, Info{"ST7789", 240, 240, 16, CanInvert | CanRefreshRect | CanSleep}},
+		{"gp1287", NoRotation, Info{"GP1287", 256, 50, 1, CanInvert | CanRefreshRect}},
+		{"gp1294", Rotate90, Info{"GP1294", 256, 48, 1, CanInvert | CanRefreshRect | CanOffset}},
+		{"gu3000", NoRotation, Info{"GU3000", 256, 128, 1, CanRefreshRect}},
+	}
+	for _, test := range tests {
+		t.Run(test.Driver+" "+test.Rotation.String(), func(it *testing.T) {
+			d, err := Open(test.Driver, conntest.NewSPIRecorder(), &Config{
+				Rotation:  test.Rotation,
+				Backlight: conntest.NewPin("BL"),
+			})
+			if err != nil {
+				it.Fatal(err)
+			}
+			if _, ok := d.(Describer); !ok {
+				it.Fatal("expected driver to be a Describer")
+			}
+			if got := DisplayInfo(d); !reflect.DeepEqual(got, test.Want) {
+				it.Errorf("expected %s, got %s", test.Want, got)
+			}
+		})
+	}
+}
+
+func TestDisplayInfoWrapped(t *testing.T) {
+	d, err := Open("ssd1306", conntest.NewRecorder(), &Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := DisplayInfo(d)
+
+	// Optional interfaces are found through stacked wrappers.
+	wrapped := NewPowerManager(ProtectBurnIn(TrackDamage(d), nil), nil)
+	if got := DisplayInfo(wrapped); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %s, got %s", want, got)
+	}
+	if got := Capabilities(wrapped); got != want.Capabilities {
+		t.Errorf("expected capabilities %s, got %s", want.Capabilities, got)
+	}
+	if o, ok := As[Offsetter](wrapped); !ok || o != d {
+		t.Errorf("expected the driver as Offsetter, got %v", o)
+	}
+	if _, ok := As[Sleeper](wrapped); ok {
+		t.Error("expected no Sleeper")
+	}
+}
+
+func TestCapabilityString(t *testing.T) {
+	for _, test := range []struct {
+		Capability Capability
+		Want       string
+	}{
+		{0, "none"},
+		{CanInvert, "invert"},
+		{CanScroll | CanSleep, "scroll|sleep"},
+		{CanRefreshRect | CanSetGrayscaleTable, "refresh-rect|grayscale-table"},
+	} {
+		if got := test.Capability.String(); got != test.Want {
+			t.Errorf("expected %q, got %q", test.Want, got)
+		}
+	}
+}
+
+func TestStartLine(t *testing.T) {
+	for _, test := range []struct {
+		Line, Rows int
+		Want       byte
+	}{
+		{0, 64, 0},
+		{8, 64, 8},
+		{64, 64, 0},
+		{-1, 64, 63},
+		{-130, 128, 126},
+	} {
+		if got := startLine(test.Line, test.Rows); got != test.Want {
+			t.Errorf("startLine(%d, %d): expected %d, got %d", test.Line, test.Rows, test.Want, got)
+		}
+	}
+}
+
+func TestSetGrayscaleTable(t *testing.T) {
+	c := conntest.NewSPIRecorder()
+	d, err := Open("ssd1322", c, &Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := d.(GrayscaleTabler)
+
+	table := []uint8{1, 2, 4, 8, 12, 16, 22, 30, 40, 52, 66, 84, 104, 128, 156}
+	c.Clear()
+	if err = g.SetGrayscaleTable(table); err != nil {
+		t.Fatal(err)
+	}
+	calls := c.Filter(conntest.OpCommand)
+	if len(calls) != 2 {
+		t.Fatalf("expected 2 commands, got %d", len(calls))
+	}
+	if calls[0].Command != ssd1322SetGrayScaleTable || !reflect.DeepEqual(calls[0].Data, table) {
+		t.Errorf("expected grayscale table command, got %s", calls[0])
+	}
+	if calls[1].Command != ssd1322EnableGrayScaleTable {
+		t.Errorf("expected enable grayscale table command, got %s", calls[1])
+	}
+
+	c.Clear()
+	if err = g.SetGrayscaleTable(nil); err != nil {
+		t.Fatal(err)
+	}
+	if got := c.Commands(); !reflect.DeepEqual(got, []byte{ssd1322SetDefaultGrayscale}) {
+		t.Errorf("expected default grayscale command, got % x", got)
+	}
+
+	if err = g.SetGrayscaleTable(table[:4]); !errors.Is(err, ErrGrayscaleTable) {
+		t.Errorf("expected %v for a short table, got %v", ErrGrayscaleTable, err)
+	}
+	if err = g.SetGrayscaleTable(append(table[:14:14], 200)); !errors.Is(err, ErrGrayscaleTable) {
+		t.Errorf("expected %v for an out of range level, got %v", ErrGrayscaleTable, err)
+	}
+}
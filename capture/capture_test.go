@@ -0,0 +1,230 @@
+package capture_test
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"periph.io/x/conn/v3/gpio"
+
+	"github.com/BeatGlow/display"
+	"github.com/BeatGlow/display/capture"
+	"github.com/BeatGlow/display/conntest"
+	"github.com/BeatGlow/display/emulator"
+)
+
+func TestParseEvent(t *testing.T) {
+	tests := []struct {
+		Line string
+		Want string
+	}{
+		{"command ae", "command ae"},
+		{"command  d5 80 ", "command d5 80"},
+		{"command d580", "command d5 80"},
+		{"data 00 ff", "data 00 ff"},
+		{"data", "data"},
+		{"reset High", "reset high"},
+		{"reset low", "reset low"},
+		{"delay 150ms", "delay 150ms"},
+		{"delay 1.5ms", "delay 1.5ms"},
+		{"datalow High", "datalow true"},
+		{"datalow false", "datalow false"},
+		{"mode 3", "mode 3"},
+		{"speed 8000000", "speed 8000000"},
+		{"close", "close"},
+	}
+	for _, test := range tests {
+		t.Run(test.Line, func(it *testing.T) {
+			e, err := capture.ParseEvent(test.Line)
+			if err != nil {
+				it.Fatal(err)
+			}
+			if v := e.String(); v != test.Want {
+				it.Errorf("expected %q, got %q", test.Want, v)
+			}
+		})
+	}
+
+	for _, line := range []string{"command", "command zz", "reset maybe", "delay soon", "mode x", "jump 01"} {
+		if _, err := capture.ParseEvent(line); !errors.Is(err, capture.ErrSyntax) {
+			t.Errorf("%q: expected syntax error, got %v", line, err)
+		}
+	}
+}
+
+func TestReader(t *testing.T) {
+	events, err := capture.ReadAll(strings.NewReader("# display capture\n\ncommand ae\n# comment\ndata 01\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 2 || events[0].Op != capture.OpCommand || events[1].Op != capture.OpData {
+		t.Errorf("unexpected events %v", events)
+	}
+
+	_, err = capture.ReadAll(strings.NewReader("command ae\nbogus\n"))
+	if err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("expected error on line 2, got %v", err)
+	}
+}
+
+func TestTee(t *testing.T) {
+	var (
+		c   = conntest.NewSPIRecorder()
+		out = new(bytes.Buffer)
+		tee = capture.NewTee(c, out)
+		now = time.Unix(0, 0)
+	)
+	tee.Now = func() time.Time { return now }
+
+	_ = tee.SetMode(3)
+	_ = tee.SetMaxSpeed(8000000)
+	_ = tee.Reset(gpio.Low)
+	now = now.Add(10 * time.Millisecond)
+	_ = tee.Reset(gpio.High)
+	now = now.Add(100 * time.Microsecond)
+	tee.SetDataLow(true)
+	_ = tee.Command(0xAE, 0xD5, 0x80)
+	_ = tee.Data(0x00, 0xFF)
+	if err := tee.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := strings.Join([]string{
+		capture.Header,
+		"mode 3",
+		"speed 8000000",
+		"reset low",
+		"delay 10ms",
+		"reset high",
+		"datalow true",
+		"command ae d5 80",
+		"data 00 ff",
+		"close",
+	}, "\n") + "\n"
+	if v := out.String(); v != want {
+		t.Errorf("expected capture:\n%s\ngot:\n%s", want, v)
+	}
+	if calls := c.Calls(); len(calls) != 8 {
+		t.Errorf("expected 8 calls passed through, got %d", len(calls))
+	}
+	if c.Mode != 3 || c.MaxSpeed != 8000000 || !c.DataLow {
+		t.Errorf("SPI configuration not passed through")
+	}
+}
+
+type failWriter struct{}
+
+func (failWriter) Write([]byte) (int, error) { return 0, io.ErrShortWrite }
+
+func TestTeeError(t *testing.T) {
+	var (
+		c   = conntest.NewRecorder()
+		tee = capture.NewTee(c, failWriter{})
+	)
+	if err := tee.Command(0xAF); err != nil {
+		t.Fatalf("expected call to pass, got %v", err)
+	}
+	if err := tee.Close(); !errors.Is(err, io.ErrShortWrite) {
+		t.Errorf("expected write error, got %v", err)
+	}
+	if len(c.Calls()) != 2 {
+		t.Errorf("expected calls to pass through")
+	}
+
+	c.Err = errors.New("bus error")
+	if err := capture.NewTee(c, io.Discard).Data(0x00); err != c.Err {
+		t.Errorf("expected bus error, got %v", err)
+	}
+}
+
+func TestReplay(t *testing.T) {
+	var (
+		recorded = emulator.NewSSD1306(128, 64)
+		out      = new(bytes.Buffer)
+		tee      = capture.NewTee(recorded, out)
+	)
+	d, err := display.Open("ssd1306", tee, &display.Config{Rotation: display.Rotate180})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for y := 0; y < 64; y++ {
+		for x := 0; x < 128; x++ {
+			if (x^y)&4 == 0 {
+				d.Set(x, y, color.White)
+			}
+		}
+	}
+	if err = d.Refresh(); err != nil {
+		t.Fatal(err)
+	}
+	if err = tee.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	replayed := emulator.NewSSD1306(128, 64)
+	if err = capture.Replay(replayed, bytes.NewReader(out.Bytes())); err != nil {
+		t.Fatal(err)
+	}
+	if equal(replayed.Image(), emulator.NewSSD1306(128, 64).Image()) {
+		t.Error("expected replay to show an image")
+	}
+	if !equal(recorded.Image(), replayed.Image()) {
+		t.Error("replayed image differs from recorded image")
+	}
+	if replayed.Mode() != recorded.Mode() {
+		t.Errorf("expected SPI mode %d, got %d", recorded.Mode(), replayed.Mode())
+	}
+
+	if err = capture.Replay(conntest.NewRecorder(), strings.NewReader("command ae\nnope\n")); err == nil {
+		t.Error("expected syntax error")
+	}
+}
+
+func TestReplayer(t *testing.T) {
+	var (
+		c      = conntest.NewRecorder()
+		p      = capture.NewReplayer(c)
+		delays []time.Duration
+	)
+	p.Sleep = func(d time.Duration) { delays = append(delays, d) }
+	p.SkipClose = true
+	if err := p.Replay(strings.NewReader("reset low\ndelay 10ms\nreset high\nmode 3\ndatalow true\ncommand af\nclose\n")); err != nil {
+		t.Fatal(err)
+	}
+	if len(delays) != 1 || delays[0] != 10*time.Millisecond {
+		t.Errorf("expected a 10ms delay, got %v", delays)
+	}
+	var calls []string
+	for _, call := range c.Calls() {
+		calls = append(calls, call.String())
+	}
+	if v, want := strings.Join(calls, ", "), "reset Low, reset High, command af"; v != want {
+		t.Errorf("expected calls %q, got %q", want, v)
+	}
+
+	p.SkipDelays = true
+	delays = nil
+	if err := p.Replay(strings.NewReader("delay 1s\n")); err != nil || delays != nil {
+		t.Errorf("expected delays to be skipped, got %v, %v", delays, err)
+	}
+}
+
+func equal(a, b image.Image) bool {
+	if a.Bounds() != b.Bounds() {
+		return false
+	}
+	r := a.Bounds()
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			if a.At(x, y) != b.At(x, y) {
+				return false
+			}
+		}
+	}
+	return true
+}
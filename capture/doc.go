@@ -0,0 +1,26 @@
+// Package capture records and replays display bus sessions.
+//
+// A capture file is a line oriented text file, every line holds one event:
+//
+//	# display capture
+//	reset low
+//	delay 150ms
+//	reset high
+//	mode 3
+//	speed 8000000
+//	datalow false
+//	command ae d5 80
+//	data 00 ff 00 ff
+//	close
+//
+// Command and data events list their bytes in hexadecimal, the first byte of a command event is the
+// command byte. Delay events hold the time between two calls, in the format accepted by
+// [time.ParseDuration]. The datalow event records a change of the data/command (DC) pin behaviour. Empty
+// lines and lines starting with # are ignored.
+//
+// The format is compatible with the output of the conntest package, so recordings made in tests can be
+// replayed and disassembled.
+//
+// A [Tee] records the session of a driver to a capture file while passing all calls through to the real
+// bus, a [Replayer] drives a real connection or an emulator from a capture file.
+package capture
@@ -0,0 +1,156 @@
+package capture
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"periph.io/x/conn/v3/gpio"
+)
+
+// Op is the type of event.
+type Op uint8
+
+// Events.
+const (
+	OpCommand Op = iota + 1
+	OpData
+	OpReset
+	OpDelay
+	OpDataLow
+	OpMode
+	OpSpeed
+	OpClose
+)
+
+var opNames = map[Op]string{
+	OpCommand: "command",
+	OpData:    "data",
+	OpReset:   "reset",
+	OpDelay:   "delay",
+	OpDataLow: "datalow",
+	OpMode:    "mode",
+	OpSpeed:   "speed",
+	OpClose:   "close",
+}
+
+func (op Op) String() string {
+	if name, ok := opNames[op]; ok {
+		return name
+	}
+	return fmt.Sprintf("op(%d)", op)
+}
+
+// Event is a recorded bus event.
+type Event struct {
+	// Op is the type of event.
+	Op Op
+
+	// Command byte, for OpCommand.
+	Command byte
+
+	// Data are the command arguments for OpCommand, or the data bytes for OpData.
+	Data []byte
+
+	// Level of the reset pin for OpReset.
+	Level gpio.Level
+
+	// DataLow is the data/command behaviour for OpDataLow.
+	DataLow bool
+
+	// Delay is the time between two calls for OpDelay.
+	Delay time.Duration
+
+	// Value is the SPI mode for OpMode, or the speed in Hz for OpSpeed.
+	Value int
+}
+
+func (e Event) String() string {
+	switch e.Op {
+	case OpCommand:
+		return strings.TrimSpace(fmt.Sprintf("command %02x % x", e.Command, e.Data))
+	case OpData:
+		return strings.TrimSpace(fmt.Sprintf("data % x", e.Data))
+	case OpReset:
+		return "reset " + strings.ToLower(e.Level.String())
+	case OpDelay:
+		return "delay " + e.Delay.String()
+	case OpDataLow:
+		return "datalow " + strconv.FormatBool(e.DataLow)
+	case OpMode, OpSpeed:
+		return fmt.Sprintf("%s %d", e.Op, e.Value)
+	default:
+		return e.Op.String()
+	}
+}
+
+// ErrSyntax is returned for lines that can't be parsed.
+var ErrSyntax = errors.New("capture: syntax error")
+
+// ParseEvent parses a line of a capture file.
+func ParseEvent(line string) (e Event, err error) {
+	name, args, _ := strings.Cut(strings.TrimSpace(line), " ")
+	args = strings.TrimSpace(args)
+	switch name {
+	case "command":
+		var b []byte
+		if b, err = parseHex(args); err != nil {
+			return
+		}
+		if len(b) == 0 {
+			return e, fmt.Errorf("%w: command without command byte", ErrSyntax)
+		}
+		e.Op, e.Command, e.Data = OpCommand, b[0], b[1:]
+	case "data":
+		e.Op = OpData
+		e.Data, err = parseHex(args)
+	case "reset":
+		e.Op = OpReset
+		e.Level, err = parseLevel(args)
+	case "delay":
+		e.Op = OpDelay
+		if e.Delay, err = time.ParseDuration(args); err != nil {
+			err = fmt.Errorf("%w: %w", ErrSyntax, err)
+		}
+	case "datalow":
+		var level gpio.Level
+		if level, err = parseLevel(args); err == nil {
+			e.Op, e.DataLow = OpDataLow, bool(level)
+		}
+	case "mode", "speed":
+		e.Op = OpMode
+		if name == "speed" {
+			e.Op = OpSpeed
+		}
+		if e.Value, err = strconv.Atoi(args); err != nil {
+			err = fmt.Errorf("%w: %w", ErrSyntax, err)
+		}
+	case "close":
+		e.Op = OpClose
+	default:
+		err = fmt.Errorf("%w: unknown event %q", ErrSyntax, name)
+	}
+	return
+}
+
+func parseHex(s string) ([]byte, error) {
+	b, err := hex.DecodeString(strings.Join(strings.Fields(s), ""))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrSyntax, err)
+	}
+	return b, nil
+}
+
+func parseLevel(s string) (gpio.Level, error) {
+	switch strings.ToLower(s) {
+	case "high", "true", "1":
+		return gpio.High, nil
+	case "low", "false", "0":
+		return gpio.Low, nil
+	default:
+		return gpio.Low, fmt.Errorf("%w: invalid level %q", ErrSyntax, s)
+	}
+}
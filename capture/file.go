@@ -0,0 +1,86 @@
+package capture
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Header is the first line written to a capture file.
+const Header = "# display capture"
+
+// Writer writes events to a capture file.
+type Writer struct {
+	w      *bufio.Writer
+	header bool
+}
+
+// NewWriter returns a new capture file writer.
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: bufio.NewWriter(w)}
+}
+
+// Write an event.
+func (w *Writer) Write(e Event) (err error) {
+	if !w.header {
+		if _, err = fmt.Fprintln(w.w, Header); err != nil {
+			return
+		}
+		w.header = true
+	}
+	_, err = fmt.Fprintln(w.w, e)
+	return
+}
+
+// Flush writes any buffered events to the underlying writer.
+func (w *Writer) Flush() error {
+	return w.w.Flush()
+}
+
+// Reader reads events from a capture file.
+type Reader struct {
+	s    *bufio.Scanner
+	line int
+}
+
+// NewReader returns a new capture file reader.
+func NewReader(r io.Reader) *Reader {
+	s := bufio.NewScanner(r)
+	s.Buffer(nil, 1<<24)
+	return &Reader{s: s}
+}
+
+// Next returns the next event, or io.EOF at the end of the file.
+func (r *Reader) Next() (Event, error) {
+	for r.s.Scan() {
+		r.line++
+		line := strings.TrimSpace(r.s.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		e, err := ParseEvent(line)
+		if err != nil {
+			return e, fmt.Errorf("line %d: %w", r.line, err)
+		}
+		return e, nil
+	}
+	if err := r.s.Err(); err != nil {
+		return Event{}, err
+	}
+	return Event{}, io.EOF
+}
+
+// ReadAll reads all events from a capture file.
+func ReadAll(r io.Reader) (events []Event, err error) {
+	cr := NewReader(r)
+	for {
+		var e Event
+		if e, err = cr.Next(); err == io.EOF {
+			return events, nil
+		} else if err != nil {
+			return
+		}
+		events = append(events, e)
+	}
+}
@@ -0,0 +1,91 @@
+package capture
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/BeatGlow/display"
+	"github.com/BeatGlow/display/conn"
+)
+
+// Replayer drives a connection from a capture file.
+type Replayer struct {
+	// Conn is the connection the events are replayed to, this may be a real bus or an emulator.
+	Conn display.Conn
+
+	// SkipDelays replays the session as fast as possible, ignoring all delay events.
+	SkipDelays bool
+
+	// SkipClose ignores close events, so the connection remains usable after the replay.
+	SkipClose bool
+
+	// Sleep waits for delay events, defaults to [time.Sleep].
+	Sleep func(time.Duration)
+}
+
+// NewReplayer returns a replayer that drives c.
+func NewReplayer(c display.Conn) *Replayer {
+	return &Replayer{Conn: c}
+}
+
+// Replay all events read from r.
+func (p *Replayer) Replay(r io.Reader) error {
+	cr := NewReader(r)
+	for {
+		e, err := cr.Next()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if err = p.Event(e); err != nil {
+			return fmt.Errorf("capture: replay %s: %w", e.Op, err)
+		}
+	}
+}
+
+// Event replays a single event. SPI events are ignored if the connection isn't a SPI connection.
+func (p *Replayer) Event(e Event) error {
+	spi, isSPI := p.Conn.(display.SPI)
+	switch e.Op {
+	case OpCommand:
+		return p.Conn.Command(e.Command, e.Data...)
+	case OpData:
+		return p.Conn.Data(e.Data...)
+	case OpReset:
+		return p.Conn.Reset(e.Level)
+	case OpDelay:
+		if !p.SkipDelays {
+			if p.Sleep != nil {
+				p.Sleep(e.Delay)
+			} else {
+				time.Sleep(e.Delay)
+			}
+		}
+	case OpDataLow:
+		if isSPI {
+			spi.SetDataLow(e.DataLow)
+		}
+	case OpMode:
+		if isSPI {
+			return spi.SetMode(conn.SPIMode(e.Value))
+		}
+	case OpSpeed:
+		if isSPI {
+			return spi.SetMaxSpeed(e.Value)
+		}
+	case OpClose:
+		if !p.SkipClose {
+			return p.Conn.Close()
+		}
+	default:
+		return fmt.Errorf("capture: unknown event %s", e.Op)
+	}
+	return nil
+}
+
+// Replay all events read from r to c, honoring delays.
+func Replay(c display.Conn, r io.Reader) error {
+	return NewReplayer(c).Replay(r)
+}
@@ -0,0 +1,172 @@
+package capture
+
+import (
+	"io"
+	"sync"
+	"time"
+
+	"periph.io/x/conn/v3/gpio"
+
+	"github.com/BeatGlow/display"
+	"github.com/BeatGlow/display/conn"
+)
+
+// DefaultMinDelay is the shortest gap between two calls that is recorded as a delay.
+const DefaultMinDelay = time.Millisecond
+
+// Tee is a connection that records all calls to a capture file, while passing them through to the
+// underlying connection.
+//
+// Drivers wait between some calls, for example after a reset. These waits are recorded as delay events,
+// if the time between two calls is at least MinDelay.
+//
+// Calls are passed through even if recording fails, the first recording error is returned by Err.
+type Tee struct {
+	// MinDelay is the shortest gap between two calls that is recorded as a delay, defaults to
+	// DefaultMinDelay.
+	MinDelay time.Duration
+
+	// Now returns the current time, defaults to [time.Now].
+	Now func() time.Time
+
+	mu   sync.Mutex
+	c    display.Conn
+	w    *Writer
+	last time.Time
+	err  error
+}
+
+// NewTee returns a connection that records all calls to c to w.
+func NewTee(c display.Conn, w io.Writer) *Tee {
+	return &Tee{
+		c: c,
+		w: NewWriter(w),
+	}
+}
+
+func (t *Tee) now() time.Time {
+	if t.Now != nil {
+		return t.Now()
+	}
+	return time.Now()
+}
+
+// record an event and pass the call through.
+func (t *Tee) record(e Event, call func() error) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	minDelay := t.MinDelay
+	if minDelay <= 0 {
+		minDelay = DefaultMinDelay
+	}
+	if now := t.now(); !t.last.IsZero() {
+		if delay := now.Sub(t.last); delay >= minDelay {
+			t.write(Event{Op: OpDelay, Delay: delay.Round(time.Microsecond)})
+		}
+	}
+	t.write(e)
+
+	err := call()
+	t.last = t.now()
+	return err
+}
+
+func (t *Tee) write(e Event) {
+	if err := t.w.Write(e); err != nil && t.err == nil {
+		t.err = err
+	}
+}
+
+// Err returns the first error that occurred while recording.
+func (t *Tee) Err() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.err
+}
+
+// Flush writes all recorded events to the capture file.
+func (t *Tee) Flush() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if err := t.w.Flush(); err != nil && t.err == nil {
+		t.err = err
+	}
+	return t.err
+}
+
+func (t *Tee) String() string {
+	return "capture " + t.c.String()
+}
+
+// Interface returns the interface of the underlying connection.
+func (t *Tee) Interface() interface{} {
+	return t.c.Interface()
+}
+
+// Close the underlying connection and flush the capture file.
+func (t *Tee) Close() (err error) {
+	err = t.record(Event{Op: OpClose}, t.c.Close)
+	if ferr := t.Flush(); err == nil {
+		err = ferr
+	}
+	return
+}
+
+// Reset sets the reset pin to the provided level.
+func (t *Tee) Reset(level gpio.Level) error {
+	return t.record(Event{Op: OpReset, Level: level}, func() error {
+		return t.c.Reset(level)
+	})
+}
+
+// Command sends a command byte with optional arguments.
+func (t *Tee) Command(command byte, args ...byte) error {
+	return t.record(Event{Op: OpCommand, Command: command, Data: args}, func() error {
+		return t.c.Command(command, args...)
+	})
+}
+
+// Data sends data bytes.
+func (t *Tee) Data(data ...byte) error {
+	return t.record(Event{Op: OpData, Data: data}, func() error {
+		return t.c.Data(data...)
+	})
+}
+
+// SetDataLow changes the data/command direction behaviour, if the underlying connection is a SPI
+// connection.
+func (t *Tee) SetDataLow(low bool) {
+	_ = t.record(Event{Op: OpDataLow, DataLow: low}, func() error {
+		if c, ok := t.c.(display.SPI); ok {
+			c.SetDataLow(low)
+		}
+		return nil
+	})
+}
+
+// SetMode requests a SPI mode, if the underlying connection is a SPI connection.
+func (t *Tee) SetMode(mode conn.SPIMode) error {
+	return t.record(Event{Op: OpMode, Value: int(mode)}, func() error {
+		if c, ok := t.c.(display.SPI); ok {
+			return c.SetMode(mode)
+		}
+		return nil
+	})
+}
+
+// SetMaxSpeed requests a SPI speed, if the underlying connection is a SPI connection.
+func (t *Tee) SetMaxSpeed(hz int) error {
+	return t.record(Event{Op: OpSpeed, Value: hz}, func() error {
+		if c, ok := t.c.(display.SPI); ok {
+			return c.SetMaxSpeed(hz)
+		}
+		return nil
+	})
+}
+
+// Interface checks
+var (
+	_ display.SPI = (*Tee)(nil)
+)
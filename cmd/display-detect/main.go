@@ -0,0 +1,42 @@
+// Command display-detect scans an I²C bus for OLED displays and suggests a driver for each display found.
+//
+// Probing writes to the display RAM, so the contents of a running display may change.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"periph.io/x/host/v3"
+
+	"github.com/BeatGlow/display"
+)
+
+func main() {
+	i2cDeviceFlag := flag.Int("i2c-dev", display.DefaultI2CConfig.Device, "I²C device number (default: use first available)")
+	flag.Parse()
+
+	if _, err := host.Init(); err != nil {
+		fatal(err)
+	}
+
+	found, err := display.DetectI2C(*i2cDeviceFlag)
+	if err != nil {
+		fatal(err)
+	}
+	if len(found) == 0 {
+		fmt.Println("no displays found")
+		os.Exit(1)
+	}
+	for _, d := range found {
+		fmt.Println(d)
+		fmt.Printf("  display-test -i2c-dev %d -i2c-addr %#02x -width %d -height %d i2c %s\n",
+			d.I2C.Device, d.I2C.Addr, d.Config.Width, d.Config.Height, d.Driver)
+	}
+}
+
+func fatal(err error) {
+	fmt.Fprintln(os.Stderr, "fatal: "+err.Error())
+	os.Exit(1)
+}
@@ -0,0 +1,96 @@
+// Command display-disasm decodes recorded display bus traffic into human-readable instructions.
+//
+// The input is a capture file, as written by the capture package or the conntest package:
+//
+//	command ae d5 80
+//	data 00 ff 00 ff
+//
+// Command and data events are decoded, other events (such as resets and delays) are copied to the output
+// as comments.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/BeatGlow/display"
+	"github.com/BeatGlow/display/capture"
+)
+
+func main() {
+	protocolFlag := flag.String("protocol", "", "Protocol or driver name (ssd1306, sh1106, sh1122, ssd1322, st7735, st7789, vfd)")
+	hexFlag := flag.Bool("hex", false, "Show the raw bytes of every instruction")
+	flag.Parse()
+
+	protocol, err := display.ParseProtocol(*protocolFlag)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Usage: %s -protocol <name> [<file>...]\n\n", os.Args[0])
+		flag.PrintDefaults()
+		os.Exit(1)
+	}
+
+	d := display.NewDisassembler(protocol)
+	if flag.NArg() == 0 {
+		if err = disassemble(os.Stdout, os.Stdin, d, *hexFlag); err != nil {
+			fatal(err)
+		}
+		return
+	}
+	for _, name := range flag.Args() {
+		f, err := os.Open(name)
+		if err != nil {
+			fatal(err)
+		}
+		err = disassemble(os.Stdout, f, d, *hexFlag)
+		_ = f.Close()
+		if err != nil {
+			fatal(fmt.Errorf("%s: %w", name, err))
+		}
+	}
+}
+
+func disassemble(w io.Writer, r io.Reader, d *display.Disassembler, showHex bool) error {
+	cr := capture.NewReader(r)
+	for {
+		e, err := cr.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		}
+
+		switch e.Op {
+		case capture.OpCommand:
+			output(w, d.Command(e.Command, e.Data...), showHex)
+		case capture.OpData:
+			output(w, d.Data(e.Data...), showHex)
+		default:
+			output(w, d.Flush(), showHex)
+			fmt.Fprintf(w, "; %s\n", e)
+		}
+	}
+	output(w, d.Flush(), showHex)
+	return nil
+}
+
+func output(w io.Writer, instructions []display.Instruction, showHex bool) {
+	for _, i := range instructions {
+		if !showHex {
+			fmt.Fprintln(w, i)
+			continue
+		}
+		b := i.Bytes()
+		if len(b) > 16 {
+			fmt.Fprintf(w, "%-40s ; % x ...\n", i, b[:16])
+		} else {
+			fmt.Fprintf(w, "%-40s ; % x\n", i, b)
+		}
+	}
+}
+
+func fatal(err error) {
+	fmt.Fprintln(os.Stderr, "fatal: "+err.Error())
+	os.Exit(1)
+}
@@ -15,6 +15,9 @@ import (
 	"github.com/BeatGlow/display"
 	"github.com/BeatGlow/display/draw"
 	"github.com/BeatGlow/display/pixel"
+	"github.com/BeatGlow/display/preview"
+	"github.com/BeatGlow/display/rfb"
+	"github.com/BeatGlow/display/terminal"
 )
 
 func main() {
@@ -30,10 +33,20 @@ func main() {
 	cePinFlag := flag.String("ce", "GPIO8", "Chip enable GPIO pin")
 	blPinFlag := flag.String("bl", "GPIO19", "Backlight GPIO pin")
 	rotateFlag := flag.String("rotate", "", "Display rotation")
+	listFlag := flag.Bool("list", false, "List available drivers")
+	previewFlag := flag.String("preview", "", "Serve a live preview over HTTP on this address (for example :8080)")
+	vncFlag := flag.String("vnc", "", "Serve the display over VNC on this address (for example :5900)")
 	flag.Parse()
 
+	if *listFlag {
+		listDrivers()
+		return
+	}
+
 	if flag.NArg() != 2 {
 		fmt.Fprintf(os.Stderr, "Usage: %s <bus> <driver>\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "       %s terminal <mono|braille|gray2|gray4|rgb>\n\n", os.Args[0])
+		listDrivers()
 		os.Exit(1)
 	}
 
@@ -52,6 +65,20 @@ func main() {
 	}
 	fmt.Printf("using rotation: %s\n", rotation)
 
+	if flag.Arg(0) == "terminal" {
+		output, err := openTerminal(flag.Arg(1), &display.Config{
+			Width:    *widthFlag,
+			Height:   *heightFlag,
+			Rotation: rotation,
+		})
+		if err != nil {
+			fatal(err)
+		}
+		defer output.Close()
+		run(withVNC(withPreview(output, *previewFlag), *vncFlag))
+		return
+	}
+
 	if _, err := host.Init(); err != nil {
 		fatal(err)
 	}
@@ -92,32 +119,66 @@ func main() {
 	defer conn.Close()
 	fmt.Printf("using connection: %s\n", conn)
 
-	switch driver := strings.ToLower(flag.Arg(1)); driver {
-	case "gp1294":
-		output, err = display.GP1294(conn, config)
-	case "sh1106":
-		output, err = display.SH1106(conn, config)
-	case "ssd1305":
-		output, err = display.SSD1305(conn, config)
-	case "ssd1306":
-		output, err = display.SSD1306(conn, config)
-	case "ssd1322":
-		output, err = display.SSD1322(conn, config)
-	//case "ssd1326":
-	//	output, err = display.SSD1326(conn, config)
-	case "st7735":
-		output, err = display.ST7735(conn, config)
-	case "st7789":
-		output, err = display.ST7789(conn, config)
-	default:
-		err = fmt.Errorf("unsupported driver %q", driver)
-	}
-	if err != nil {
+	if output, err = display.Open(flag.Arg(1), conn, config); err != nil {
 		fatal(err)
 	}
+	run(withVNC(withPreview(output, *previewFlag), *vncFlag))
+}
+
+// withPreview serves a live preview of the display on addr, if not empty.
+func withPreview(output display.Display, addr string) display.Display {
+	if addr == "" {
+		return output
+	}
+	server := preview.New(output)
+	go func() {
+		if err := server.ListenAndServe(addr); err != nil {
+			fatal(err)
+		}
+	}()
+	fmt.Printf("serving preview on http://%s/\n", addr)
+	return server
+}
+
+// withVNC serves the display over VNC on addr, if not empty.
+func withVNC(output display.Display, addr string) display.Display {
+	if addr == "" {
+		return output
+	}
+	server := rfb.New(output)
+	server.ReadOnly = true
+	go func() {
+		if err := server.ListenAndServe(addr); err != nil {
+			fatal(err)
+		}
+	}()
+	fmt.Printf("serving VNC on %s\n", addr)
+	return server
+}
 
+// openTerminal opens a terminal display emulating the named color model.
+func openTerminal(model string, config *display.Config) (display.Display, error) {
+	terminalConfig := &terminal.Config{Config: *config}
+	switch model {
+	case "mono":
+	case "braille":
+		terminalConfig.Braille = true
+	case "gray2":
+		terminalConfig.Model = pixel.Gray2Model
+	case "gray4":
+		terminalConfig.Model = pixel.Gray4Model
+	case "rgb":
+		terminalConfig.Model = pixel.CRGB16Model
+	default:
+		return nil, fmt.Errorf("unsupported terminal model %q", model)
+	}
+	return terminal.New(os.Stdout, terminalConfig)
+}
+
+func run(output display.Display) {
 	fmt.Printf("using driver: %s\n", output)
 	var (
+		err    error
 		offset int
 		ticker = time.NewTicker(50 * time.Millisecond)
 		r      = output.Bounds()
@@ -231,6 +292,17 @@ func main() {
 	}
 }
 
+func listDrivers() {
+	fmt.Println("Available drivers:")
+	for _, driver := range display.Drivers() {
+		name := driver.Name
+		if len(driver.Aliases) > 0 {
+			name += " (" + strings.Join(driver.Aliases, ", ") + ")"
+		}
+		fmt.Printf("  %-28s %-12s %dx%d\n", name, driver.Buses, driver.Width, driver.Height)
+	}
+}
+
 func fatal(err error) {
 	fmt.Fprintln(os.Stderr, "fatal: "+err.Error())
 	os.Exit(1)
@@ -12,6 +12,7 @@ import (
 type I2C struct {
 	bus  i2c.BusCloser
 	conn conn.Conn
+	addr uint8
 }
 
 func OpenI2C(device int, addr uint8) (*I2C, error) {
@@ -28,10 +29,21 @@ func OpenI2C(device int, addr uint8) (*I2C, error) {
 		return nil, err
 	}
 
+	return NewI2C(bus, addr), nil
+}
+
+// NewI2C returns a connection to the device at addr on an opened bus, such as a mock bus in tests.
+func NewI2C(bus i2c.BusCloser, addr uint8) *I2C {
 	return &I2C{
 		bus:  bus,
 		conn: &i2c.Dev{Bus: bus, Addr: uint16(addr)},
-	}, nil
+		addr: addr,
+	}
+}
+
+// Addr returns the device address.
+func (c *I2C) Addr() uint8 {
+	return c.addr
 }
 
 func (c *I2C) String() string {
@@ -54,3 +66,8 @@ func (c *I2C) Read(p []byte) (int, error) {
 func (c *I2C) Write(p []byte) (int, error) {
 	return len(p), c.conn.Tx(p, nil)
 }
+
+// Tx writes w and then reads r in a single transaction, either may be nil.
+func (c *I2C) Tx(w, r []byte) error {
+	return c.conn.Tx(w, r)
+}
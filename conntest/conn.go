@@ -0,0 +1,237 @@
+package conntest
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"periph.io/x/conn/v3/gpio"
+
+	"github.com/BeatGlow/display/conn"
+)
+
+// Op is the type of recorded call.
+type Op uint8
+
+// Recorded calls.
+const (
+	OpCommand Op = iota
+	OpData
+	OpReset
+	OpClose
+	OpSetDataLow
+	OpSetMode
+	OpSetMaxSpeed
+)
+
+func (op Op) String() string {
+	switch op {
+	case OpCommand:
+		return "command"
+	case OpData:
+		return "data"
+	case OpReset:
+		return "reset"
+	case OpClose:
+		return "close"
+	case OpSetDataLow:
+		return "datalow"
+	case OpSetMode:
+		return "mode"
+	case OpSetMaxSpeed:
+		return "speed"
+	default:
+		return fmt.Sprintf("op(%d)", op)
+	}
+}
+
+// Call is a recorded call.
+type Call struct {
+	// Time of the call.
+	Time time.Time
+
+	// Op is the type of call.
+	Op Op
+
+	// Command byte, for OpCommand.
+	Command byte
+
+	// Data are the command arguments for OpCommand, or the data bytes for OpData.
+	Data []byte
+
+	// Level of the reset pin for OpReset, or the data/command behaviour for OpSetDataLow.
+	Level gpio.Level
+
+	// Value is the SPI mode for OpSetMode, or the speed in Hz for OpSetMaxSpeed.
+	Value int
+}
+
+func (c Call) String() string {
+	switch c.Op {
+	case OpCommand:
+		return strings.TrimSpace(fmt.Sprintf("command %02x % x", c.Command, c.Data))
+	case OpData:
+		return fmt.Sprintf("data % x", c.Data)
+	case OpReset, OpSetDataLow:
+		return fmt.Sprintf("%s %s", c.Op, c.Level)
+	case OpSetMode, OpSetMaxSpeed:
+		return fmt.Sprintf("%s %d", c.Op, c.Value)
+	default:
+		return c.Op.String()
+	}
+}
+
+// Recorder is a connection that records all calls.
+type Recorder struct {
+	// Err is returned by all calls, if set.
+	Err error
+
+	// Now returns the time for recorded calls, defaults to [time.Now].
+	Now func() time.Time
+
+	mu    sync.Mutex
+	calls []Call
+}
+
+// NewRecorder returns a new connection recorder.
+func NewRecorder() *Recorder {
+	return new(Recorder)
+}
+
+func (r *Recorder) record(call Call) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.Now != nil {
+		call.Time = r.Now()
+	} else {
+		call.Time = time.Now()
+	}
+	r.calls = append(r.calls, call)
+	return r.Err
+}
+
+func (r *Recorder) String() string {
+	return "recorder"
+}
+
+// Close records the close call.
+func (r *Recorder) Close() error {
+	return r.record(Call{Op: OpClose})
+}
+
+// Reset records the reset pin level.
+func (r *Recorder) Reset(level gpio.Level) error {
+	return r.record(Call{Op: OpReset, Level: level})
+}
+
+// Command records a command with its arguments.
+func (r *Recorder) Command(command byte, args ...byte) error {
+	return r.record(Call{Op: OpCommand, Command: command, Data: append([]byte(nil), args...)})
+}
+
+// Data records data bytes.
+func (r *Recorder) Data(data ...byte) error {
+	return r.record(Call{Op: OpData, Data: append([]byte(nil), data...)})
+}
+
+// Interface returns the recorder.
+func (r *Recorder) Interface() interface{} {
+	return r
+}
+
+// Calls returns a copy of the recorded calls.
+func (r *Recorder) Calls() []Call {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Call(nil), r.calls...)
+}
+
+// Filter returns the recorded calls of the requested ops.
+func (r *Recorder) Filter(ops ...Op) []Call {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var calls []Call
+	for _, call := range r.calls {
+		for _, op := range ops {
+			if call.Op == op {
+				calls = append(calls, call)
+				break
+			}
+		}
+	}
+	return calls
+}
+
+// Commands returns the recorded command bytes, without arguments.
+func (r *Recorder) Commands() []byte {
+	var commands []byte
+	for _, call := range r.Filter(OpCommand) {
+		commands = append(commands, call.Command)
+	}
+	return commands
+}
+
+// DataLen returns the total number of recorded data bytes.
+func (r *Recorder) DataLen() int {
+	var n int
+	for _, call := range r.Filter(OpData) {
+		n += len(call.Data)
+	}
+	return n
+}
+
+// Clear the recorded calls.
+func (r *Recorder) Clear() {
+	r.mu.Lock()
+	r.calls = nil
+	r.mu.Unlock()
+}
+
+// SPIRecorder is a connection recorder that also records SPI configuration calls.
+type SPIRecorder struct {
+	Recorder
+
+	// DataLow is the current data/command behaviour.
+	DataLow bool
+
+	// Mode is the current SPI mode.
+	Mode conn.SPIMode
+
+	// MaxSpeed is the current SPI speed in Hz.
+	MaxSpeed int
+}
+
+// NewSPIRecorder returns a new SPI connection recorder.
+func NewSPIRecorder() *SPIRecorder {
+	return new(SPIRecorder)
+}
+
+func (r *SPIRecorder) String() string {
+	return "SPI recorder"
+}
+
+// Interface returns the recorder.
+func (r *SPIRecorder) Interface() interface{} {
+	return r
+}
+
+// SetDataLow records the data/command behaviour.
+func (r *SPIRecorder) SetDataLow(v bool) {
+	r.DataLow = v
+	_ = r.record(Call{Op: OpSetDataLow, Level: gpio.Level(v)})
+}
+
+// SetMode records the SPI mode.
+func (r *SPIRecorder) SetMode(mode conn.SPIMode) error {
+	r.Mode = mode
+	return r.record(Call{Op: OpSetMode, Value: int(mode)})
+}
+
+// SetMaxSpeed records the SPI speed.
+func (r *SPIRecorder) SetMaxSpeed(hz int) error {
+	r.MaxSpeed = hz
+	return r.record(Call{Op: OpSetMaxSpeed, Value: hz})
+}
@@ -0,0 +1,99 @@
+package conntest_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"periph.io/x/conn/v3/gpio"
+
+	"github.com/BeatGlow/display"
+	"github.com/BeatGlow/display/conn"
+	"github.com/BeatGlow/display/conntest"
+)
+
+func TestRecorder(t *testing.T) {
+	var (
+		now = time.Unix(0, 0)
+		c   = conntest.NewSPIRecorder()
+	)
+	c.Now = func() time.Time {
+		now = now.Add(time.Millisecond)
+		return now
+	}
+
+	c.SetDataLow(true)
+	_ = c.SetMode(conn.SPIMode3)
+	_ = c.SetMaxSpeed(8_000_000)
+	_ = c.Reset(gpio.Low)
+	_ = c.Command(0xae, 0x01, 0x02)
+	_ = c.Data(0xff)
+	_ = c.Close()
+
+	want := []string{
+		"datalow High",
+		"mode 3",
+		"speed 8000000",
+		"reset Low",
+		"command ae 01 02",
+		"data ff",
+		"close",
+	}
+	calls := c.Calls()
+	if len(calls) != len(want) {
+		t.Fatalf("expected %d calls, got %d", len(want), len(calls))
+	}
+	for i, call := range calls {
+		if v := call.String(); v != want[i] {
+			t.Errorf("call %d: expected %q, got %q", i, want[i], v)
+		}
+		if v := call.Time; !v.Equal(time.Unix(0, int64(i+1)*int64(time.Millisecond))) {
+			t.Errorf("call %d: unexpected time %s", i, v)
+		}
+	}
+
+	if v := c.Commands(); len(v) != 1 || v[0] != 0xae {
+		t.Errorf("expected commands [ae], got % x", v)
+	}
+	if v := c.DataLen(); v != 1 {
+		t.Errorf("expected 1 data byte, got %d", v)
+	}
+
+	c.Clear()
+	if v := c.Calls(); len(v) != 0 {
+		t.Errorf("expected no calls after Clear, got %d", len(v))
+	}
+
+	c.Err = errors.New("test")
+	if err := c.Data(0x00); err != c.Err {
+		t.Errorf("expected error %v, got %v", c.Err, err)
+	}
+}
+
+func TestPin(t *testing.T) {
+	p := conntest.NewPin("BL")
+	if err := p.Out(gpio.High); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.PWM(gpio.DutyHalf, 1000); err != nil {
+		t.Fatal(err)
+	}
+	if v := p.Read(); v != gpio.High {
+		t.Errorf("expected pin to be high, got %s", v)
+	}
+	events := p.Events()
+	if len(events) != 2 || events[1].Duty != gpio.DutyHalf {
+		t.Errorf("unexpected events %+v", events)
+	}
+
+	p.NoPWM = true
+	if err := p.PWM(gpio.DutyMax, 1000); !errors.Is(err, conntest.ErrNoPWM) {
+		t.Errorf("expected ErrNoPWM, got %v", err)
+	}
+}
+
+// Interface checks
+var (
+	_ display.Conn = (*conntest.Recorder)(nil)
+	_ display.SPI  = (*conntest.SPIRecorder)(nil)
+)
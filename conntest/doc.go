@@ -0,0 +1,7 @@
+// Package conntest provides fake connections and GPIO pins for testing display drivers without hardware.
+//
+// The [Recorder] and [SPIRecorder] implement the display.Conn and display.SPI interfaces respectively, and
+// record every call made by a driver, so init sequences and refresh traffic can be inspected in tests.
+//
+// The [I2CBus] simulates OLED controllers on an I²C bus, for testing display detection.
+package conntest
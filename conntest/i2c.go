@@ -0,0 +1,131 @@
+package conntest
+
+import (
+	"fmt"
+	"sync"
+
+	"periph.io/x/conn/v3/i2c"
+	"periph.io/x/conn/v3/physic"
+)
+
+// I2CBus is a mock I²C bus with simulated OLED controllers, for testing bus scans. Transactions with
+// addresses without a device fail, like a missing acknowledge on a real bus.
+type I2CBus struct {
+	mu      sync.Mutex
+	devices map[uint16]*I2CDevice
+}
+
+// NewI2CBus returns an empty bus.
+func NewI2CBus() *I2CBus {
+	return &I2CBus{devices: make(map[uint16]*I2CDevice)}
+}
+
+// Add a device at addr.
+func (b *I2CBus) Add(addr uint16, d *I2CDevice) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.devices[addr] = d
+}
+
+func (b *I2CBus) String() string {
+	return "mock I²C bus"
+}
+
+// Close does nothing, the bus may be reopened.
+func (b *I2CBus) Close() error {
+	return nil
+}
+
+// SetSpeed does nothing.
+func (b *I2CBus) SetSpeed(physic.Frequency) error {
+	return nil
+}
+
+// Tx writes w to and then reads r from the device at addr.
+func (b *I2CBus) Tx(addr uint16, w, r []byte) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	d, ok := b.devices[addr]
+	if !ok {
+		return fmt.Errorf("conntest: no device at I²C address %#02x", addr)
+	}
+	d.tx(w, r)
+	return nil
+}
+
+// I2CDevice simulates the I²C interface of a page addressed OLED controller, such as the SSD1306 and
+// SH1106. Only the page and column addressing commands are interpreted.
+type I2CDevice struct {
+	// Status is the byte returned by status reads.
+	Status byte
+
+	// Columns is the number of RAM columns.
+	Columns int
+
+	// ReadRAM enables reading the RAM, preceded by a dummy byte. Controllers that can't read the RAM over
+	// I²C, such as the SSD1306, return the status byte instead.
+	ReadRAM bool
+
+	ram          [8][]byte
+	page, column int
+}
+
+func (d *I2CDevice) tx(w, r []byte) {
+	if len(w) == 0 {
+		d.status(r)
+		return
+	}
+
+	if w[0]&0x40 == 0 {
+		for _, command := range w[1:] {
+			switch {
+			case command <= 0x0F:
+				d.column = d.column&0xF0 | int(command)
+			case command <= 0x1F:
+				d.column = d.column&0x0F | int(command&0x0F)<<4
+			case command >= 0xB0 && command <= 0xB7:
+				d.page = int(command & 7)
+			}
+		}
+		d.status(r)
+		return
+	}
+
+	if d.ram[d.page] == nil {
+		d.ram[d.page] = make([]byte, d.Columns)
+	}
+	for _, b := range w[1:] {
+		if d.column < d.Columns {
+			d.ram[d.page][d.column] = b
+		}
+		d.column++
+	}
+	if !d.ReadRAM {
+		d.status(r)
+		return
+	}
+	for i := range r {
+		if i == 0 {
+			r[i] = 0 // dummy read
+			continue
+		}
+		if d.column < d.Columns {
+			r[i] = d.ram[d.page][d.column]
+		} else {
+			r[i] = 0
+		}
+		d.column++
+	}
+}
+
+func (d *I2CDevice) status(r []byte) {
+	for i := range r {
+		r[i] = d.Status
+	}
+}
+
+// Interface checks
+var (
+	_ i2c.BusCloser = (*I2CBus)(nil)
+)
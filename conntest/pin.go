@@ -0,0 +1,159 @@
+package conntest
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"periph.io/x/conn/v3/gpio"
+	"periph.io/x/conn/v3/physic"
+)
+
+// ErrNoPWM is returned by [Pin.PWM] if hardware PWM is disabled.
+var ErrNoPWM = errors.New("conntest: pin does not support PWM")
+
+// PinEvent is a recorded pin change.
+type PinEvent struct {
+	// Time of the change.
+	Time time.Time
+
+	// Level of the pin.
+	Level gpio.Level
+
+	// Duty cycle, if the pin is driven by PWM.
+	Duty gpio.Duty
+
+	// Frequency of the PWM signal, zero if the pin is not driven by PWM.
+	Frequency physic.Frequency
+}
+
+// Pin is a fake GPIO pin that records all level changes.
+type Pin struct {
+	// N is the pin name.
+	N string
+
+	// NoPWM makes PWM calls fail with ErrNoPWM, to emulate pins without hardware PWM.
+	NoPWM bool
+
+	// Now returns the time for recorded events, defaults to [time.Now].
+	Now func() time.Time
+
+	mu     sync.Mutex
+	level  gpio.Level
+	pull   gpio.Pull
+	events []PinEvent
+}
+
+// NewPin returns a new fake pin.
+func NewPin(name string) *Pin {
+	return &Pin{N: name}
+}
+
+func (p *Pin) record(event PinEvent) {
+	if p.Now != nil {
+		event.Time = p.Now()
+	} else {
+		event.Time = time.Now()
+	}
+	p.events = append(p.events, event)
+}
+
+func (p *Pin) String() string {
+	return fmt.Sprintf("%s(fake)", p.N)
+}
+
+// Halt does nothing.
+func (p *Pin) Halt() error {
+	return nil
+}
+
+// Name of the pin.
+func (p *Pin) Name() string {
+	return p.N
+}
+
+// Number returns -1.
+func (p *Pin) Number() int {
+	return -1
+}
+
+// Function returns the current pin function.
+func (p *Pin) Function() string {
+	return "Out/" + p.Read().String()
+}
+
+// In sets the pull resistor, edge detection is not supported.
+func (p *Pin) In(pull gpio.Pull, _ gpio.Edge) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.pull = pull
+	return nil
+}
+
+// Read returns the current level.
+func (p *Pin) Read() gpio.Level {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.level
+}
+
+// WaitForEdge returns false after timeout.
+func (p *Pin) WaitForEdge(timeout time.Duration) bool {
+	if timeout > 0 {
+		time.Sleep(timeout)
+	}
+	return false
+}
+
+// Pull returns the pull resistor.
+func (p *Pin) Pull() gpio.Pull {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.pull
+}
+
+// DefaultPull returns gpio.Float.
+func (p *Pin) DefaultPull() gpio.Pull {
+	return gpio.Float
+}
+
+// Out sets the pin level.
+func (p *Pin) Out(level gpio.Level) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.level = level
+	p.record(PinEvent{Level: level})
+	return nil
+}
+
+// PWM records the duty cycle and frequency, unless NoPWM is set.
+func (p *Pin) PWM(duty gpio.Duty, f physic.Frequency) error {
+	if p.NoPWM {
+		return ErrNoPWM
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.level = duty > 0
+	p.record(PinEvent{Level: p.level, Duty: duty, Frequency: f})
+	return nil
+}
+
+// Events returns a copy of the recorded pin changes.
+func (p *Pin) Events() []PinEvent {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]PinEvent(nil), p.events...)
+}
+
+// Clear the recorded pin changes.
+func (p *Pin) Clear() {
+	p.mu.Lock()
+	p.events = nil
+	p.mu.Unlock()
+}
+
+// Interface checks
+var (
+	_ gpio.PinIO = (*Pin)(nil)
+)
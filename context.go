@@ -0,0 +1,183 @@
+package display
+
+import (
+	"context"
+	"image"
+
+	"periph.io/x/conn/v3/gpio"
+
+	"github.com/BeatGlow/display/conn"
+)
+
+// contextChunkSize is the maximum number of data bytes sent between context checks.
+const contextChunkSize = 1024
+
+// RefreshContext redraws the display, like [Display.Refresh]. The refresh is abandoned with ctx.Err() when
+// ctx is done, leaving the display partially redrawn.
+//
+// Drivers in this package check ctx before every command and every 1 kB of data. Other displays are only
+// checked before the refresh starts.
+func RefreshContext(ctx context.Context, d Display) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if b, ok := d.(contextBinder); ok {
+		defer b.bindContext(ctx)()
+	}
+	return d.Refresh()
+}
+
+// RefreshRectContext redraws the part of the display inside r, like [RefreshRect]. The refresh is
+// abandoned with ctx.Err() when ctx is done.
+func RefreshRectContext(ctx context.Context, d Display, r image.Rectangle) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if b, ok := d.(contextBinder); ok {
+		defer b.bindContext(ctx)()
+	}
+	return RefreshRect(d, r)
+}
+
+// CloseContext closes the display, like [Display.Close]. Switching off the display is abandoned when ctx
+// is done, the connection is closed regardless.
+func CloseContext(ctx context.Context, d Display) error {
+	if b, ok := d.(contextBinder); ok {
+		defer b.bindContext(ctx)()
+	}
+	return d.Close()
+}
+
+// OpenContext opens a display using the driver registered by name, like [Open]. Initialization is
+// abandoned with ctx.Err() when ctx is done; delays required by the controller are not interrupted, but
+// no bus traffic is sent after ctx is done.
+//
+// The context is only used during initialization.
+func OpenContext(ctx context.Context, name string, c Conn, config *Config) (Display, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	bound := withContext(c, ctx)
+	defer bound.setContext(nil)
+	return Open(name, bound, config)
+}
+
+// contextBinder is a Display that can bind a context to its connection.
+type contextBinder interface {
+	// bindContext checks ctx on all bus traffic, until the returned function is called.
+	bindContext(ctx context.Context) (restore func())
+}
+
+func (d *baseDisplay) bindContext(ctx context.Context) func() {
+	return bindConn(&d.c, ctx)
+}
+
+// bindConn binds ctx to the connection c points to, the returned function restores the connection.
+func bindConn(c *Conn, ctx context.Context) (restore func()) {
+	if bound, ok := (*c).(contextConnector); ok {
+		prev := bound.setContext(ctx)
+		return func() { bound.setContext(prev) }
+	}
+	prev := *c
+	*c = withContext(prev, ctx)
+	return func() { *c = prev }
+}
+
+// contextConnector is a connection that checks a context.
+type contextConnector interface {
+	Conn
+
+	// setContext replaces the context and returns the previous context, a nil context is never done.
+	setContext(ctx context.Context) context.Context
+}
+
+// withContext returns a connection that checks ctx before sending commands and data. SPI connections
+// remain SPI connections.
+func withContext(c Conn, ctx context.Context) contextConnector {
+	cc := &contextConn{Conn: c, ctx: ctx}
+	if spi, ok := c.(SPI); ok {
+		return &contextSPI{contextConn: cc, spi: spi}
+	}
+	return cc
+}
+
+// contextConn is a connection that returns the context error once its context is done.
+type contextConn struct {
+	Conn
+	ctx context.Context
+}
+
+func (c *contextConn) setContext(ctx context.Context) (prev context.Context) {
+	prev, c.ctx = c.ctx, ctx
+	return
+}
+
+func (c *contextConn) err() error {
+	if c.ctx == nil {
+		return nil
+	}
+	return c.ctx.Err()
+}
+
+// Reset sets the reset pin level, unless the context is done.
+func (c *contextConn) Reset(level gpio.Level) error {
+	if err := c.err(); err != nil {
+		return err
+	}
+	return c.Conn.Reset(level)
+}
+
+// Command sends a command, unless the context is done.
+func (c *contextConn) Command(command byte, args ...byte) error {
+	if err := c.err(); err != nil {
+		return err
+	}
+	return c.Conn.Command(command, args...)
+}
+
+// Data sends data in chunks, checking the context before every chunk. Without a context, data is sent in a
+// single call.
+func (c *contextConn) Data(data ...byte) error {
+	if c.ctx == nil {
+		return c.Conn.Data(data...)
+	}
+	for {
+		if err := c.err(); err != nil {
+			return err
+		}
+		if len(data) <= contextChunkSize {
+			return c.Conn.Data(data...)
+		}
+		if err := c.Conn.Data(data[:contextChunkSize]...); err != nil {
+			return err
+		}
+		data = data[contextChunkSize:]
+	}
+}
+
+// contextSPI is a SPI connection that returns the context error once its context is done.
+type contextSPI struct {
+	*contextConn
+	spi SPI
+}
+
+func (c *contextSPI) SetDataLow(v bool) {
+	c.spi.SetDataLow(v)
+}
+
+func (c *contextSPI) SetMode(mode conn.SPIMode) error {
+	return c.spi.SetMode(mode)
+}
+
+func (c *contextSPI) SetMaxSpeed(hz int) error {
+	return c.spi.SetMaxSpeed(hz)
+}
+
+// Interface checks
+var (
+	_ Conn          = (*contextConn)(nil)
+	_ SPI           = (*contextSPI)(nil)
+	_ contextBinder = (*baseDisplay)(nil)
+	_ contextBinder = (*gp1294)(nil)
+	_ contextBinder = (*damageTracker)(nil)
+)
@@ -0,0 +1,186 @@
+package display
+
+import (
+	"context"
+	"errors"
+	"image"
+	"testing"
+
+	"github.com/BeatGlow/display/conn"
+	"github.com/BeatGlow/display/conntest"
+)
+
+// cancelConn cancels a context after a number of data calls.
+type cancelConn struct {
+	*conntest.Recorder
+	cancel func()
+	after  int
+}
+
+func (c *cancelConn) Data(data ...byte) error {
+	if c.after--; c.after == 0 {
+		c.cancel()
+	}
+	return c.Recorder.Data(data...)
+}
+
+func TestRefreshContext(t *testing.T) {
+	tests := []struct {
+		Name      string
+		FrameSize int
+	}{
+		{"ssd1306", 128 * 64 / 8},
+		{"ssd1322", 256 * 64 / 2},
+		{"st7789", 240 * 240 * 2},
+		{"gu3000", 256 * 128 / 8},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(it *testing.T) {
+			c := &cancelConn{Recorder: conntest.NewRecorder()}
+			d, err := Open(test.Name, c, &Config{})
+			if err != nil {
+				it.Fatal(err)
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			c.Clear()
+			c.cancel, c.after = cancel, 1
+			if err = RefreshContext(ctx, d); !errors.Is(err, context.Canceled) {
+				it.Fatalf("expected %v, got %v", context.Canceled, err)
+			}
+			if n := c.DataLen(); n == 0 || n > contextChunkSize {
+				it.Errorf("expected refresh to stop after at most %d bytes, sent %d", contextChunkSize, n)
+			}
+
+			// The context is no longer checked after the refresh.
+			c.Clear()
+			if err = d.Refresh(); err != nil {
+				it.Fatal(err)
+			}
+			if n := c.DataLen(); n < test.FrameSize {
+				it.Errorf("expected refresh to send at least %d bytes, got %d", test.FrameSize, n)
+			}
+
+			c.Clear()
+			if err = RefreshRectContext(ctx, d, image.Rect(0, 0, 8, 8)); !errors.Is(err, context.Canceled) {
+				it.Fatalf("expected %v, got %v", context.Canceled, err)
+			}
+			if calls := c.Calls(); len(calls) != 0 {
+				it.Errorf("expected no traffic with a done context, got %d calls", len(calls))
+			}
+		})
+	}
+}
+
+func TestRefreshContextDamage(t *testing.T) {
+	c := conntest.NewRecorder()
+	d, err := Open("ssd1306", c, &Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	d = TrackDamage(d)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	if err = RefreshContext(ctx, d); err != nil {
+		t.Fatal(err)
+	}
+	cancel()
+
+	d.Set(0, 0, image.White)
+	c.Clear()
+	if err = RefreshContext(ctx, d); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected %v, got %v", context.Canceled, err)
+	}
+	if err = d.Refresh(); err != nil {
+		t.Fatal(err)
+	}
+	if n := c.DataLen(); n == 0 {
+		t.Error("expected the abandoned damage to be redrawn")
+	}
+}
+
+func TestOpenContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	c := conntest.NewSPIRecorder()
+	d, err := OpenContext(ctx, "ssd1322", c, &Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Mode != conn.SPIMode3 {
+		t.Errorf("expected SPI mode to be set through the context connection, got %d", c.Mode)
+	}
+
+	// The context is only used during initialization, data isn't split into chunks afterwards.
+	cancel()
+	c.Clear()
+	if err = d.Refresh(); err != nil {
+		t.Fatal(err)
+	}
+	plain := conntest.NewSPIRecorder()
+	p, err := Open("ssd1322", plain, &Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	plain.Clear()
+	if err = p.Refresh(); err != nil {
+		t.Fatal(err)
+	}
+	if n, want := len(c.Filter(conntest.OpData)), len(plain.Filter(conntest.OpData)); n != want {
+		t.Errorf("expected %d data calls after initialization, got %d", want, n)
+	}
+
+	if _, err = OpenContext(ctx, "ssd1322", conntest.NewSPIRecorder(), &Config{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected %v, got %v", context.Canceled, err)
+	}
+
+	c.Clear()
+	if err = CloseContext(ctx, d); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected %v, got %v", context.Canceled, err)
+	}
+	if calls := c.Filter(conntest.OpClose); len(calls) != 1 {
+		t.Errorf("expected the connection to be closed, got %d close calls", len(calls))
+	}
+	if calls := c.Filter(conntest.OpCommand); len(calls) != 0 {
+		t.Errorf("expected no commands, got %v", calls)
+	}
+}
+
+func TestOpenContextSPI(t *testing.T) {
+	// Drivers configure the bus through the context connection like through the connection itself.
+	for _, test := range []struct {
+		Driver string
+		Mode   conn.SPIMode
+		Speed  int
+	}{
+		{"gp1294", conn.SPIMode0, 500000},
+		{"ssd1322", conn.SPIMode3, 2500000},
+	} {
+		var (
+			plain = conntest.NewSPIRecorder()
+			bound = conntest.NewSPIRecorder()
+		)
+		if _, err := Open(test.Driver, plain, &Config{Backlight: conntest.NewPin("BL")}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := OpenContext(context.Background(), test.Driver, bound, &Config{Backlight: conntest.NewPin("BL")}); err != nil {
+			t.Fatal(err)
+		}
+		for _, c := range []*conntest.SPIRecorder{plain, bound} {
+			if c.Mode != test.Mode || c.MaxSpeed != test.Speed {
+				t.Errorf("%s: expected SPI mode %d at %d Hz, got mode %d at %d Hz", test.Driver, test.Mode, test.Speed, c.Mode, c.MaxSpeed)
+			}
+		}
+	}
+}
+
+func TestOpenContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	c := &cancelConn{Recorder: conntest.NewRecorder(), cancel: cancel, after: 1}
+	if _, err := OpenContext(ctx, "ssd1306", c, &Config{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected %v, got %v", context.Canceled, err)
+	}
+	if n := len(c.Filter(conntest.OpData)); n != 1 {
+		t.Errorf("expected initialization to stop after 1 data call, got %d", n)
+	}
+}
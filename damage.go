@@ -0,0 +1,191 @@
+package display
+
+import (
+	"bytes"
+	"context"
+	"image"
+
+	"github.com/BeatGlow/display/pixel"
+)
+
+// TrackDamage returns a Display that keeps a shadow copy of the last transmitted frame, so that Refresh only
+// redraws the parts of the display that have changed. Nothing is sent if the frame buffer is unchanged.
+//
+// Damage is tracked per page of 8 rows for [pixel.MonoVerticalLSBImage] frame buffers, and per row span for
+// other pixel buffers, such as [pixel.Gray4Image] and [pixel.CRGB16Image]. Displays that are not [Buffered]
+// are always redrawn completely.
+func TrackDamage(d Display) Display {
+	return &damageTracker{Display: d}
+}
+
+type damageTracker struct {
+	Display
+	shadow []byte
+}
+
+func (d *damageTracker) Refresh() error {
+	buffer, bits, vertical := frameBuffer(d.Display)
+	if buffer == nil {
+		return d.Display.Refresh()
+	}
+
+	if len(d.shadow) != len(buffer.Pix) {
+		// First frame, redraw everything.
+		if err := d.Display.Refresh(); err != nil {
+			return err
+		}
+		d.shadow = append(d.shadow[:0], buffer.Pix...)
+		return nil
+	}
+
+	var damaged []image.Rectangle
+	if vertical {
+		damaged = pageDamage(buffer, d.shadow)
+	} else {
+		damaged = rowDamage(buffer, d.shadow, bits)
+	}
+	if err := d.refreshDamaged(damaged); err != nil {
+		return err
+	}
+	copy(d.shadow, buffer.Pix)
+	return nil
+}
+
+// refreshDamaged redraws the damaged frame buffer rectangles, or the whole display once if it can't redraw
+// part of the display.
+func (d *damageTracker) refreshDamaged(damaged []image.Rectangle) error {
+	w, ok := d.Display.(WindowRefresher)
+	if !ok {
+		if len(damaged) == 0 {
+			return nil
+		}
+		return d.Display.Refresh()
+	}
+	mapper, rotated := As[bufferMapper](d.Display)
+	for _, r := range damaged {
+		if rotated {
+			r = mapper.displayRect(r)
+		}
+		if err := w.RefreshRect(r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// bufferMapper maps frame buffer coordinates to display coordinates.
+type bufferMapper interface {
+	displayRect(image.Rectangle) image.Rectangle
+}
+
+func (d *damageTracker) RefreshRect(r image.Rectangle) error {
+	return RefreshRect(d.Display, r)
+}
+
+// Unwrap returns the tracked display.
+func (d *damageTracker) Unwrap() Display {
+	return d.Display
+}
+
+func (d *damageTracker) bindContext(ctx context.Context) func() {
+	if b, ok := d.Display.(contextBinder); ok {
+		return b.bindContext(ctx)
+	}
+	return func() {}
+}
+
+// frameBuffer returns the pixel buffer of d, or of the display it wraps, the number of bits per pixel and if pixels are stored in vertical
+// pages.
+func frameBuffer(d Display) (buffer *pixel.Buffer, bits int, vertical bool) {
+	b, ok := As[Buffered](d)
+	if !ok {
+		return
+	}
+	switch i := b.FrameBuffer().(type) {
+	case *pixel.MonoVerticalLSBImage:
+		return &i.Buffer, 1, true
+	case *pixel.MonoImage:
+		return &i.Buffer, 1, false
+	case *pixel.Gray2Image:
+		return &i.Buffer, 2, false
+	case *pixel.Gray4Image:
+		return &i.Buffer, 4, false
+	case *pixel.CBGR15Image:
+		return &i.Buffer, 16, false
+	case *pixel.CBGR16Image:
+		return &i.Buffer, 16, false
+	case *pixel.CRGB15Image:
+		return &i.Buffer, 16, false
+	case *pixel.CRGB16Image:
+		return &i.Buffer, 16, false
+	}
+	return
+}
+
+// pageDamage returns the changed column span for each page of 8 rows, consecutive pages with the same span
+// are merged.
+func pageDamage(buffer *pixel.Buffer, shadow []byte) (damaged []image.Rectangle) {
+	var (
+		bounds = buffer.Rect
+		pages  = len(buffer.Pix) / buffer.Stride
+	)
+	for page := 0; page < pages; page++ {
+		off := page * buffer.Stride
+		x0, x1, ok := span(buffer.Pix[off:off+buffer.Stride], shadow[off:off+buffer.Stride])
+		if !ok {
+			continue
+		}
+		r := image.Rect(bounds.Min.X+x0, bounds.Min.Y+page*8, bounds.Min.X+x1, bounds.Min.Y+page*8+8).Intersect(bounds)
+		damaged = merge(damaged, r)
+	}
+	return
+}
+
+// rowDamage returns the changed pixel span for each row, consecutive rows with the same span are merged.
+func rowDamage(buffer *pixel.Buffer, shadow []byte, bits int) (damaged []image.Rectangle) {
+	bounds := buffer.Rect
+	for y := 0; y < bounds.Dy(); y++ {
+		off := y * buffer.Stride
+		b0, b1, ok := span(buffer.Pix[off:off+buffer.Stride], shadow[off:off+buffer.Stride])
+		if !ok {
+			continue
+		}
+		var (
+			x0 = b0 * 8 / bits
+			x1 = (b1*8 + bits - 1) / bits
+		)
+		r := image.Rect(bounds.Min.X+x0, bounds.Min.Y+y, bounds.Min.X+x1, bounds.Min.Y+y+1).Intersect(bounds)
+		damaged = merge(damaged, r)
+	}
+	return
+}
+
+// span returns the range of bytes in a that differ from b.
+func span(a, b []byte) (start, end int, changed bool) {
+	if bytes.Equal(a, b) {
+		return
+	}
+	for start = 0; a[start] == b[start]; start++ {
+	}
+	for end = len(a); a[end-1] == b[end-1]; end-- {
+	}
+	return start, end, true
+}
+
+// merge adds r to damaged, extending the last rectangle if it is directly above r and has the same width.
+func merge(damaged []image.Rectangle, r image.Rectangle) []image.Rectangle {
+	if l := len(damaged); l > 0 {
+		last := &damaged[l-1]
+		if last.Max.Y == r.Min.Y && last.Min.X == r.Min.X && last.Max.X == r.Max.X {
+			last.Max.Y = r.Max.Y
+			return damaged
+		}
+	}
+	return append(damaged, r)
+}
+
+// Interface checks
+var (
+	_ WindowRefresher = (*damageTracker)(nil)
+	_ Wrapper         = (*damageTracker)(nil)
+)
@@ -0,0 +1,144 @@
+package display
+
+import (
+	"image"
+	"image/color"
+	"reflect"
+	"testing"
+
+	"github.com/BeatGlow/display/draw"
+	"github.com/BeatGlow/display/pixel"
+)
+
+type testDisplay struct {
+	baseDisplay
+	refreshes int
+	rects     []image.Rectangle
+}
+
+func (d *testDisplay) String() string               { return "test" }
+func (d *testDisplay) Close() error                 { return nil }
+func (d *testDisplay) Show(bool) error              { return nil }
+func (d *testDisplay) SetContrast(uint8) error      { return nil }
+func (d *testDisplay) SetRotation(r Rotation) error { d.rotation = r; return nil }
+func (d *testDisplay) Refresh() error               { d.refreshes++; return nil }
+func (d *testDisplay) RefreshRect(r image.Rectangle) error {
+	d.rects = append(d.rects, r)
+	return nil
+}
+
+func TestTrackDamage(t *testing.T) {
+	tests := []struct {
+		Name  string
+		Image draw.Image
+		Draw  func(draw.Image)
+		Want  []image.Rectangle
+	}{
+		{
+			"mono/unchanged",
+			pixel.NewMonoVerticalLSBImage(128, 64),
+			func(draw.Image) {},
+			nil,
+		},
+		{
+			"mono/pixel",
+			pixel.NewMonoVerticalLSBImage(128, 64),
+			func(i draw.Image) { i.Set(10, 20, pixel.On) },
+			[]image.Rectangle{image.Rect(10, 16, 11, 24)},
+		},
+		{
+			"mono/pages",
+			pixel.NewMonoVerticalLSBImage(128, 64),
+			func(i draw.Image) {
+				for y := 4; y < 20; y++ {
+					i.Set(30, y, pixel.On)
+				}
+				i.Set(100, 63, pixel.On)
+			},
+			[]image.Rectangle{image.Rect(30, 0, 31, 24), image.Rect(100, 56, 101, 64)},
+		},
+		{
+			"gray4/span",
+			pixel.NewGray4Image(256, 64),
+			func(i draw.Image) {
+				i.Set(3, 1, pixel.Gray4{Y: 15})
+				i.Set(8, 1, pixel.Gray4{Y: 15})
+				i.Set(3, 2, pixel.Gray4{Y: 15})
+				i.Set(8, 2, pixel.Gray4{Y: 15})
+				i.Set(3, 3, pixel.Gray4{Y: 15})
+			},
+			[]image.Rectangle{image.Rect(2, 1, 10, 3), image.Rect(2, 3, 4, 4)},
+		},
+		{
+			"crgb16/span",
+			pixel.NewCRGB16Image(240, 240),
+			func(i draw.Image) {
+				i.Set(239, 239, color.White)
+			},
+			[]image.Rectangle{image.Rect(239, 239, 240, 240)},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(it *testing.T) {
+			d := &testDisplay{baseDisplay: baseDisplay{Image: test.Image}}
+			tracked := TrackDamage(d)
+
+			if err := tracked.Refresh(); err != nil {
+				it.Fatal(err)
+			}
+			if d.refreshes != 1 {
+				it.Fatalf("expected first refresh to redraw the whole display")
+			}
+
+			test.Draw(tracked)
+			if err := tracked.Refresh(); err != nil {
+				it.Fatal(err)
+			}
+			if !reflect.DeepEqual(d.rects, test.Want) {
+				it.Errorf("expected damage %v, got %v", test.Want, d.rects)
+			}
+
+			// Nothing changed since the last refresh.
+			d.rects = nil
+			if err := tracked.Refresh(); err != nil {
+				it.Fatal(err)
+			}
+			if len(d.rects) != 0 || d.refreshes != 1 {
+				it.Errorf("expected no redraw, got %v", d.rects)
+			}
+		})
+	}
+}
+
+// fullRefreshDisplay is a buffered display that can't redraw part of the display.
+type fullRefreshDisplay struct {
+	Buffered
+}
+
+func TestTrackDamageFullRefresh(t *testing.T) {
+	var (
+		d       = &testDisplay{baseDisplay: baseDisplay{Image: pixel.NewGray4Image(256, 64)}}
+		tracked = TrackDamage(fullRefreshDisplay{d})
+	)
+	if err := tracked.Refresh(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Several damaged rectangles are redrawn with a single refresh.
+	tracked.Set(3, 1, pixel.Gray4{Y: 15})
+	tracked.Set(100, 30, pixel.Gray4{Y: 15})
+	tracked.Set(200, 60, pixel.Gray4{Y: 15})
+	if err := tracked.Refresh(); err != nil {
+		t.Fatal(err)
+	}
+	if d.refreshes != 2 || len(d.rects) != 0 {
+		t.Errorf("expected 2 refreshes, got %d and rects %v", d.refreshes, d.rects)
+	}
+
+	if err := tracked.Refresh(); err != nil {
+		t.Fatal(err)
+	}
+	if d.refreshes != 2 {
+		t.Errorf("expected no redraw of an unchanged frame, got %d refreshes", d.refreshes)
+	}
+}
@@ -0,0 +1,132 @@
+package display
+
+import (
+	"fmt"
+
+	"github.com/BeatGlow/display/conn"
+)
+
+// I2CAddrs are the common I²C addresses of OLED display controllers.
+var I2CAddrs = []uint8{0x3C, 0x3D}
+
+// I²C control bytes.
+const (
+	i2cControlCommand = 0x00
+	i2cControlData    = 0x40
+)
+
+// probeMarker is written to the RAM to test if it can be read back.
+const probeMarker = 0xA5
+
+// Detection is a display controller found on an I²C bus.
+type Detection struct {
+	// I2C is the connection configuration, to pass to OpenI2C.
+	I2C I2CConfig
+
+	// Driver is the name of the suggested driver.
+	Driver string
+
+	// Config is the suggested display configuration, with the default size of the driver.
+	Config Config
+
+	// Status is the controller status byte.
+	Status byte
+
+	// Columns is the number of RAM columns, or zero if the RAM can't be read.
+	Columns int
+}
+
+func (d Detection) String() string {
+	return fmt.Sprintf("%s %dx%d at I²C address %#02x (status %#02x)", d.Driver, d.Config.Width, d.Config.Height, d.I2C.Addr, d.Status)
+}
+
+// DetectI2C scans I²C bus device at the common OLED addresses, use -1 to scan the first available bus.
+//
+// Probing writes to the display RAM, so the display contents may change.
+func DetectI2C(device int) (found []Detection, err error) {
+	for _, addr := range I2CAddrs {
+		var c *conn.I2C
+		if c, err = conn.OpenI2C(device, addr); err != nil {
+			return nil, err
+		}
+		d, probeErr := ProbeI2C(c)
+		if err = c.Close(); err != nil {
+			return nil, err
+		}
+		if probeErr != nil {
+			// Nothing responds at this address.
+			continue
+		}
+		d.I2C.Device = device
+		found = append(found, d)
+	}
+	return
+}
+
+// ProbeI2C identifies the display controller on an I²C connection. An error is returned if the device
+// doesn't respond.
+//
+// The controller is identified heuristically: the SH1106 status byte has 0x08 in the low nibble, and
+// controllers with 132 RAM columns (SH1106 and SSD1305) can read back the RAM beyond column 128 over I²C,
+// which the SSD1306 can't. Controllers that are not recognized are assumed to be a SSD1306.
+func ProbeI2C(c *conn.I2C) (d Detection, err error) {
+	var status [1]byte
+	if err = c.Tx(nil, status[:]); err != nil {
+		return d, fmt.Errorf("display: no response from I²C device: %w", err)
+	}
+	d.I2C.Addr = c.Addr()
+	d.Status = status[0]
+
+	if d.Columns, err = probeColumns(c); err != nil {
+		return
+	}
+
+	switch {
+	case d.Status&0x0F == 0x08:
+		d.Driver = "sh1106"
+	case d.Columns == 132:
+		d.Driver = "ssd1305"
+	default:
+		d.Driver = "ssd1306"
+	}
+	if driver, ok := Lookup(d.Driver); ok {
+		d.Config.Width, d.Config.Height = driver.Width, driver.Height
+	}
+	return
+}
+
+// probeColumns returns 132 if the last column of a 132 column RAM can be read back, or 0 otherwise.
+func probeColumns(c *conn.I2C) (columns int, err error) {
+	const column = 131
+	var (
+		address = []byte{
+			i2cControlCommand,
+			ssd1306SetPageAddr,
+			ssd1xxxSetHighColumn | column>>4,
+			ssd1xxxSetLowColumn | column&0x0F,
+		}
+		read [2]byte // dummy byte and RAM byte
+	)
+	if err = c.Tx(address, nil); err != nil {
+		return
+	}
+	if err = c.Tx([]byte{i2cControlData, probeMarker}, nil); err != nil {
+		return
+	}
+	if err = c.Tx(address, nil); err != nil {
+		return
+	}
+	if err = c.Tx([]byte{i2cControlData}, read[:]); err != nil {
+		return
+	}
+	if read[1] == probeMarker {
+		columns = 132
+	}
+
+	// Clear the marker.
+	if err = c.Tx(address, nil); err != nil {
+		return
+	}
+	err = c.Tx([]byte{i2cControlData, 0x00}, nil)
+	return
+}
@@ -0,0 +1,74 @@
+package display
+
+import (
+	"reflect"
+	"testing"
+
+	"periph.io/x/conn/v3/i2c"
+	"periph.io/x/conn/v3/i2c/i2creg"
+
+	"github.com/BeatGlow/display/conn"
+	"github.com/BeatGlow/display/conntest"
+)
+
+func TestProbeI2C(t *testing.T) {
+	tests := []struct {
+		Name    string
+		Device  *conntest.I2CDevice
+		Driver  string
+		Columns int
+		Width   int
+		Height  int
+	}{
+		{"ssd1306", &conntest.I2CDevice{Status: 0x43, Columns: 128}, "ssd1306", 0, 128, 64},
+		{"sh1106", &conntest.I2CDevice{Status: 0x08, Columns: 132, ReadRAM: true}, "sh1106", 132, 128, 64},
+		{"ssd1305", &conntest.I2CDevice{Status: 0x06, Columns: 132, ReadRAM: true}, "ssd1305", 132, 128, 32},
+		{"unknown", &conntest.I2CDevice{Status: 0x00, Columns: 128, ReadRAM: true}, "ssd1306", 0, 128, 64},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(it *testing.T) {
+			bus := conntest.NewI2CBus()
+			bus.Add(0x3C, test.Device)
+
+			d, err := ProbeI2C(conn.NewI2C(bus, 0x3C))
+			if err != nil {
+				it.Fatal(err)
+			}
+			want := Detection{
+				I2C:     I2CConfig{Addr: 0x3C},
+				Driver:  test.Driver,
+				Config:  Config{Width: test.Width, Height: test.Height},
+				Status:  test.Device.Status,
+				Columns: test.Columns,
+			}
+			if !reflect.DeepEqual(d, want) {
+				it.Errorf("expected %+v, got %+v", want, d)
+			}
+		})
+	}
+
+	if _, err := ProbeI2C(conn.NewI2C(conntest.NewI2CBus(), 0x3C)); err == nil {
+		t.Error("expected an error without a device")
+	}
+}
+
+func TestDetectI2C(t *testing.T) {
+	bus := conntest.NewI2CBus()
+	bus.Add(0x3D, &conntest.I2CDevice{Status: 0x08, Columns: 132, ReadRAM: true})
+	bus.Add(0x50, &conntest.I2CDevice{Status: 0x43, Columns: 128}) // not a display address
+	if err := i2creg.Register("detect-test", nil, 42, func() (i2c.BusCloser, error) { return bus, nil }); err != nil {
+		t.Fatal(err)
+	}
+	defer i2creg.Unregister("detect-test")
+
+	found, err := DetectI2C(42)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(found) != 1 {
+		t.Fatalf("expected 1 display, got %v", found)
+	}
+	if d := found[0]; d.Driver != "sh1106" || d.I2C != (I2CConfig{Device: 42, Addr: 0x3D}) {
+		t.Errorf("expected sh1106 at bus 42 address 0x3d, got %s on %+v", d, d.I2C)
+	}
+}
@@ -0,0 +1,573 @@
+package display
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Protocol is a display controller command protocol, used by the [Disassembler].
+type Protocol uint8
+
+// Supported protocols.
+const (
+	// ProtocolSSD1306 is the command stream protocol of the SSD1306 and SSD1305 controllers.
+	ProtocolSSD1306 Protocol = iota + 1
+
+	// ProtocolSH1106 is the command stream protocol of the SH1106 controller.
+	ProtocolSH1106
+
+	// ProtocolSH1122 is the command stream protocol of the SH1122 controller.
+	ProtocolSH1122
+
+	// ProtocolSSD1322 is the command protocol of the SSD1322 controller, arguments are sent as data.
+	ProtocolSSD1322
+
+	// ProtocolST7735 is the command protocol of the ST7735 controller, arguments are sent as data.
+	ProtocolST7735
+
+	// ProtocolST7789 is the command protocol of the ST7789 controller, arguments are sent as data.
+	ProtocolST7789
+
+	// ProtocolVFD is the bit reversed command protocol of the GP1294 and GP1287 controllers.
+	ProtocolVFD
+)
+
+var protocolNames = map[Protocol]string{
+	ProtocolSSD1306: "ssd1306",
+	ProtocolSH1106:  "sh1106",
+	ProtocolSH1122:  "sh1122",
+	ProtocolSSD1322: "ssd1322",
+	ProtocolST7735:  "st7735",
+	ProtocolST7789:  "st7789",
+	ProtocolVFD:     "vfd",
+}
+
+func (p Protocol) String() string {
+	if name, ok := protocolNames[p]; ok {
+		return name
+	}
+	return fmt.Sprintf("Protocol(%d)", p)
+}
+
+// ParseProtocol returns the protocol by protocol or driver name.
+func ParseProtocol(name string) (Protocol, error) {
+	switch strings.ToLower(name) {
+	case "ssd1306", "ssd1305", "ssd1xxx":
+		return ProtocolSSD1306, nil
+	case "sh1106":
+		return ProtocolSH1106, nil
+	case "sh1122":
+		return ProtocolSH1122, nil
+	case "ssd1322":
+		return ProtocolSSD1322, nil
+	case "st7735", "st7735r", "st7735s":
+		return ProtocolST7735, nil
+	case "st7789", "st7789v", "st77xx":
+		return ProtocolST7789, nil
+	case "vfd", "gp1294", "gp1287", "gp1278":
+		return ProtocolVFD, nil
+	default:
+		return 0, fmt.Errorf("display: unknown protocol %q", name)
+	}
+}
+
+// Instruction is a decoded command or data transfer.
+type Instruction struct {
+	// Command byte, for commands.
+	Command byte
+
+	// Name of the command, empty for data transfers.
+	Name string
+
+	// Args are the command arguments, or the data bytes for data transfers.
+	Args []byte
+
+	// Operands are the decoded arguments.
+	Operands string
+
+	// Data is set for data transfers.
+	Data bool
+}
+
+func (i Instruction) String() string {
+	switch {
+	case i.Data:
+		return fmt.Sprintf("data %d bytes", len(i.Args))
+	case i.Name == "":
+		return strings.TrimSpace(fmt.Sprintf("unknown 0x%02X % X", i.Command, i.Args))
+	case i.Operands != "":
+		return i.Name + " " + i.Operands
+	default:
+		return i.Name
+	}
+}
+
+// Bytes returns the raw bytes of the instruction.
+func (i Instruction) Bytes() []byte {
+	if i.Data {
+		return i.Args
+	}
+	return append([]byte{i.Command}, i.Args...)
+}
+
+// opcode describes a command.
+type opcode struct {
+	name string
+
+	// args is the number of argument bytes, for protocols where arguments are sent in the command stream.
+	args int
+
+	// format the arguments, defaults to hexadecimal bytes.
+	format func(command byte, args []byte) string
+}
+
+// formatHex formats arguments as hexadecimal bytes.
+func formatHex(_ byte, args []byte) string {
+	s := make([]string, len(args))
+	for i, arg := range args {
+		s[i] = fmt.Sprintf("0x%02X", arg)
+	}
+	return strings.Join(s, " ")
+}
+
+// formatRange formats 8-bit start and end addresses.
+func formatRange(_ byte, args []byte) string {
+	if len(args) < 2 {
+		return formatHex(0, args)
+	}
+	return fmt.Sprintf("%d..%d", args[0], args[1])
+}
+
+// formatRange16 formats 16-bit start and end addresses.
+func formatRange16(_ byte, args []byte) string {
+	if len(args) < 4 {
+		return formatHex(0, args)
+	}
+	return fmt.Sprintf("%d..%d", uint16(args[0])<<8|uint16(args[1]), uint16(args[2])<<8|uint16(args[3]))
+}
+
+// formatDecimal formats arguments as decimal values.
+func formatDecimal(_ byte, args []byte) string {
+	s := make([]string, len(args))
+	for i, arg := range args {
+		s[i] = fmt.Sprint(arg)
+	}
+	return strings.Join(s, " ")
+}
+
+// formatLength formats the number of data bytes.
+func formatLength(_ byte, args []byte) string {
+	return fmt.Sprintf("%d bytes", len(args))
+}
+
+// formatLowBits returns a formatter for the value encoded in the low bits of the command byte.
+func formatLowBits(mask byte) func(byte, []byte) string {
+	return func(command byte, _ []byte) string {
+		return fmt.Sprint(command & mask)
+	}
+}
+
+// formatMADCTL formats the memory data access control flags.
+func formatMADCTL(_ byte, args []byte) string {
+	if len(args) != 1 {
+		return formatHex(0, args)
+	}
+	var flags []string
+	for _, flag := range []struct {
+		bit  byte
+		name string
+	}{
+		{st7789PageAddressOrder, "MY"},
+		{st7789ColumnAddressOrder, "MX"},
+		{st7789PageColumnOrder, "MV"},
+		{st7789LineAddressOrder, "ML"},
+		{st7789RGBOrder, "BGR"},
+		{st7789DisplayDataLatchOrder, "MH"},
+	} {
+		if args[0]&flag.bit != 0 {
+			flags = append(flags, flag.name)
+		}
+	}
+	if len(flags) == 0 {
+		return fmt.Sprintf("0x%02X", args[0])
+	}
+	return fmt.Sprintf("0x%02X (%s)", args[0], strings.Join(flags, "|"))
+}
+
+// formatWriteGRAM formats the VFD graphics RAM write window and data length.
+func formatWriteGRAM(_ byte, args []byte) string {
+	if len(args) < 3 {
+		return formatHex(0, args)
+	}
+	return fmt.Sprintf("x=%d y=%d rows=%d, %d bytes", args[0], args[1], int(args[2])+1, len(args)-3)
+}
+
+var (
+	ssd1xxxOpcodes = map[byte]opcode{
+		ssd1xxxSetMemoryMode:                 {"SetMemoryMode", 1, nil},
+		ssd1xxxSetColumnAddr:                 {"SetColumnAddr", 2, formatRange},
+		ssd1xxxSetPageAddr:                   {"SetPageAddr", 2, formatRange},
+		ssd1306SetFadeBlink:                  {"SetFadeBlink", 1, nil},
+		ssd1xxxRightHorizontalScroll:         {"RightHorizontalScroll", 6, nil},
+		ssd1xxxLeftHorizontalScroll:          {"LeftHorizontalScroll", 6, nil},
+		ssd1xxxVerticalRightHorizontalScroll: {"VerticalRightHorizontalScroll", 5, nil},
+		ssd1xxxVerticalLeftHorizontalScroll:  {"VerticalLeftHorizontalScroll", 5, nil},
+		ssd1xxxDeactivateScroll:              {"DeactivateScroll", 0, nil},
+		ssd1xxxActivateScroll:                {"ActivateScroll", 0, nil},
+		ssd1xxxSetContrast:                   {"SetContrast", 1, nil},
+		ssd1xxxSetChargePump:                 {"SetChargePump", 1, nil},
+		ssd1305SetLUT:                        {"SetLUT", 4, nil},
+		ssd1xxxSetRemap:                      {"SetRemap", 0, nil},
+		ssd1xxxSetSegmentRemap:               {"SetSegmentRemap", 0, nil},
+		ssd1xxxSetVerticalScrollArea:         {"SetVerticalScrollArea", 2, nil},
+		ssd1xxxSetDisplayAllOnResume:         {"SetDisplayAllOnResume", 0, nil},
+		ssd1xxxSetDisplayAllOn:               {"SetDisplayAllOn", 0, nil},
+		ssd1xxxSetNormalDisplay:              {"SetNormalDisplay", 0, nil},
+		ssd1xxxSetInvertDisplay:              {"SetInvertDisplay", 0, nil},
+		ssd1xxxSetMultiplexRatio:             {"SetMultiplexRatio", 1, nil},
+		ssd1305SetMasterConfig:               {"SetMasterConfig", 1, nil},
+		ssd1xxxSetDisplayOff:                 {"SetDisplayOff", 0, nil},
+		ssd1xxxSetDisplayOn:                  {"SetDisplayOn", 0, nil},
+		ssd1xxxSetComScanInc:                 {"SetComScanInc", 0, nil},
+		ssd1xxxSetComScanDec:                 {"SetComScanDec", 0, nil},
+		ssd1xxxSetDisplayOffset:              {"SetDisplayOffset", 1, nil},
+		ssd1xxxSetDisplayClockDiv:            {"SetDisplayClockDiv", 1, nil},
+		ssd1306SetZoomIn:                     {"SetZoomIn", 1, nil},
+		ssd1305setAreaColor:                  {"SetAreaColor", 1, nil},
+		ssd1xxxSetPrecharge:                  {"SetPrecharge", 1, nil},
+		ssd1xxxSetComPins:                    {"SetComPins", 1, nil},
+		ssd1xxxSetVCOMDeselect:               {"SetVCOMDeselect", 1, nil},
+		ssd1xxxSetCommandLock:                {"SetCommandLock", 1, nil},
+	}
+
+	sh1122Opcodes = map[byte]opcode{
+		ssd1xxxSetContrast:           {"SetContrast", 1, nil},
+		sh1122SetDCDC:                {"SetDCDC", 1, nil},
+		ssd1xxxSetRemap:              {"SetRemap", 0, nil},
+		ssd1xxxSetSegmentRemap:       {"SetSegmentRemap", 0, nil},
+		ssd1xxxSetDisplayAllOnResume: {"SetDisplayAllOnResume", 0, nil},
+		ssd1xxxSetDisplayAllOn:       {"SetDisplayAllOn", 0, nil},
+		ssd1xxxSetNormalDisplay:      {"SetNormalDisplay", 0, nil},
+		ssd1xxxSetInvertDisplay:      {"SetInvertDisplay", 0, nil},
+		ssd1xxxSetMultiplexRatio:     {"SetMultiplexRatio", 1, nil},
+		ssd1xxxSetDisplayOff:         {"SetDisplayOff", 0, nil},
+		ssd1xxxSetDisplayOn:          {"SetDisplayOn", 0, nil},
+		sh1122SetRowAddress:          {"SetRowAddress", 1, formatDecimal},
+		ssd1xxxSetComScanInc:         {"SetComScanInc", 0, nil},
+		ssd1xxxSetComScanDec:         {"SetComScanDec", 0, nil},
+		ssd1xxxSetDisplayOffset:      {"SetDisplayOffset", 1, nil},
+		ssd1xxxSetDisplayClockDiv:    {"SetDisplayClockDiv", 1, nil},
+		ssd1xxxSetPrecharge:          {"SetPrecharge", 1, nil},
+		ssd1xxxSetVCOMDeselect:       {"SetVCOMDeselect", 1, nil},
+		sh1122SetVSEGMLevel:          {"SetVSEGMLevel", 1, nil},
+	}
+
+	ssd1322Opcodes = map[byte]opcode{
+		ssd1322EnableGrayScaleTable:   {"EnableGrayScaleTable", 0, nil},
+		ssd1322SetColumnAddress:       {"SetColumnAddress", 2, formatRange},
+		ssd1322WriteRAM:               {"WriteRAM", 0, formatLength},
+		ssd1322SetRowAddress:          {"SetRowAddress", 2, formatRange},
+		ssd1322SetRemap:               {"SetRemap", 2, nil},
+		ssd1322SetDisplayStartLine:    {"SetDisplayStartLine", 1, formatDecimal},
+		ssd1322SetDisplayOffset:       {"SetDisplayOffset", 1, formatDecimal},
+		ssd1322SetDisplayNormal:       {"SetDisplayNormal", 0, nil},
+		ssd1322SetDisplayAllOn:        {"SetDisplayAllOn", 0, nil},
+		ssd1322SetNormalDisplay:       {"SetNormalDisplay", 0, nil},
+		ssd1322SetInverseDIsplay:      {"SetInverseDisplay", 0, nil},
+		ssd1322SetExitPartialDisplay:  {"SetExitPartialDisplay", 0, nil},
+		ssd1322SetFunction:            {"SetFunction", 1, nil},
+		ssd1322SetDislpayOff:          {"SetDisplayOff", 0, nil},
+		ssd1322SetDisplayOn:           {"SetDisplayOn", 0, nil},
+		ssd1322SetPhaseLength:         {"SetPhaseLength", 1, nil},
+		ssd1322SetFrontClockDiv:       {"SetFrontClockDiv", 1, nil},
+		ssd1322SetDisplayEnhancementA: {"SetDisplayEnhancementA", 2, nil},
+		ssd1322SetGPIO:                {"SetGPIO", 1, nil},
+		ssd1322SetSecondPrecharge:     {"SetSecondPrecharge", 1, nil},
+		ssd1322SetGrayScaleTable:      {"SetGrayScaleTable", 15, nil},
+		ssd1322SetDefaultGrayscale:    {"SetDefaultGrayscale", 0, nil},
+		ssd1322SetPrechargeVoltage:    {"SetPrechargeVoltage", 1, nil},
+		ssd1322SetVCOMHVoltage:        {"SetVCOMHVoltage", 1, nil},
+		ssd1322SetContrast:            {"SetContrast", 1, nil},
+		ssd1322SetMasterCurrent:       {"SetMasterCurrent", 1, nil},
+		ssd1322SetMultiplexRatio:      {"SetMultiplexRatio", 1, nil},
+		ssd1322SetDisplayEnhancementB: {"SetDisplayEnhancementB", 2, nil},
+		ssd1322SetCommandLock:         {"SetCommandLock", 1, nil},
+	}
+
+	st7735Opcodes = map[byte]opcode{
+		st7735NOP:      {"NOP", 0, nil},
+		st7735SWRESET:  {"SWRESET", 0, nil},
+		st7735RDDID:    {"RDDID", 0, nil},
+		st7735RDDST:    {"RDDST", 0, nil},
+		st7735SLPIN:    {"SLPIN", 0, nil},
+		st7735SLPOUT:   {"SLPOUT", 0, nil},
+		st7735PTLON:    {"PTLON", 0, nil},
+		st7735NORON:    {"NORON", 0, nil},
+		st7735INVOFF:   {"INVOFF", 0, nil},
+		st7735INVON:    {"INVON", 0, nil},
+		st7735DISPOFF:  {"DISPOFF", 0, nil},
+		st7735DISPON:   {"DISPON", 0, nil},
+		st7735CASET:    {"CASET", 4, formatRange16},
+		st7735RASET:    {"RASET", 4, formatRange16},
+		st7735RAMWR:    {"RAMWR", 0, formatLength},
+		st7735RAMRD:    {"RAMRD", 0, nil},
+		st7735PTLAR:    {"PTLAR", 4, formatRange16},
+		st7735VSCRDEF:  {"VSCRDEF", 6, nil},
+		st7735MADCTL:   {"MADCTL", 1, formatMADCTL},
+		st7735VSCRSADD: {"VSCRSADD", 2, nil},
+		st7735COLMOD:   {"COLMOD", 1, nil},
+		st7735FRMCTR1:  {"FRMCTR1", 3, nil},
+		st7735FRMCTR2:  {"FRMCTR2", 3, nil},
+		st7735FRMCTR3:  {"FRMCTR3", 6, nil},
+		st7735INVCTR:   {"INVCTR", 1, nil},
+		st7735DISSET5:  {"DISSET5", 2, nil},
+		st7735PWCTR1:   {"PWCTR1", 3, nil},
+		st7735PWCTR2:   {"PWCTR2", 1, nil},
+		st7735PWCTR3:   {"PWCTR3", 2, nil},
+		st7735PWCTR4:   {"PWCTR4", 2, nil},
+		st7735PWCTR5:   {"PWCTR5", 2, nil},
+		st7735VMCTR1:   {"VMCTR1", 1, nil},
+		st7735RDID1:    {"RDID1", 0, nil},
+		st7735RDID2:    {"RDID2", 0, nil},
+		st7735RDID3:    {"RDID3", 0, nil},
+		st7735RDID4:    {"RDID4", 0, nil},
+		st7735GMCTRP1:  {"GMCTRP1", 16, nil},
+		st7735GMCTRN1:  {"GMCTRN1", 16, nil},
+		st7735PWCTR6:   {"PWCTR6", 2, nil},
+	}
+
+	st7789Opcodes = map[byte]opcode{
+		st7789NOP:       {"NOP", 0, nil},
+		st7789SWRESET:   {"SWRESET", 0, nil},
+		st7789RDDID:     {"RDDID", 0, nil},
+		st7789RDDST:     {"RDDST", 0, nil},
+		st7789RDDPM:     {"RDDPM", 0, nil},
+		st7789RDDMADCTL: {"RDDMADCTL", 0, nil},
+		st7789RDDCOLMOD: {"RDDCOLMOD", 0, nil},
+		st7789RDDIM:     {"RDDIM", 0, nil},
+		st7789RDDSM:     {"RDDSM", 0, nil},
+		st7789RDDSDR:    {"RDDSDR", 0, nil},
+		st7789SLPIN:     {"SLPIN", 0, nil},
+		st7789SLPOUT:    {"SLPOUT", 0, nil},
+		st7789PTLON:     {"PTLON", 0, nil},
+		st7789NORON:     {"NORON", 0, nil},
+		st7789INVOFF:    {"INVOFF", 0, nil},
+		st7789INVON:     {"INVON", 0, nil},
+		st7789GAMSET:    {"GAMSET", 1, nil},
+		st7789DISPOFF:   {"DISPOFF", 0, nil},
+		st7789DISPON:    {"DISPON", 0, nil},
+		st7789CASET:     {"CASET", 4, formatRange16},
+		st7789RASET:     {"RASET", 4, formatRange16},
+		st7789RAMWR:     {"RAMWR", 0, formatLength},
+		st7789RAMRD:     {"RAMRD", 0, nil},
+		st7789PTLAR:     {"PTLAR", 4, formatRange16},
+		st7789VSCRDEF:   {"VSCRDEF", 6, nil},
+		st7789TEOFF:     {"TEOFF", 0, nil},
+		st7789TEON:      {"TEON", 1, nil},
+		st7789MADCTL:    {"MADCTL", 1, formatMADCTL},
+		st7789VSCRSADD:  {"VSCRSADD", 2, nil},
+		st7789IDMOFF:    {"IDMOFF", 0, nil},
+		st7789IDMON:     {"IDMON", 0, nil},
+		st7789COLMOD:    {"COLMOD", 1, nil},
+		st7789RAMWRC:    {"RAMWRC", 0, formatLength},
+		st7789RAMRDC:    {"RAMRDC", 0, nil},
+		st7789TESCAN:    {"TESCAN", 2, nil},
+		st7789RDTESCAN:  {"RDTESCAN", 0, nil},
+		st7789WRDISBV:   {"WRDISBV", 1, nil},
+		st7789RDDISBV:   {"RDDISBV", 0, nil},
+		st7789WRCTRLD:   {"WRCTRLD", 1, nil},
+		st7789RDCTRLD:   {"RDCTRLD", 0, nil},
+		st7789WRCACE:    {"WRCACE", 1, nil},
+		st7789RDCABC:    {"RDCABC", 0, nil},
+		st7789WRCABCMB:  {"WRCABCMB", 1, nil},
+		st7789RDCABCMB:  {"RDCABCMB", 0, nil},
+		st7789RDABCSDR:  {"RDABCSDR", 0, nil},
+		st7789RDID1:     {"RDID1", 0, nil},
+		st7789RDID2:     {"RDID2", 0, nil},
+		st7789RDID3:     {"RDID3", 0, nil},
+		st7789RAMCTRL:   {"RAMCTRL", 2, nil},
+		st7789RGBCTRL:   {"RGBCTRL", 3, nil},
+		st7789PORCTRL:   {"PORCTRL", 5, nil},
+		st7789FRCTRL1:   {"FRCTRL1", 3, nil},
+		st7789GCTRL:     {"GCTRL", 1, nil},
+		st7789DGMEN:     {"DGMEN", 1, nil},
+		st7789VCOMS:     {"VCOMS", 1, nil},
+		st7789LCMCTRL:   {"LCMCTRL", 1, nil},
+		st7789IDSET:     {"IDSET", 3, nil},
+		st7789VDVVRHEN:  {"VDVVRHEN", 2, nil},
+		st7789VRHS:      {"VRHS", 1, nil},
+		st7789VDVSET:    {"VDVSET", 1, nil},
+		st7789VCMOFSET:  {"VCMOFSET", 1, nil},
+		st7789FRCTR2:    {"FRCTR2", 1, nil},
+		st7789CABCCTRL:  {"CABCCTRL", 1, nil},
+		st7789REGSEL1:   {"REGSEL1", 1, nil},
+		st7789REGSEL2:   {"REGSEL2", 1, nil},
+		st7789PWMFRSEL:  {"PWMFRSEL", 1, nil},
+		st7789PWCTRL1:   {"PWCTRL1", 2, nil},
+		st7789VAPVANEN:  {"VAPVANEN", 1, nil},
+		st7789PVGAMCTRL: {"PVGAMCTRL", 14, nil},
+		st7789NVGAMCTRL: {"NVGAMCTRL", 14, nil},
+		st7789DGMLUTR:   {"DGMLUTR", 64, nil},
+		st7789DGMLUTB:   {"DGMLUTB", 64, nil},
+		st7789GATECTRL:  {"GATECTRL", 3, nil},
+		st7789PWCTRL2:   {"PWCTRL2", 1, nil},
+		st7789EQCTRL:    {"EQCTRL", 3, nil},
+		st7789PROMCTRL:  {"PROMCTRL", 1, nil},
+		st7789PROMEN:    {"PROMEN", 4, nil},
+		st7789NVMSET:    {"NVMSET", 2, nil},
+		st7789PROMACT:   {"PROMACT", 2, nil},
+	}
+
+	vfdOpcodes = map[byte]opcode{
+		gp1294FrameSync:        {"FrameSync", 0, nil},
+		gp1278ClearGRAM:        {"ClearGRAM", 0, nil},
+		gp1294DisplayOff:       {"DisplayOff", 0, nil},
+		gp127WakeUp:            {"WakeUp", 0, nil},
+		gp1294DisplayOn:        {"DisplayOn", 0, nil},
+		gp1294OscSetting:       {"OscSetting", 1, nil},
+		gp1294DisplayMode:      {"DisplayMode", 1, nil},
+		gp1294Brightness:       {"Brightness", 2, nil},
+		gp1294Reset:            {"Reset", 0, nil},
+		gp1278SetInternalSpeed: {"SetInternalSpeed", 4, nil},
+		gp1294DisplayOffset:    {"DisplayOffset", 2, nil},
+		gp1294VFDMode:          {"VFDMode", 0, nil},
+		gp1278SetDisplayArea:   {"SetDisplayArea", 7, nil},
+		gp1294WriteGRAM:        {"WriteGRAM", 3, formatWriteGRAM},
+	}
+)
+
+// lookup returns the opcode for a command of the protocol.
+func (p Protocol) lookup(command byte) (opcode, bool) {
+	switch p {
+	case ProtocolSSD1306, ProtocolSH1106:
+		switch {
+		case command <= 0x0F:
+			return opcode{"SetLowColumn", 0, formatLowBits(0x0F)}, true
+		case command <= 0x1F:
+			return opcode{"SetHighColumn", 0, formatLowBits(0x0F)}, true
+		case command >= ssd1xxxSetStartLine && command <= 0x7F:
+			return opcode{"SetStartLine", 0, formatLowBits(0x3F)}, true
+		case command >= ssd1306SetPageAddr && command <= ssd1306SetPageAddr|0x07:
+			return opcode{"SetPage", 0, formatLowBits(0x07)}, true
+		case p == ProtocolSH1106 && command >= ssd1xxxSetMemoryMode && command <= ssd1xxxSetPageAddr:
+			// Not supported by the SH1106, these commands don't take arguments.
+			return opcode{}, false
+		}
+		op, ok := ssd1xxxOpcodes[command]
+		return op, ok
+	case ProtocolSH1122:
+		switch {
+		case command <= 0x0F:
+			return opcode{"SetLowColumn", 0, formatLowBits(0x0F)}, true
+		case command <= 0x17:
+			return opcode{"SetHighColumn", 0, formatLowBits(0x07)}, true
+		case command >= sh1122SetDischargeVSLLevel && command <= 0x3F:
+			return opcode{"SetDischargeVSLLevel", 0, formatLowBits(0x0F)}, true
+		case command >= sh1122SetDisplayStartLine && command <= 0x7F:
+			return opcode{"SetDisplayStartLine", 0, formatLowBits(0x3F)}, true
+		}
+		op, ok := sh1122Opcodes[command]
+		return op, ok
+	case ProtocolSSD1322:
+		op, ok := ssd1322Opcodes[command]
+		return op, ok
+	case ProtocolST7735:
+		op, ok := st7735Opcodes[command]
+		return op, ok
+	case ProtocolST7789:
+		op, ok := st7789Opcodes[command]
+		return op, ok
+	case ProtocolVFD:
+		op, ok := vfdOpcodes[command]
+		return op, ok
+	default:
+		return opcode{}, false
+	}
+}
+
+// stream returns if all command arguments are sent as command bytes, and multiple commands may be sent
+// in a single transfer.
+func (p Protocol) stream() bool {
+	return p == ProtocolSSD1306 || p == ProtocolSH1106 || p == ProtocolSH1122
+}
+
+// Disassembler decodes display controller bus traffic into instructions.
+//
+// Commands are decoded as they would be by the controller: for the SSD1306, SH1106 and SH1122 protocols
+// all bytes of a command transfer are interpreted as a stream of commands and their arguments, for the
+// other protocols each command transfer contains a single command, and command arguments may follow as
+// data. Instructions are returned once they are complete, call [Disassembler.Flush] at the end of the
+// traffic to decode the last instruction.
+type Disassembler struct {
+	protocol Protocol
+	pending  *Instruction
+	need     int
+}
+
+// NewDisassembler returns a disassembler for the protocol.
+func NewDisassembler(protocol Protocol) *Disassembler {
+	return &Disassembler{protocol: protocol}
+}
+
+// Command decodes a command transfer.
+func (d *Disassembler) Command(command byte, args ...byte) []Instruction {
+	if d.protocol == ProtocolVFD {
+		command, args = rev8tab[command], reverse(args)
+	}
+	if !d.protocol.stream() {
+		out := d.Flush()
+		d.pending = d.instruction(command, args)
+		return out
+	}
+
+	var out []Instruction
+	for _, b := range append([]byte{command}, args...) {
+		if d.pending == nil {
+			d.pending = d.instruction(b, nil)
+			op, _ := d.protocol.lookup(b)
+			d.need = op.args
+		} else {
+			d.pending.Args = append(d.pending.Args, b)
+			d.need--
+		}
+		if d.need == 0 {
+			out = append(out, d.Flush()...)
+		}
+	}
+	return out
+}
+
+// Data decodes a data transfer, data may contain the arguments of the last command.
+func (d *Disassembler) Data(data ...byte) []Instruction {
+	if d.protocol == ProtocolVFD {
+		data = reverse(data)
+	}
+	if d.pending != nil && !d.protocol.stream() {
+		d.pending.Args = append(d.pending.Args, data...)
+		return nil
+	}
+	out := d.Flush()
+	return append(out, Instruction{Args: append([]byte(nil), data...), Data: true})
+}
+
+// Flush returns the pending instruction, if any.
+func (d *Disassembler) Flush() []Instruction {
+	if d.pending == nil {
+		return nil
+	}
+	i := *d.pending
+	d.pending, d.need = nil, 0
+	if op, ok := d.protocol.lookup(i.Command); ok {
+		i.Name = op.name
+		if op.format != nil {
+			i.Operands = op.format(i.Command, i.Args)
+		} else {
+			i.Operands = formatHex(i.Command, i.Args)
+		}
+	}
+	return []Instruction{i}
+}
+
+func (d *Disassembler) instruction(command byte, args []byte) *Instruction {
+	return &Instruction{Command: command, Args: append([]byte(nil), args...)}
+}
@@ -0,0 +1,134 @@
+package display
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/BeatGlow/display/conntest"
+)
+
+func TestDisassembler(t *testing.T) {
+	tests := []struct {
+		Driver   string
+		Protocol Protocol
+		Want     []string
+	}{
+		{"ssd1306", ProtocolSSD1306, []string{
+			"SetDisplayOff",
+			"SetDisplayClockDiv 0x80",
+			"SetMultiplexRatio 0x3F",
+			"SetStartLine 0",
+			"SetContrast 0xCF",
+			"SetColumnAddr 0..127",
+			"SetPageAddr 0..7",
+			"data 128 bytes",
+			"SetDisplayOn",
+		}},
+		{"sh1106", ProtocolSH1106, []string{
+			"unknown 0x20",
+			"SetHighColumn 0",
+			"SetPage 7",
+			"SetLowColumn 2",
+			"data 128 bytes",
+		}},
+		{"sh1122", ProtocolSH1122, []string{
+			"SetRowAddress 0",
+			"SetDischargeVSLLevel 0",
+			"data 4096 bytes",
+		}},
+		{"ssd1322", ProtocolSSD1322, []string{
+			"SetCommandLock 0x12",
+			"SetMultiplexRatio 0x3F",
+			"SetRemap 0x14 0x11",
+			"SetRowAddress 0..63",
+			"SetColumnAddress 28..91",
+			"WriteRAM 8192 bytes",
+		}},
+		{"st7789", ProtocolST7789, []string{
+			"SLPOUT",
+			"MADCTL 0x00",
+			"COLMOD 0x05",
+			"PORCTRL 0x0C 0x0C",
+			"DISPON",
+		}},
+		{"gp1294", ProtocolVFD, []string{
+			"Reset",
+			"Brightness 0x28 0x00",
+			"WriteGRAM x=0 y=0 rows=48, 1536 bytes",
+			"DisplayOn",
+		}},
+	}
+	for _, test := range tests {
+		t.Run(test.Driver, func(it *testing.T) {
+			c := conntest.NewSPIRecorder()
+			if _, err := Open(test.Driver, c, &Config{Backlight: conntest.NewPin("BL")}); err != nil {
+				it.Fatal(err)
+			}
+
+			var (
+				d     = NewDisassembler(test.Protocol)
+				lines []string
+			)
+			for _, call := range c.Filter(conntest.OpCommand, conntest.OpData) {
+				var out []Instruction
+				if call.Op == conntest.OpCommand {
+					out = d.Command(call.Command, call.Data...)
+				} else {
+					out = d.Data(call.Data...)
+				}
+				for _, i := range out {
+					lines = append(lines, i.String())
+				}
+			}
+			for _, i := range d.Flush() {
+				lines = append(lines, i.String())
+			}
+
+			for _, want := range test.Want {
+				var found bool
+				for _, line := range lines {
+					if found = line == want; found {
+						break
+					}
+				}
+				if !found {
+					it.Errorf("expected %q in:\n%s", want, strings.Join(lines, "\n"))
+				}
+			}
+		})
+	}
+}
+
+func TestDisassemblerStream(t *testing.T) {
+	d := NewDisassembler(ProtocolSSD1306)
+
+	// Arguments may span multiple transfers.
+	if out := d.Command(ssd1xxxSetDisplayOff, ssd1xxxSetColumnAddr, 0x00); len(out) != 1 {
+		t.Fatalf("expected 1 instruction, got %d", len(out))
+	}
+	out := d.Command(0x7F, ssd1xxxSetMultiplexRatio, 0x1F)
+	if len(out) != 2 {
+		t.Fatalf("expected 2 instructions, got %d", len(out))
+	}
+	if v := out[0].String(); v != "SetColumnAddr 0..127" {
+		t.Errorf("expected SetColumnAddr 0..127, got %q", v)
+	}
+	if v := out[1].String(); v != "SetMultiplexRatio 0x1F" {
+		t.Errorf("expected SetMultiplexRatio 0x1F, got %q", v)
+	}
+}
+
+func TestParseProtocol(t *testing.T) {
+	for _, name := range []string{"ssd1306", "sh1106", "sh1122", "ssd1322", "st7735", "st7789", "vfd"} {
+		p, err := ParseProtocol(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v := p.String(); v != name {
+			t.Errorf("expected %q, got %q", name, v)
+		}
+	}
+	if _, err := ParseProtocol("test"); err == nil {
+		t.Error("expected error")
+	}
+}
@@ -3,6 +3,7 @@ package display
 
 import (
 	"errors"
+	"image"
 	"image/color"
 	"image/draw"
 
@@ -15,6 +16,11 @@ import (
 var (
 	ErrBounds   = errors.New("display: out of bounds")
 	ErrNotReady = errors.New("display: ready timeout")
+
+	ErrGrayscaleTable = errors.New("display: invalid grayscale table")
+
+	ErrUnknownDriver = errors.New("display: unknown driver")
+
 	ErrResetPin = InvalidPin{"reset"}
 	ErrDCPin    = InvalidPin{"data/command (DC)"}
 )
@@ -72,6 +78,63 @@ type Display interface {
 	Refresh() error
 }
 
+// WindowRefresher is a Display that can redraw part of the display.
+type WindowRefresher interface {
+	Display
+
+	// RefreshRect redraws the part of the display inside r.
+	//
+	// Drivers may redraw a slightly larger area if the controller's window
+	// addressing is coarser than a single pixel.
+	RefreshRect(r image.Rectangle) error
+}
+
+// RefreshRect redraws the part of the display inside r, if the display supports it.
+// Otherwise the whole display is redrawn.
+func RefreshRect(d Display, r image.Rectangle) error {
+	if w, ok := d.(WindowRefresher); ok {
+		return w.RefreshRect(r)
+	}
+	return d.Refresh()
+}
+
+// Buffered is a Display that draws into an in-memory frame buffer, which is transmitted on Refresh.
+type Buffered interface {
+	Display
+
+	// FrameBuffer returns the frame buffer, in the layout used by the controller.
+	FrameBuffer() draw.Image
+}
+
+// Wrapper is a Display that adds behaviour to another display, such as the displays returned by
+// [TrackDamage], [ProtectBurnIn] and [NewPowerManager].
+type Wrapper interface {
+	Display
+
+	// Unwrap returns the wrapped display.
+	Unwrap() Display
+}
+
+// As returns d as a T if it is one, or else the first display wrapped by d that is a T. Optional interfaces
+// of displays are hidden by wrappers, use As to find them:
+//
+//	if s, ok := display.As[display.Sleeper](d); ok {
+//		err = s.SetSleep(true)
+//	}
+func As[T any](d Display) (t T, ok bool) {
+	for d != nil {
+		if t, ok = d.(T); ok {
+			return
+		}
+		w, wrapped := d.(Wrapper)
+		if !wrapped {
+			break
+		}
+		d = w.Unwrap()
+	}
+	return
+}
+
 // Config is the display configuration.
 type Config struct {
 	// Width of the display in pixels.
@@ -81,6 +144,9 @@ type Config struct {
 	Height int
 
 	// Rotation of the display.
+	//
+	// Displays that rotate in software expect Width and Height to be the native size of the panel, their
+	// bounds are swapped when rotated by 90° or 270°.
 	Rotation Rotation
 
 	// UseMono sets 1-color monochrome mode on displays that support grayscale.
@@ -101,6 +167,54 @@ type baseDisplay struct {
 	colOffset int
 	rowOffset int
 	rotation  Rotation
+	transform Rotation // rotation applied in software
+}
+
+// Bounds of the display, taking the software rotation into account.
+func (d *baseDisplay) Bounds() image.Rectangle {
+	b := d.Image.Bounds()
+	if d.transform == Rotate90 || d.transform == Rotate270 {
+		return image.Rect(0, 0, b.Dy(), b.Dx())
+	}
+	return b
+}
+
+func (d *baseDisplay) At(x, y int) color.Color {
+	if d.transform != NoRotation {
+		b := d.Image.Bounds()
+		x, y = rotatePoint(d.transform, x, y, b.Dx(), b.Dy())
+	}
+	return d.Image.At(x, y)
+}
+
+func (d *baseDisplay) Set(x, y int, c color.Color) {
+	if d.transform != NoRotation {
+		b := d.Image.Bounds()
+		x, y = rotatePoint(d.transform, x, y, b.Dx(), b.Dy())
+	}
+	d.Image.Set(x, y, c)
+}
+
+// bufferRect maps r on the display to the frame buffer, clipped to the frame buffer bounds.
+func (d *baseDisplay) bufferRect(r image.Rectangle) image.Rectangle {
+	b := d.Image.Bounds()
+	return rotateRect(d.transform, r, b.Dx(), b.Dy()).Intersect(b)
+}
+
+// displayRect maps r in the frame buffer to the display, it is the inverse of bufferRect.
+func (d *baseDisplay) displayRect(r image.Rectangle) image.Rectangle {
+	b := d.Bounds()
+	return rotateRect((4-d.transform)&3, r, b.Dx(), b.Dy()).Intersect(b)
+}
+
+// setSoftwareRotation rotates the display by mapping pixel coordinates onto the frame buffer.
+func (d *baseDisplay) setSoftwareRotation(rotation Rotation) {
+	d.rotation = rotation & 3
+	d.transform = rotation & 3
+}
+
+func (d *baseDisplay) FrameBuffer() draw.Image {
+	return d.Image
 }
 
 func (d *baseDisplay) data(data ...byte) error {
@@ -145,3 +259,37 @@ func (d *baseDisplay) Fill(c color.Color) {
 		}
 	}
 }
+
+// rotatePoint maps (x, y) on a display with rotation r to (x, y) in the w×h unrotated frame buffer.
+func rotatePoint(r Rotation, x, y, w, h int) (int, int) {
+	switch r & 3 {
+	case Rotate90:
+		return w - 1 - y, x
+	case Rotate180:
+		return w - 1 - x, h - 1 - y
+	case Rotate270:
+		return y, h - 1 - x
+	default:
+		return x, y
+	}
+}
+
+// rotateRect maps rect on a display with rotation r to the w×h unrotated frame buffer.
+func rotateRect(r Rotation, rect image.Rectangle, w, h int) image.Rectangle {
+	if r&3 == NoRotation || rect.Empty() {
+		return rect
+	}
+	var (
+		x0, y0 = rotatePoint(r, rect.Min.X, rect.Min.Y, w, h)
+		x1, y1 = rotatePoint(r, rect.Max.X-1, rect.Max.Y-1, w, h)
+	)
+	return image.Rect(min(x0, x1), min(y0, y1), max(x0, x1)+1, max(y0, y1)+1)
+}
+
+// abs returns the absolute value of v.
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -40,7 +40,22 @@ func (d *monoDisplay) SetContrast(level uint8) error {
 	return d.command(ssd1xxxSetContrast, level)
 }
 
+// SetRotation rotates the display. Rotating by 180° is done by the controller, by reversing the segment
+// remap and COM scan direction, other rotations are done in software.
+//
+// The segment remap only applies to data written after changing the rotation, so the display has to be
+// redrawn after calling SetRotation.
 func (d *monoDisplay) SetRotation(rotation Rotation) error {
+	rotation &= 3
+	remap, comScan := ssd1xxxFlip(rotation == Rotate180)
+	if err := d.command(remap, comScan); err != nil {
+		return err
+	}
+	if rotation == Rotate180 {
+		d.setSoftwareRotation(NoRotation)
+	} else {
+		d.setSoftwareRotation(rotation)
+	}
 	d.rotation = rotation
 	return nil
 }
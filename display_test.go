@@ -0,0 +1,91 @@
+package display
+
+import (
+	"image"
+	"testing"
+
+	"github.com/BeatGlow/display/pixel"
+)
+
+func TestRotatePoint(t *testing.T) {
+	const w, h = 8, 4
+	tests := []struct {
+		Rotation Rotation
+		In, Want image.Point
+	}{
+		{NoRotation, image.Pt(1, 2), image.Pt(1, 2)},
+		{Rotate90, image.Pt(0, 0), image.Pt(w-1, 0)},
+		{Rotate90, image.Pt(h-1, w-1), image.Pt(0, h-1)},
+		{Rotate180, image.Pt(0, 0), image.Pt(w-1, h-1)},
+		{Rotate270, image.Pt(0, 0), image.Pt(0, h-1)},
+		{Rotate270, image.Pt(h-1, w-1), image.Pt(w-1, 0)},
+	}
+	for _, test := range tests {
+		x, y := rotatePoint(test.Rotation, test.In.X, test.In.Y, w, h)
+		if v := image.Pt(x, y); !v.Eq(test.Want) {
+			t.Errorf("%s: expected %s to map to %s, got %s", test.Rotation, test.In, test.Want, v)
+		}
+	}
+}
+
+func TestSoftwareRotation(t *testing.T) {
+	for _, rotation := range []Rotation{NoRotation, Rotate90, Rotate180, Rotate270} {
+		t.Run(rotation.String(), func(it *testing.T) {
+			d := &testDisplay{baseDisplay: baseDisplay{Image: pixel.NewMonoVerticalLSBImage(128, 64)}}
+			d.setSoftwareRotation(rotation)
+
+			bounds := d.Bounds()
+			if rotation == Rotate90 || rotation == Rotate270 {
+				if !bounds.Size().Eq(image.Pt(64, 128)) {
+					it.Fatalf("expected rotated bounds, got %s", bounds)
+				}
+			} else if !bounds.Size().Eq(image.Pt(128, 64)) {
+				it.Fatalf("expected native bounds, got %s", bounds)
+			}
+
+			d.Set(5, 10, pixel.On)
+			if v := d.At(5, 10); v != pixel.On {
+				it.Errorf("expected pixel to be on after Set, got %v", v)
+			}
+
+			var on int
+			buffer := d.FrameBuffer()
+			for y := 0; y < 64; y++ {
+				for x := 0; x < 128; x++ {
+					if buffer.At(x, y) == pixel.On {
+						on++
+					}
+				}
+			}
+			if on != 1 {
+				it.Errorf("expected 1 pixel in frame buffer, got %d", on)
+			}
+
+			r := image.Rect(3, 9, 20, 11)
+			b := d.bufferRect(r)
+			if b.Dx()*b.Dy() != r.Dx()*r.Dy() {
+				it.Errorf("expected area of %s to be preserved, got %s", r, b)
+			}
+			if v := d.displayRect(b); !v.Eq(r) {
+				it.Errorf("expected %s to map back to %s, got %s", b, r, v)
+			}
+		})
+	}
+}
+
+func TestTrackDamageRotated(t *testing.T) {
+	d := &testDisplay{baseDisplay: baseDisplay{Image: pixel.NewMonoVerticalLSBImage(128, 64)}}
+	d.setSoftwareRotation(Rotate90)
+	tracked := TrackDamage(d)
+	if err := tracked.Refresh(); err != nil {
+		t.Fatal(err)
+	}
+
+	tracked.Set(10, 100, pixel.On)
+	if err := tracked.Refresh(); err != nil {
+		t.Fatal(err)
+	}
+	if len(d.rects) != 1 || !image.Pt(10, 100).In(d.rects[0]) {
+		t.Errorf("expected damage around (10,100), got %v", d.rects)
+	}
+}
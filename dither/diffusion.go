@@ -0,0 +1,133 @@
+package dither
+
+import (
+	"image"
+	"image/draw"
+)
+
+// Kernel is an error diffusion kernel.
+type Kernel struct {
+	// Weights are the fractions of the quantization error passed to neighbouring pixels, by row starting
+	// with the row of the current pixel. Only weights after the current pixel in the first row are used.
+	Weights [][]int
+
+	// X is the column of the current pixel in Weights.
+	X int
+
+	// Divisor is the denominator of the weights.
+	Divisor int
+}
+
+// Standard error diffusion kernels.
+var (
+	// FloydSteinbergKernel passes the whole error to four neighbours.
+	FloydSteinbergKernel = Kernel{
+		Weights: [][]int{
+			{0, 0, 7},
+			{3, 5, 1},
+		},
+		X:       1,
+		Divisor: 16,
+	}
+
+	// AtkinsonKernel passes three quarters of the error to six neighbours, which gives more contrast
+	// at the expense of detail in very light and dark areas.
+	AtkinsonKernel = Kernel{
+		Weights: [][]int{
+			{0, 0, 1, 1},
+			{1, 1, 1, 0},
+			{0, 1, 0, 0},
+		},
+		X:       1,
+		Divisor: 8,
+	}
+
+	// SierraKernel passes the whole error to ten neighbours over three rows.
+	SierraKernel = Kernel{
+		Weights: [][]int{
+			{0, 0, 0, 5, 3},
+			{2, 4, 5, 4, 2},
+			{0, 2, 3, 2, 0},
+		},
+		X:       2,
+		Divisor: 32,
+	}
+)
+
+// ErrorDiffusion is a drawer that passes the quantization error of each pixel on to its neighbours.
+type ErrorDiffusion struct {
+	// Kernel is the error diffusion kernel. Without weights, the pixels are quantized without diffusion.
+	Kernel Kernel
+
+	// Serpentine scans every other row from right to left, with the kernel mirrored, which avoids the
+	// diagonal artifacts of scanning in one direction.
+	Serpentine bool
+}
+
+// Draw aligns r.Min in dst with sp in src and then replaces the rectangle r in dst with the dithered
+// source.
+func (d ErrorDiffusion) Draw(dst draw.Image, r image.Rectangle, src image.Image, sp image.Point) {
+	r, sp, colors, ok := prepare(dst, r, src, sp)
+	if !ok {
+		return
+	}
+
+	var (
+		k      = d.Kernel
+		levels = int32(len(colors) - 1)
+		width  = r.Dx()
+		pad    int
+	)
+	if len(k.Weights) == 0 || k.Divisor == 0 {
+		k = Kernel{Weights: [][]int{{0}}, Divisor: 1}
+	}
+	for _, weights := range k.Weights {
+		pad = max(pad, k.X, len(weights)-1-k.X)
+	}
+
+	// Accumulated errors, multiplied by the divisor, for the rows covered by the kernel. The rows are
+	// padded so the kernel never reaches outside.
+	errs := make([][]int32, len(k.Weights))
+	for i := range errs {
+		errs[i] = make([]int32, width+2*pad)
+	}
+	row := make([]int32, width)
+
+	for y := 0; y < r.Dy(); y++ {
+		luma(row, src, sp.X, sp.Y+y)
+
+		x, end, dir := 0, width, 1
+		if d.Serpentine && y&1 == 1 {
+			x, end, dir = width-1, -1, -1
+		}
+		for ; x != end; x += dir {
+			v := min(max(row[x]+errs[0][pad+x]/int32(k.Divisor), 0), maxY)
+			q := (v*levels + maxY/2) / maxY
+			dst.Set(r.Min.X+x, r.Min.Y+y, colors[q])
+
+			e := v - q*maxY/levels
+			if e == 0 {
+				continue
+			}
+			for i, weights := range k.Weights {
+				for j, weight := range weights {
+					if weight == 0 || (i == 0 && j <= k.X) {
+						continue
+					}
+					errs[i][pad+x+(j-k.X)*dir] += e * int32(weight)
+				}
+			}
+		}
+
+		// Move on to the next row of errors.
+		first := errs[0]
+		copy(errs, errs[1:])
+		clear(first)
+		errs[len(errs)-1] = first
+	}
+}
+
+// Interface checks
+var (
+	_ draw.Drawer = ErrorDiffusion{}
+)
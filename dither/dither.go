@@ -0,0 +1,84 @@
+package dither
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+
+	"github.com/BeatGlow/display/pixel"
+)
+
+// Error diffusion drawers with serpentine scanning.
+var (
+	FloydSteinberg draw.Drawer = ErrorDiffusion{Kernel: FloydSteinbergKernel, Serpentine: true}
+	Atkinson       draw.Drawer = ErrorDiffusion{Kernel: AtkinsonKernel, Serpentine: true}
+	Sierra         draw.Drawer = ErrorDiffusion{Kernel: SierraKernel, Serpentine: true}
+)
+
+// maxY is the largest luminance value.
+const maxY = 0xffff
+
+// palette returns the colors of the gray levels of m, from black to white, or nil if m is not a monochrome
+// or grayscale pixel model.
+func palette(m color.Model) []color.Color {
+	switch m {
+	case pixel.MonoModel:
+		return []color.Color{pixel.Off, pixel.On}
+	case pixel.Gray2Model:
+		p := make([]color.Color, 4)
+		for i := range p {
+			p[i] = pixel.Gray2{Y: uint8(i)}
+		}
+		return p
+	case pixel.Gray4Model:
+		p := make([]color.Color, 16)
+		for i := range p {
+			p[i] = pixel.Gray4{Y: uint8(i)}
+		}
+		return p
+	default:
+		return nil
+	}
+}
+
+// clip clips r against the dst and src bounds, and adjusts sp accordingly, like [image/draw.Draw] does.
+func clip(dst draw.Image, r image.Rectangle, src image.Image, sp image.Point) (image.Rectangle, image.Point) {
+	orig := r.Min
+	r = r.Intersect(dst.Bounds())
+	r = r.Intersect(src.Bounds().Add(orig.Sub(sp)))
+	return r, sp.Add(r.Min.Sub(orig))
+}
+
+// prepare clips the drawing rectangle and returns the destination palette. If the destination doesn't
+// use a monochrome or grayscale model, the source is drawn without dithering and ok is false.
+func prepare(dst draw.Image, r image.Rectangle, src image.Image, sp image.Point) (_ image.Rectangle, _ image.Point, colors []color.Color, ok bool) {
+	if colors = palette(dst.ColorModel()); colors == nil {
+		draw.Draw(dst, r, src, sp, draw.Src)
+		return
+	}
+	r, sp = clip(dst, r, src, sp)
+	return r, sp, colors, !r.Empty()
+}
+
+// luma reads the luminance of len(row) pixels of src, starting at (x, y), in the range [0, maxY].
+func luma(row []int32, src image.Image, x, y int) {
+	switch src := src.(type) {
+	case *image.RGBA:
+		i := src.PixOffset(x, y)
+		for j := range row {
+			s := src.Pix[i : i+3 : i+3]
+			row[j] = int32((19595*uint32(s[0])+38470*uint32(s[1])+7471*uint32(s[2])+1<<15)>>16) * 0x101
+			i += 4
+		}
+	case *image.YCbCr:
+		i := src.YOffset(x, y)
+		for j, v := range src.Y[i : i+len(row)] {
+			row[j] = int32(v) * 0x101
+		}
+	default:
+		for j := range row {
+			r, g, b, _ := src.At(x+j, y).RGBA()
+			row[j] = int32((19595*r + 38470*g + 7471*b + 1<<15) >> 16)
+		}
+	}
+}
@@ -0,0 +1,220 @@
+package dither
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+	"testing"
+
+	"github.com/BeatGlow/display/pixel"
+)
+
+// opaque hides the concrete type of an image, to test the generic source path.
+type opaque struct {
+	image.Image
+}
+
+var drawers = []struct {
+	Name   string
+	Drawer draw.Drawer
+
+	// Tolerance is the maximum deviation of the mean brightness of a mono gradient.
+	Tolerance float64
+}{
+	{"floyd-steinberg", FloydSteinberg, 0.05},
+	{"atkinson", Atkinson, 0.1}, // loses the error in light and dark areas
+	{"sierra", Sierra, 0.05},
+	{"floyd-steinberg-raster", ErrorDiffusion{Kernel: FloydSteinbergKernel}, 0.05},
+	{"bayer2", Bayer(2), 0.07}, // five levels
+	{"bayer4", Bayer(4), 0.05},
+	{"bayer8", Bayer(8), 0.05},
+}
+
+var targets = []struct {
+	Name string
+	New  func(w, h int) pixel.Image
+}{
+	{"mono", func(w, h int) pixel.Image { return pixel.NewMonoImage(w, h) }},
+	{"mono-vertical", func(w, h int) pixel.Image { return pixel.NewMonoVerticalLSBImage(w, h) }},
+	{"gray2", func(w, h int) pixel.Image { return pixel.NewGray2Image(w, h) }},
+	{"gray4", func(w, h int) pixel.Image { return pixel.NewGray4Image(w, h) }},
+}
+
+// level returns the brightness of a dithered pixel in the range [0, 1].
+func level(c color.Color) float64 {
+	switch c := c.(type) {
+	case pixel.Mono:
+		if c.On {
+			return 1
+		}
+		return 0
+	case pixel.Gray2:
+		return float64(c.Y) / 3
+	case pixel.Gray4:
+		return float64(c.Y) / 15
+	default:
+		panic("unexpected color")
+	}
+}
+
+// mean returns the mean brightness of r in i.
+func mean(i image.Image, r image.Rectangle) float64 {
+	var sum float64
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			sum += level(i.At(x, y))
+		}
+	}
+	return sum / float64(r.Dx()*r.Dy())
+}
+
+func gradient(w, h int) *image.RGBA {
+	i := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			i.Set(x, y, color.Gray{Y: uint8(x * 255 / (w - 1))})
+		}
+	}
+	return i
+}
+
+func TestDither(t *testing.T) {
+	src := gradient(256, 32)
+	for _, drawer := range drawers {
+		for _, target := range targets {
+			t.Run(drawer.Name+" "+target.Name, func(it *testing.T) {
+				dst := target.New(256, 32)
+				drawer.Drawer.Draw(dst, dst.Bounds(), src, image.Point{})
+
+				// The mean brightness of every band of 32 columns follows the gradient.
+				for x := 0; x < 256; x += 32 {
+					var (
+						r    = image.Rect(x, 0, x+32, 32)
+						want = (float64(x) + 15.5) / 255
+					)
+					if got := mean(dst, r); math.Abs(got-want) > drawer.Tolerance {
+						it.Errorf("columns %d-%d: expected mean brightness %.3f, got %.3f", r.Min.X, r.Max.X, want, got)
+					}
+				}
+
+				// Black and white are not dithered.
+				for _, c := range []color.Gray{{Y: 0}, {Y: 255}} {
+					drawer.Drawer.Draw(dst, dst.Bounds(), image.NewUniform(c), image.Point{})
+					if got := mean(dst, dst.Bounds()); got != float64(c.Y)/255 {
+						it.Errorf("%v: expected a solid image, got mean brightness %.3f", c, got)
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestBayer(t *testing.T) {
+	for _, n := range []int{2, 4, 8} {
+		o := Bayer(n)
+		seen := make(map[int64]bool)
+		for _, v := range o.matrix {
+			seen[v] = true
+		}
+		if len(o.matrix) != n*n || len(seen) != n*n {
+			t.Errorf("bayer%d: expected %d distinct thresholds, got %v", n, n*n, o.matrix)
+		}
+	}
+
+	// Mid gray is a checkerboard.
+	dst := pixel.NewMonoImage(8, 8)
+	Bayer(2).Draw(dst, dst.Bounds(), image.NewUniform(color.Gray{Y: 128}), image.Point{})
+	for y := 0; y < 8; y++ {
+		for x := 0; x < 8; x++ {
+			if want := (x+y)&1 == 1; dst.At(x, y) != (pixel.Mono{On: want}) {
+				t.Fatalf("expected a checkerboard, pixel %d,%d is %v", x, y, dst.At(x, y))
+			}
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected Bayer(3) to panic")
+		}
+	}()
+	Bayer(3)
+}
+
+func TestDitherFastPath(t *testing.T) {
+	var (
+		rgba  = gradient(64, 16)
+		ycbcr = image.NewYCbCr(rgba.Rect, image.YCbCrSubsampleRatio420)
+	)
+	for y := 0; y < 16; y++ {
+		for x := 0; x < 64; x++ {
+			ycbcr.Y[ycbcr.YOffset(x, y)] = uint8(x * 255 / 63)
+		}
+	}
+	for i := range ycbcr.Cb {
+		ycbcr.Cb[i], ycbcr.Cr[i] = 128, 128
+	}
+
+	for _, drawer := range drawers {
+		for _, src := range []image.Image{rgba, ycbcr} {
+			var (
+				want = pixel.NewGray2Image(64, 16)
+				got  = pixel.NewGray2Image(64, 16)
+			)
+			drawer.Drawer.Draw(want, want.Bounds(), opaque{src}, image.Point{})
+			drawer.Drawer.Draw(got, got.Bounds(), src, image.Point{})
+			for y := 0; y < 16; y++ {
+				for x := 0; x < 64; x++ {
+					if got.At(x, y) != want.At(x, y) {
+						t.Fatalf("%s %T: pixel %d,%d differs from the generic path", drawer.Name, src, x, y)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestDitherClip(t *testing.T) {
+	var (
+		src = image.NewUniform(color.White)
+		dst = pixel.NewMonoImage(16, 16)
+	)
+	for _, drawer := range drawers {
+		dst.Clear()
+		drawer.Drawer.Draw(dst, image.Rect(8, 8, 32, 32), src, image.Point{})
+		if got := mean(dst, image.Rect(8, 8, 16, 16)); got != 1 {
+			t.Errorf("%s: expected the clipped rectangle to be drawn, got mean brightness %.3f", drawer.Name, got)
+		}
+		if got := mean(dst, image.Rect(0, 0, 16, 8)); got != 0 {
+			t.Errorf("%s: expected pixels outside the rectangle to be unchanged", drawer.Name)
+		}
+	}
+
+	// Destinations without gray levels are drawn without dithering.
+	rgba := image.NewRGBA(image.Rect(0, 0, 4, 4))
+	FloydSteinberg.Draw(rgba, rgba.Bounds(), image.NewUniform(color.Gray{Y: 100}), image.Point{})
+	if c := rgba.RGBAAt(1, 1); c != (color.RGBA{R: 100, G: 100, B: 100, A: 255}) {
+		t.Errorf("expected the source to be copied, got %v", c)
+	}
+}
+
+func BenchmarkFloydSteinberg(b *testing.B) {
+	var (
+		src = gradient(256, 64)
+		dst = pixel.NewGray4Image(256, 64)
+	)
+	for i := 0; i < b.N; i++ {
+		FloydSteinberg.Draw(dst, dst.Bounds(), src, image.Point{})
+	}
+}
+
+func BenchmarkBayer(b *testing.B) {
+	var (
+		src = gradient(256, 64)
+		dst = pixel.NewGray4Image(256, 64)
+		o   = Bayer(4)
+	)
+	for i := 0; i < b.N; i++ {
+		o.Draw(dst, dst.Bounds(), src, image.Point{})
+	}
+}
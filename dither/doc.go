@@ -0,0 +1,10 @@
+// Package dither draws images into monochrome and grayscale pixel images with dithering.
+//
+// Plain color model conversion thresholds every pixel, so photos and gradients lose all their shades on
+// displays with few gray levels. The drawers in this package spread the quantization error over the
+// neighbouring pixels ([FloydSteinberg], [Atkinson] and [Sierra]), or apply a threshold map ([Bayer]).
+//
+// All drawers implement [image/draw.Drawer] and quantize to the levels of [pixel.MonoModel],
+// [pixel.Gray2Model] or [pixel.Gray4Model] destinations, including displays using these models. Other
+// destinations are drawn without dithering.
+package dither
@@ -0,0 +1,75 @@
+package dither
+
+import (
+	"fmt"
+	"image"
+	"image/draw"
+)
+
+// Ordered is a drawer that compares each pixel with a threshold from a map tiled over the destination.
+// Unlike error diffusion, pixels don't depend on their neighbours, so animations don't flicker and partial
+// redraws match the rest of the image. The zero Ordered quantizes without dithering.
+type Ordered struct {
+	size   int
+	matrix []int64
+}
+
+// Bayer returns an ordered drawer with a Bayer threshold map of n×n pixels, n must be 2, 4 or 8.
+func Bayer(n int) Ordered {
+	if n != 2 && n != 4 && n != 8 {
+		panic(fmt.Sprintf("dither: invalid Bayer matrix size %d", n))
+	}
+
+	// Every doubling of the size replaces each threshold t with the 2×2 block 4t+{0, 2, 3, 1}.
+	matrix := []int64{0}
+	for size := 1; size < n; size *= 2 {
+		next := make([]int64, 4*size*size)
+		for y := 0; y < size; y++ {
+			for x := 0; x < size; x++ {
+				t := 4 * matrix[y*size+x]
+				next[(2*y)*2*size+2*x] = t
+				next[(2*y)*2*size+2*x+1] = t + 2
+				next[(2*y+1)*2*size+2*x] = t + 3
+				next[(2*y+1)*2*size+2*x+1] = t + 1
+			}
+		}
+		matrix = next
+	}
+	return Ordered{size: n, matrix: matrix}
+}
+
+// Draw aligns r.Min in dst with sp in src and then replaces the rectangle r in dst with the dithered
+// source.
+func (o Ordered) Draw(dst draw.Image, r image.Rectangle, src image.Image, sp image.Point) {
+	r, sp, colors, ok := prepare(dst, r, src, sp)
+	if !ok {
+		return
+	}
+
+	size, matrix := o.size, o.matrix
+	if size == 0 {
+		size, matrix = 1, []int64{0}
+	}
+	var (
+		levels = int64(len(colors) - 1)
+		n      = int64(2 * size * size)
+		row    = make([]int32, r.Dx())
+	)
+	for y := 0; y < r.Dy(); y++ {
+		luma(row, src, sp.X, sp.Y+y)
+
+		// The map is aligned with the destination, so the pattern doesn't depend on r.
+		thresholds := matrix[((r.Min.Y+y)&(size-1))*size:][:size]
+		for x, v := range row {
+			// Round v, scaled to the levels, down after adding the threshold (t+½)/size².
+			t := thresholds[(r.Min.X+x)&(size-1)]
+			q := min((int64(v)*levels*n+(2*t+1)*maxY)/(n*maxY), levels)
+			dst.Set(r.Min.X+x, r.Min.Y+y, colors[q])
+		}
+	}
+}
+
+// Interface checks
+var (
+	_ draw.Drawer = Ordered{}
+)
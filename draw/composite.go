@@ -0,0 +1,280 @@
+package draw
+
+import (
+	"image"
+	"image/color"
+	"reflect"
+)
+
+// m is the maximum color component value returned by [color.Color.RGBA].
+const m = 1<<16 - 1
+
+// drawPixel draws into pixel image destinations, bypassing the color model conversion of every pixel. It
+// returns false if the combination of dst, src and mask is not supported.
+//
+// The composition follows the generic implementation of [image/draw.DrawMask], so the results are the same.
+func drawPixel(dst Image, r image.Rectangle, src image.Image, sp image.Point, mask image.Image, mp image.Point, op Op) bool {
+	t := newTarget(dst)
+	if t == nil || (op != Over && op != Src) || src == image.Image(dst) {
+		return false
+	}
+	s := newSource(dst, t, src)
+	if s == nil {
+		return false
+	}
+	alpha, ok := newMask(mask)
+	if !ok {
+		return false
+	}
+
+	if r, sp, mp = clip(dst, r, src, sp, mask, mp); r.Empty() {
+		return true
+	}
+
+	if u, ok := s.(uniformSource); ok {
+		if k, ok := mask.(*image.Alpha); ok {
+			// Glyphs are drawn with a uniform color through an alpha mask.
+			t.drawUniform(r, u, k, mp, op)
+			return true
+		}
+	}
+
+	if mask == nil {
+		if t.copy(r, src, sp) {
+			// Pixel image colors are opaque, so Over is the same as Src.
+			return true
+		}
+		if u, ok := s.(uniformSource); ok && (op == Src || u.c[3] == m) {
+			if op == Src {
+				t.fill(r, u.v)
+			} else {
+				// Over converts the composed color, not the source color.
+				t.fill(r, t.value(&color.RGBA64{R: uint16(u.c[0]), G: uint16(u.c[1]), B: uint16(u.c[2]), A: m}))
+			}
+			return true
+		}
+	}
+
+	var (
+		n           = r.Dx()
+		v           = make([]uint32, n)   // pixel values
+		c           = make([]uint32, 4*n) // source colors
+		d           []uint32              // destination colors
+		a           []uint32              // mask alpha values
+		transparent = t.value(color.Transparent)
+	)
+	if op == Over {
+		d = make([]uint32, 4*n)
+	}
+	if alpha != nil {
+		a = make([]uint32, n)
+	}
+	for y, sy, my := r.Min.Y, sp.Y, mp.Y; y < r.Max.Y; y, sy, my = y+1, sy+1, my+1 {
+		s.rgbaRow(c, sp.X, sy)
+		if op == Src {
+			s.valueRow(v, sp.X, sy)
+		} else {
+			t.rgbaRow(d, r.Min.X, y)
+		}
+		if alpha != nil {
+			alpha.alphaRow(a, mp.X, my)
+		}
+
+		for i := range v {
+			ma := uint32(m)
+			if a != nil {
+				ma = a[i]
+			}
+			j := i * 4
+			switch {
+			case ma == 0:
+				if op == Over {
+					v[i] = skipPixel
+				} else {
+					v[i] = transparent
+				}
+			case ma == m && op == Src:
+				// The value was read from the source.
+			case op == Over:
+				f := m - c[j+3]*ma/m
+				c[j] = (d[j]*f + c[j]*ma) / m
+				c[j+1] = (d[j+1]*f + c[j+1]*ma) / m
+				c[j+2] = (d[j+2]*f + c[j+2]*ma) / m
+				v[i] = convertPixel
+			default:
+				c[j] = c[j] * ma / m
+				c[j+1] = c[j+1] * ma / m
+				c[j+2] = c[j+2] * ma / m
+				v[i] = convertPixel
+			}
+		}
+
+		t.convertRow(v, c)
+		t.writeRow(v, r.Min.X, y)
+	}
+	return true
+}
+
+// clip clips r against each image's bounds (after translating into the destination image's coordinate
+// space) and shifts the points sp and mp by the same amount as the change in r.Min.
+func clip(dst Image, r image.Rectangle, src image.Image, sp image.Point, mask image.Image, mp image.Point) (image.Rectangle, image.Point, image.Point) {
+	orig := r.Min
+	r = r.Intersect(dst.Bounds())
+	r = r.Intersect(src.Bounds().Add(orig.Sub(sp)))
+	if mask != nil {
+		r = r.Intersect(mask.Bounds().Add(orig.Sub(mp)))
+	}
+	d := r.Min.Sub(orig)
+	return r, sp.Add(d), mp.Add(d)
+}
+
+// source reads rows of a source image.
+type source interface {
+	// rgbaRow reads the premultiplied colors of the pixels starting at (x, y) into c, with 4 components
+	// per pixel.
+	rgbaRow(c []uint32, x, y int)
+
+	// valueRow reads the values of the pixels starting at (x, y) in the destination color model into v,
+	// or flags them with convertPixel if they are converted from their color.
+	valueRow(v []uint32, x, y int)
+}
+
+// newSource returns the source for src, or nil if src is not supported.
+func newSource(dst Image, t target, src image.Image) source {
+	switch src := src.(type) {
+	case *image.RGBA:
+		return rgbaSource{src}
+	case *image.Gray:
+		return graySource{src}
+	case *image.Alpha:
+		return alphaSource{src}
+	case *image.Uniform:
+		s := uniformSource{v: t.value(src.C)}
+		s.c[0], s.c[1], s.c[2], s.c[3] = src.C.RGBA()
+		return s
+	}
+	if reflect.TypeOf(src) == reflect.TypeOf(dst) {
+		return pixelSource{newTarget(src)}
+	}
+	return nil
+}
+
+// convertRow flags all pixels in v with convertPixel.
+func convertRow(v []uint32) {
+	for i := range v {
+		v[i] = convertPixel
+	}
+}
+
+type rgbaSource struct {
+	*image.RGBA
+}
+
+func (s rgbaSource) rgbaRow(c []uint32, x, y int) {
+	i := s.PixOffset(x, y)
+	for j, v := range s.Pix[i : i+len(c)] {
+		c[j] = uint32(v) * 0x101
+	}
+}
+
+func (rgbaSource) valueRow(v []uint32, _, _ int) {
+	convertRow(v)
+}
+
+type graySource struct {
+	*image.Gray
+}
+
+func (s graySource) rgbaRow(c []uint32, x, y int) {
+	i := s.PixOffset(x, y)
+	for j, v := range s.Pix[i : i+len(c)/4] {
+		y := uint32(v) * 0x101
+		c[j*4], c[j*4+1], c[j*4+2], c[j*4+3] = y, y, y, m
+	}
+}
+
+func (graySource) valueRow(v []uint32, _, _ int) {
+	convertRow(v)
+}
+
+type alphaSource struct {
+	*image.Alpha
+}
+
+func (s alphaSource) rgbaRow(c []uint32, x, y int) {
+	i := s.PixOffset(x, y)
+	for j, v := range s.Pix[i : i+len(c)/4] {
+		a := uint32(v) * 0x101
+		c[j*4], c[j*4+1], c[j*4+2], c[j*4+3] = a, a, a, a
+	}
+}
+
+func (alphaSource) valueRow(v []uint32, _, _ int) {
+	convertRow(v)
+}
+
+type uniformSource struct {
+	c [4]uint32 // premultiplied color
+	v uint32    // pixel value
+}
+
+func (s uniformSource) rgbaRow(c []uint32, _, _ int) {
+	for j := 0; j < len(c); j += 4 {
+		copy(c[j:j+4], s.c[:])
+	}
+}
+
+func (s uniformSource) valueRow(v []uint32, _, _ int) {
+	for i := range v {
+		v[i] = s.v
+	}
+}
+
+// pixelSource is a pixel image of the same type as the destination.
+type pixelSource struct {
+	target
+}
+
+func (s pixelSource) valueRow(v []uint32, x, y int) {
+	s.readRow(v, x, y)
+}
+
+// maskRows reads rows of a mask image.
+type maskRows interface {
+	// alphaRow reads the alpha values of the pixels starting at (x, y) into a.
+	alphaRow(a []uint32, x, y int)
+}
+
+// newMask returns the mask for i, ok is false if i is not supported. A nil mask is opaque.
+func newMask(i image.Image) (_ maskRows, ok bool) {
+	switch i := i.(type) {
+	case nil:
+		return nil, true
+	case *image.Alpha:
+		return alphaMask{i}, true
+	case *image.Uniform:
+		_, _, _, a := i.C.RGBA()
+		return uniformMask(a), true
+	default:
+		return nil, false
+	}
+}
+
+type alphaMask struct {
+	*image.Alpha
+}
+
+func (k alphaMask) alphaRow(a []uint32, x, y int) {
+	i := k.PixOffset(x, y)
+	for j, v := range k.Pix[i : i+len(a)] {
+		a[j] = uint32(v) * 0x101
+	}
+}
+
+type uniformMask uint32
+
+func (k uniformMask) alphaRow(a []uint32, _, _ int) {
+	for i := range a {
+		a[i] = uint32(k)
+	}
+}
@@ -30,6 +30,12 @@ func Draw(dst Image, r image.Rectangle, src image.Image, sp image.Point, op Op)
 
 // DrawMask aligns r.Min in dst with sp in src and mp in mask and then replaces the rectangle r
 // in dst with the result of a Porter-Duff composition. A nil mask is treated as opaque.
+//
+// Destinations from the pixel package are drawn directly into their pixel buffers for [image.RGBA],
+// [image.Gray], [image.Alpha] and [image.Uniform] sources, sources of the same type, and nil,
+// [image.Alpha] or [image.Uniform] masks. The result is the same as with [image/draw.DrawMask].
 func DrawMask(dst Image, r image.Rectangle, src image.Image, sp image.Point, mask image.Image, mp image.Point, op Op) {
-	draw.DrawMask(dst, r, src, sp, mask, mp, op)
+	if !drawPixel(dst, r, src, sp, mask, mp, op) {
+		draw.DrawMask(dst, r, src, sp, mask, mp, op)
+	}
 }
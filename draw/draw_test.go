@@ -0,0 +1,258 @@
+package draw
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"math/rand"
+	"testing"
+
+	"github.com/BeatGlow/display/pixel"
+)
+
+// opaque hides the concrete type of an image, so image/draw uses its generic implementation.
+type opaque struct {
+	draw.Image
+}
+
+var destinations = []struct {
+	Name string
+	New  func(w, h int) pixel.Image
+}{
+	{"mono", func(w, h int) pixel.Image { return pixel.NewMonoImage(w, h) }},
+	{"mono-vertical", func(w, h int) pixel.Image { return pixel.NewMonoVerticalLSBImage(w, h) }},
+	{"gray2", func(w, h int) pixel.Image { return pixel.NewGray2Image(w, h) }},
+	{"gray4", func(w, h int) pixel.Image { return pixel.NewGray4Image(w, h) }},
+	{"cbgr15", func(w, h int) pixel.Image { return pixel.NewCBGR15Image(w, h) }},
+	{"cbgr16", func(w, h int) pixel.Image { return pixel.NewCBGR16Image(w, h) }},
+	{"crgb15", func(w, h int) pixel.Image { return pixel.NewCRGB15Image(w, h) }},
+	{"crgb16", func(w, h int) pixel.Image { return pixel.NewCRGB16Image(w, h) }},
+}
+
+func buffer(i pixel.Image) *pixel.Buffer {
+	switch i := i.(type) {
+	case *pixel.MonoImage:
+		return &i.Buffer
+	case *pixel.MonoVerticalLSBImage:
+		return &i.Buffer
+	case *pixel.Gray2Image:
+		return &i.Buffer
+	case *pixel.Gray4Image:
+		return &i.Buffer
+	case *pixel.CBGR15Image:
+		return &i.Buffer
+	case *pixel.CBGR16Image:
+		return &i.Buffer
+	case *pixel.CRGB15Image:
+		return &i.Buffer
+	case *pixel.CRGB16Image:
+		return &i.Buffer
+	default:
+		panic("unexpected image")
+	}
+}
+
+func randomRGBA(r *rand.Rand, w, h int) *image.RGBA {
+	i := image.NewRGBA(image.Rect(0, 0, w, h))
+	for j := 0; j < len(i.Pix); j += 4 {
+		a := r.Intn(256)
+		switch r.Intn(3) {
+		case 0:
+			a = 0
+		case 1:
+			a = 255
+		}
+		i.Pix[j+0] = uint8(r.Intn(a + 1))
+		i.Pix[j+1] = uint8(r.Intn(a + 1))
+		i.Pix[j+2] = uint8(r.Intn(a + 1))
+		i.Pix[j+3] = uint8(a)
+	}
+	return i
+}
+
+func randomAlpha(r *rand.Rand, w, h int) *image.Alpha {
+	i := image.NewAlpha(image.Rect(0, 0, w, h))
+	for j := range i.Pix {
+		switch r.Intn(3) {
+		case 0:
+			i.Pix[j] = 0
+		case 1:
+			i.Pix[j] = 255
+		default:
+			i.Pix[j] = uint8(r.Intn(256))
+		}
+	}
+	return i
+}
+
+func randomGray(r *rand.Rand, w, h int) *image.Gray {
+	i := image.NewGray(image.Rect(0, 0, w, h))
+	r.Read(i.Pix)
+	return i
+}
+
+func TestDrawMask(t *testing.T) {
+	const w, h = 37, 21
+
+	r := rand.New(rand.NewSource(1))
+	for _, dst := range destinations {
+		same := dst.New(w, h)
+		r.Read(buffer(same).Pix)
+
+		sources := []struct {
+			Name string
+			Src  image.Image
+		}{
+			{"rgba", randomRGBA(r, w, h)},
+			{"gray", randomGray(r, w, h)},
+			{"alpha", randomAlpha(r, w, h)},
+			{"uniform", image.NewUniform(color.RGBA{R: 0x80, G: 0x40, B: 0xc0, A: 0xff})},
+			{"uniform-translucent", image.NewUniform(color.RGBA{R: 0x20, G: 0x40, B: 0x10, A: 0x60})},
+			{"uniform-transparent", image.NewUniform(color.Transparent)},
+			{"uniform-on", image.NewUniform(pixel.On)},
+			{"uniform-gray4", image.NewUniform(pixel.Gray4{Y: 7})},
+			{"same", same},
+		}
+		if i, ok := same.(*pixel.CRGB16Image); ok {
+			// Same type with a different byte order.
+			little := *i
+			little.Order = binary.LittleEndian
+			sources = append(sources, struct {
+				Name string
+				Src  image.Image
+			}{"same-little-endian", &little})
+		}
+
+		masks := []struct {
+			Name string
+			Mask image.Image
+		}{
+			{"nil", nil},
+			{"alpha", randomAlpha(r, w, h)},
+			{"uniform", image.NewUniform(color.Alpha{A: 0x80})},
+		}
+
+		for _, src := range sources {
+			for _, mask := range masks {
+				for _, op := range []Op{Src, Over} {
+					for _, rect := range []struct {
+						R      image.Rectangle
+						SP, MP image.Point
+					}{
+						{image.Rect(0, 0, w, h), image.Point{}, image.Point{}},
+						{image.Rect(8, 8, 32, 19), image.Pt(8, 0), image.Pt(0, 8)},
+						{image.Rect(3, 5, 40, 40), image.Pt(1, 2), image.Pt(2, 1)},
+						{image.Rect(-5, -3, 30, 12), image.Pt(0, 0), image.Pt(5, 3)},
+					} {
+						name := fmt.Sprintf("%s %s mask %s op %d rect %s", dst.Name, src.Name, mask.Name, op, rect.R)
+						var (
+							got  = dst.New(w, h)
+							want = dst.New(w, h)
+						)
+						r.Read(buffer(got).Pix)
+						copy(buffer(want).Pix, buffer(got).Pix)
+
+						DrawMask(got, rect.R, src.Src, rect.SP, mask.Mask, rect.MP, op)
+						draw.DrawMask(opaque{want}, rect.R, src.Src, rect.SP, mask.Mask, rect.MP, op)
+						if !equal(got, want) {
+							t.Errorf("%s: result differs from image/draw", name)
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+// equal compares the visible pixels of two images.
+func equal(a, b image.Image) bool {
+	r := a.Bounds()
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			if a.At(x, y) != b.At(x, y) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func TestDrawMaskFallback(t *testing.T) {
+	// Unsupported sources and masks use image/draw.
+	var (
+		src  = image.NewNRGBA(image.Rect(0, 0, 8, 8))
+		got  = pixel.NewGray4Image(8, 8)
+		want = pixel.NewGray4Image(8, 8)
+	)
+	for i := range src.Pix {
+		src.Pix[i] = uint8(i * 7)
+	}
+	Draw(got, got.Bounds(), src, image.Point{}, Over)
+	draw.Draw(want, want.Bounds(), src, image.Point{}, draw.Over)
+	if !bytes.Equal(got.Pix, want.Pix) {
+		t.Error("expected the same result as image/draw")
+	}
+
+	// Drawing an image onto itself.
+	copy(got.Pix, []byte{0x12, 0x34, 0x56, 0x78})
+	copy(want.Pix, got.Pix)
+	Draw(got, image.Rect(1, 0, 8, 8), got, image.Point{}, Src)
+	draw.Draw(want, image.Rect(1, 0, 8, 8), want, image.Point{}, draw.Src)
+	if !bytes.Equal(got.Pix, want.Pix) {
+		t.Errorf("expected overlapping draws to match image/draw, got % x, want % x", got.Pix[:4], want.Pix[:4])
+	}
+}
+
+func BenchmarkDraw(b *testing.B) {
+	const w, h = 256, 64
+
+	var (
+		r     = rand.New(rand.NewSource(1))
+		rgba  = randomRGBA(r, w, h)
+		gray  = randomGray(r, w, h)
+		alpha = randomAlpha(r, w, h)
+		glyph = image.NewAlpha(alpha.Rect) // bitmap font glyphs, without antialiasing
+		white = image.NewUniform(color.White)
+	)
+	for i, a := range alpha.Pix {
+		if a >= 0x80 {
+			glyph.Pix[i] = 0xff
+		}
+	}
+	for _, dst := range destinations {
+		switch dst.Name {
+		case "mono-vertical", "gray4", "crgb16":
+		default:
+			continue
+		}
+		for _, test := range []struct {
+			Name string
+			Src  image.Image
+			Mask image.Image
+			Op   Op
+		}{
+			{"rgba", rgba, nil, Src},
+			{"rgba-over", rgba, nil, Over},
+			{"gray", gray, nil, Src},
+			{"uniform", white, nil, Src},
+			{"uniform-mask", white, alpha, Over},
+			{"uniform-glyph", white, glyph, Over},
+			{"same", dst.New(w, h), nil, Src},
+		} {
+			i := dst.New(w, h)
+			b.Run(dst.Name+"/"+test.Name+"/fast", func(b *testing.B) {
+				for n := 0; n < b.N; n++ {
+					DrawMask(i, i.Bounds(), test.Src, image.Point{}, test.Mask, image.Point{}, test.Op)
+				}
+			})
+			b.Run(dst.Name+"/"+test.Name+"/generic", func(b *testing.B) {
+				for n := 0; n < b.N; n++ {
+					draw.DrawMask(opaque{i}, i.Bounds(), test.Src, image.Point{}, test.Mask, image.Point{}, test.Op)
+				}
+			})
+		}
+	}
+}
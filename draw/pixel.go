@@ -0,0 +1,592 @@
+package draw
+
+import (
+	"encoding/binary"
+	"image"
+	"image/color"
+
+	"github.com/BeatGlow/display/pixel"
+)
+
+// Pixel value flags used in rows of pixel values.
+const (
+	skipPixel    = 1 << 31 // pixel is left unchanged
+	convertPixel = 1 << 30 // pixel value is converted from the color
+)
+
+// pixels provides direct access to the pixel values of a pixel image. Pixel values are the bits stored in
+// the image, such as the 4-bit level of a Gray4Image.
+type pixels interface {
+	// value returns the pixel value of c, converted with the image color model.
+	value(c color.Color) uint32
+
+	// convert returns the pixel value of a color with 16-bit components, like the color model does for
+	// colors that are not of the image color type.
+	convert(r, g, b uint32) uint32
+
+	// rgba returns the color of a pixel value.
+	rgba(v uint32) (r, g, b uint32)
+
+	// at returns the pixel value at (x, y).
+	at(x, y int) uint32
+
+	// set sets the pixel value at (x, y).
+	set(x, y int, v uint32)
+
+	// fill sets the pixels in r to v.
+	fill(r image.Rectangle, v uint32)
+
+	// copy replaces the pixels in r with the pixels from src at sp, if src has the same type.
+	copy(r image.Rectangle, src image.Image, sp image.Point) bool
+}
+
+// target is a pixel image destination with row operations.
+type target interface {
+	value(c color.Color) uint32
+	fill(r image.Rectangle, v uint32)
+	copy(r image.Rectangle, src image.Image, sp image.Point) bool
+
+	// readRow reads the values of the pixels starting at (x, y) into v.
+	readRow(v []uint32, x, y int)
+
+	// rgbaRow reads the colors of the pixels starting at (x, y) into c, with 4 components per pixel.
+	rgbaRow(c []uint32, x, y int)
+
+	// convertRow converts the colors in c of the pixels flagged with convertPixel in v.
+	convertRow(v, c []uint32)
+
+	// writeRow writes the pixel values in v starting at (x, y), except the ones flagged with skipPixel.
+	writeRow(v []uint32, x, y int)
+
+	// drawUniform draws the uniform source s through the alpha mask at mp into r.
+	drawUniform(r image.Rectangle, s uniformSource, mask *image.Alpha, mp image.Point, op Op)
+}
+
+// rows implements the row operations of a target, the compiler generates them for each pixel image type.
+type rows[P pixels] struct {
+	p P
+}
+
+func (t rows[P]) value(c color.Color) uint32 {
+	return t.p.value(c)
+}
+
+func (t rows[P]) fill(r image.Rectangle, v uint32) {
+	t.p.fill(r, v)
+}
+
+func (t rows[P]) copy(r image.Rectangle, src image.Image, sp image.Point) bool {
+	return t.p.copy(r, src, sp)
+}
+
+func (t rows[P]) readRow(v []uint32, x, y int) {
+	for i := range v {
+		v[i] = t.p.at(x+i, y)
+	}
+}
+
+func (t rows[P]) rgbaRow(c []uint32, x, y int) {
+	for i := 0; i < len(c); i += 4 {
+		c[i], c[i+1], c[i+2] = t.p.rgba(t.p.at(x, y))
+		c[i+3] = m
+		x++
+	}
+}
+
+func (t rows[P]) convertRow(v, c []uint32) {
+	for i := range v {
+		if v[i] == convertPixel {
+			v[i] = t.p.convert(c[i*4], c[i*4+1], c[i*4+2])
+		}
+	}
+}
+
+func (t rows[P]) writeRow(v []uint32, x, y int) {
+	for i := range v {
+		if v[i] != skipPixel {
+			t.p.set(x+i, y, v[i])
+		}
+	}
+}
+
+// drawUniform composes like drawPixel, pixel by pixel. Pixels with fully transparent or opaque mask values
+// don't depend on the destination color, so only the other pixels are read and converted.
+func (t rows[P]) drawUniform(r image.Rectangle, s uniformSource, mask *image.Alpha, mp image.Point, op Op) {
+	var (
+		opaque      = s.v
+		transparent = t.p.value(color.Transparent)
+		solid       = op == Src || s.c[3] == m // opaque mask values replace the destination
+	)
+	if op == Over {
+		// Over converts the composed color, not the source color.
+		opaque = t.p.convert(s.c[0], s.c[1], s.c[2])
+	}
+	for y, my := r.Min.Y, mp.Y; y < r.Max.Y; y, my = y+1, my+1 {
+		i := mask.PixOffset(mp.X, my)
+		for x, a := range mask.Pix[i : i+r.Dx()] {
+			x += r.Min.X
+			switch {
+			case a == 0:
+				if op == Src {
+					t.p.set(x, y, transparent)
+				}
+			case a == 0xff && solid:
+				t.p.set(x, y, opaque)
+			case op == Over:
+				var (
+					ma      = uint32(a) * 0x101
+					f       = m - s.c[3]*ma/m
+					r, g, b = t.p.rgba(t.p.at(x, y))
+				)
+				t.p.set(x, y, t.p.convert((r*f+s.c[0]*ma)/m, (g*f+s.c[1]*ma)/m, (b*f+s.c[2]*ma)/m))
+			default:
+				ma := uint32(a) * 0x101
+				t.p.set(x, y, t.p.convert(s.c[0]*ma/m, s.c[1]*ma/m, s.c[2]*ma/m))
+			}
+		}
+	}
+}
+
+// newTarget returns the target for i, or nil if i isn't a pixel image.
+func newTarget(i image.Image) target {
+	switch i := i.(type) {
+	case *pixel.MonoImage:
+		return rows[monoPixels]{monoPixels{i}}
+	case *pixel.MonoVerticalLSBImage:
+		return rows[monoVerticalPixels]{monoVerticalPixels{i}}
+	case *pixel.Gray2Image:
+		return rows[gray2Pixels]{gray2Pixels{i}}
+	case *pixel.Gray4Image:
+		return rows[gray4Pixels]{gray4Pixels{i}}
+	case *pixel.CBGR15Image:
+		return rows[cbgr15Pixels]{cbgr15Pixels{newRGB16Pixels(&i.Buffer, i.Order, 0x7fff)}}
+	case *pixel.CBGR16Image:
+		return rows[cbgr16Pixels]{cbgr16Pixels{newRGB16Pixels(&i.Buffer, i.Order, 0xffff)}}
+	case *pixel.CRGB15Image:
+		return rows[crgb15Pixels]{crgb15Pixels{newRGB16Pixels(&i.Buffer, i.Order, 0x7fff)}}
+	case *pixel.CRGB16Image:
+		return rows[crgb16Pixels]{crgb16Pixels{newRGB16Pixels(&i.Buffer, i.Order, 0xffff)}}
+	default:
+		return nil
+	}
+}
+
+// copyPacked copies rows of pixels packed horizontally into bytes, with n pixels per byte. Whole bytes
+// are copied if the source and destination pixels share the same position in their bytes, other pixels
+// are copied one by one.
+func copyPacked[P pixels](dst P, dstBuf *pixel.Buffer, src P, srcBuf *pixel.Buffer, r image.Rectangle, sp image.Point, n int) {
+	aligned := r.Min.X%n == sp.X%n
+	for y, sy := r.Min.Y, sp.Y; y < r.Max.Y; y, sy = y+1, sy+1 {
+		x, sx := r.Min.X, sp.X
+		if aligned {
+			for ; x < r.Max.X && x%n != 0; x, sx = x+1, sx+1 {
+				dst.set(x, y, src.at(sx, sy))
+			}
+			if bytes := (r.Max.X - x) / n; bytes > 0 {
+				i, j := y*dstBuf.Stride+x/n, sy*srcBuf.Stride+sx/n
+				copy(dstBuf.Pix[i:i+bytes], srcBuf.Pix[j:j+bytes])
+				x, sx = x+bytes*n, sx+bytes*n
+			}
+		}
+		for ; x < r.Max.X; x, sx = x+1, sx+1 {
+			dst.set(x, y, src.at(sx, sy))
+		}
+	}
+}
+
+// fillPacked fills pixels packed horizontally into bytes, with n pixels per byte. Whole bytes are set to
+// b, the other pixels are set to v one by one.
+func fillPacked[P pixels](dst P, buf *pixel.Buffer, r image.Rectangle, n int, v uint32, b byte) {
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		x := r.Min.X
+		for ; x < r.Max.X && x%n != 0; x++ {
+			dst.set(x, y, v)
+		}
+		if bytes := (r.Max.X - x) / n; bytes > 0 {
+			i := y*buf.Stride + x/n
+			row := buf.Pix[i : i+bytes]
+			for j := range row {
+				row[j] = b
+			}
+			x += bytes * n
+		}
+		for ; x < r.Max.X; x++ {
+			dst.set(x, y, v)
+		}
+	}
+}
+
+// luma1 is the monochrome color model conversion.
+func luma1(r, g, b uint32) uint32 {
+	return (19595*r + 38470*g + 7471*b + 1<<15) >> 31
+}
+
+// luma is the grayscale color model luminance.
+func luma(r, g, b uint32) uint32 {
+	return (299*r + 587*g + 114*b + 500) / 1000
+}
+
+type monoPixels struct {
+	*pixel.MonoImage
+}
+
+func (monoPixels) value(c color.Color) uint32 {
+	if pixel.MonoModel.Convert(c).(pixel.Mono).On {
+		return 1
+	}
+	return 0
+}
+
+func (monoPixels) convert(r, g, b uint32) uint32 {
+	return luma1(r, g, b)
+}
+
+func (monoPixels) rgba(v uint32) (r, g, b uint32) {
+	v *= m
+	return v, v, v
+}
+
+func (p monoPixels) at(x, y int) uint32 {
+	return uint32(p.Pix[y*p.Stride+x/8]>>(x%8)) & 1
+}
+
+func (p monoPixels) set(x, y int, v uint32) {
+	i := y*p.Stride + x/8
+	p.Pix[i] = p.Pix[i]&^(1<<(x%8)) | byte(v)<<(x%8)
+}
+
+func (p monoPixels) fill(r image.Rectangle, v uint32) {
+	fillPacked(p, &p.Buffer, r, 8, v, byte(0xff*v))
+}
+
+func (p monoPixels) copy(r image.Rectangle, src image.Image, sp image.Point) bool {
+	s, ok := src.(*pixel.MonoImage)
+	if ok {
+		copyPacked(p, &p.Buffer, monoPixels{s}, &s.Buffer, r, sp, 8)
+	}
+	return ok
+}
+
+type monoVerticalPixels struct {
+	*pixel.MonoVerticalLSBImage
+}
+
+func (monoVerticalPixels) value(c color.Color) uint32 {
+	return monoPixels{}.value(c)
+}
+
+func (monoVerticalPixels) convert(r, g, b uint32) uint32 {
+	return luma1(r, g, b)
+}
+
+func (monoVerticalPixels) rgba(v uint32) (r, g, b uint32) {
+	v *= m
+	return v, v, v
+}
+
+func (p monoVerticalPixels) at(x, y int) uint32 {
+	return uint32(p.Pix[y/8*p.Stride+x]>>(y&7)) & 1
+}
+
+func (p monoVerticalPixels) set(x, y int, v uint32) {
+	i := y/8*p.Stride + x
+	p.Pix[i] = p.Pix[i]&^(1<<(y&7)) | byte(v)<<(y&7)
+}
+
+func (p monoVerticalPixels) fill(r image.Rectangle, v uint32) {
+	for y := r.Min.Y; y < r.Max.Y; {
+		if y&7 == 0 && y+8 <= r.Max.Y {
+			// Fill whole bands of 8 rows.
+			i := y/8*p.Stride + r.Min.X
+			band := p.Pix[i : i+r.Dx()]
+			for j := range band {
+				band[j] = byte(0xff * v)
+			}
+			y += 8
+			continue
+		}
+		for x := r.Min.X; x < r.Max.X; x++ {
+			p.set(x, y, v)
+		}
+		y++
+	}
+}
+
+func (p monoVerticalPixels) copy(r image.Rectangle, src image.Image, sp image.Point) bool {
+	s, ok := src.(*pixel.MonoVerticalLSBImage)
+	if !ok {
+		return false
+	}
+	from := monoVerticalPixels{s}
+	for y, sy := r.Min.Y, sp.Y; y < r.Max.Y; {
+		if y&7 == 0 && sy&7 == 0 && y+8 <= r.Max.Y {
+			// Copy whole bands of 8 rows.
+			i, j := y/8*p.Stride+r.Min.X, sy/8*s.Stride+sp.X
+			copy(p.Pix[i:i+r.Dx()], s.Pix[j:j+r.Dx()])
+			y, sy = y+8, sy+8
+			continue
+		}
+		for x, sx := r.Min.X, sp.X; x < r.Max.X; x, sx = x+1, sx+1 {
+			p.set(x, y, from.at(sx, sy))
+		}
+		y, sy = y+1, sy+1
+	}
+	return true
+}
+
+type gray2Pixels struct {
+	*pixel.Gray2Image
+}
+
+func (gray2Pixels) value(c color.Color) uint32 {
+	return uint32(pixel.Gray2Model.Convert(c).(pixel.Gray2).Y & 3)
+}
+
+func (gray2Pixels) convert(r, g, b uint32) uint32 {
+	return luma(r, g, b) >> 6 & 3
+}
+
+func (gray2Pixels) rgba(v uint32) (r, g, b uint32) {
+	v *= 0x4444
+	return v, v, v
+}
+
+func (p gray2Pixels) at(x, y int) uint32 {
+	return uint32(p.Pix[y*p.Stride+x/4]>>((3-x&3)<<1)) & 3
+}
+
+func (p gray2Pixels) set(x, y int, v uint32) {
+	var (
+		i     = y*p.Stride + x/4
+		shift = (3 - x&3) << 1
+	)
+	p.Pix[i] = p.Pix[i]&^(3<<shift) | byte(v)<<shift
+}
+
+func (p gray2Pixels) fill(r image.Rectangle, v uint32) {
+	fillPacked(p, &p.Buffer, r, 4, v, byte(v*0x55))
+}
+
+func (p gray2Pixels) copy(r image.Rectangle, src image.Image, sp image.Point) bool {
+	s, ok := src.(*pixel.Gray2Image)
+	if ok {
+		copyPacked(p, &p.Buffer, gray2Pixels{s}, &s.Buffer, r, sp, 4)
+	}
+	return ok
+}
+
+type gray4Pixels struct {
+	*pixel.Gray4Image
+}
+
+func (gray4Pixels) value(c color.Color) uint32 {
+	return uint32(pixel.Gray4Model.Convert(c).(pixel.Gray4).Y & 0xf)
+}
+
+func (gray4Pixels) convert(r, g, b uint32) uint32 {
+	return luma(r, g, b) >> 4 & 0xf
+}
+
+func (gray4Pixels) rgba(v uint32) (r, g, b uint32) {
+	v *= 0x1111
+	return v, v, v
+}
+
+func (p gray4Pixels) at(x, y int) uint32 {
+	return uint32(p.Pix[y*p.Stride+x>>1]>>((1-x&1)<<2)) & 0xf
+}
+
+func (p gray4Pixels) set(x, y int, v uint32) {
+	var (
+		i     = y*p.Stride + x>>1
+		shift = (1 - x&1) << 2
+	)
+	p.Pix[i] = p.Pix[i]&^(0xf<<shift) | byte(v)<<shift
+}
+
+func (p gray4Pixels) fill(r image.Rectangle, v uint32) {
+	fillPacked(p, &p.Buffer, r, 2, v, byte(v*0x11))
+}
+
+func (p gray4Pixels) copy(r image.Rectangle, src image.Image, sp image.Point) bool {
+	s, ok := src.(*pixel.Gray4Image)
+	if ok {
+		copyPacked(p, &p.Buffer, gray4Pixels{s}, &s.Buffer, r, sp, 2)
+	}
+	return ok
+}
+
+// rgb16Pixels are the pixel values of the 15- and 16-bit color images.
+type rgb16Pixels struct {
+	*pixel.Buffer
+	order binary.ByteOrder
+	big   bool   // big endian byte order
+	mask  uint32 // valid bits
+}
+
+func newRGB16Pixels(b *pixel.Buffer, order binary.ByteOrder, mask uint32) rgb16Pixels {
+	return rgb16Pixels{
+		Buffer: b,
+		order:  order,
+		big:    order == binary.ByteOrder(binary.BigEndian),
+		mask:   mask,
+	}
+}
+
+func (p rgb16Pixels) at(x, y int) uint32 {
+	i := y*p.Stride + x*2
+	if p.big {
+		return (uint32(p.Pix[i])<<8 | uint32(p.Pix[i+1])) & p.mask
+	}
+	return uint32(p.order.Uint16(p.Pix[i:])) & p.mask
+}
+
+func (p rgb16Pixels) set(x, y int, v uint32) {
+	i := y*p.Stride + x*2
+	if p.big {
+		p.Pix[i], p.Pix[i+1] = byte(v>>8), byte(v)
+		return
+	}
+	p.order.PutUint16(p.Pix[i:], uint16(v))
+}
+
+func (p rgb16Pixels) fill(r image.Rectangle, v uint32) {
+	if r.Empty() {
+		return
+	}
+	// Fill the first row, and copy it to the other rows.
+	for x := r.Min.X; x < r.Max.X; x++ {
+		p.set(x, r.Min.Y, v)
+	}
+	var (
+		i   = r.Min.Y*p.Stride + r.Min.X*2
+		row = p.Pix[i : i+r.Dx()*2]
+	)
+	for y := r.Min.Y + 1; y < r.Max.Y; y++ {
+		i += p.Stride
+		copy(p.Pix[i:], row)
+	}
+}
+
+// copyFrom copies the pixels from an image of the same type.
+func (p rgb16Pixels) copyFrom(r image.Rectangle, from rgb16Pixels, sp image.Point) {
+	if from.order == p.order && p.mask == 0xffff {
+		for y, sy := r.Min.Y, sp.Y; y < r.Max.Y; y, sy = y+1, sy+1 {
+			i, j := y*p.Stride+r.Min.X*2, sy*from.Stride+sp.X*2
+			copy(p.Pix[i:i+r.Dx()*2], from.Pix[j:j+r.Dx()*2])
+		}
+		return
+	}
+	for y, sy := r.Min.Y, sp.Y; y < r.Max.Y; y, sy = y+1, sy+1 {
+		for x, sx := r.Min.X, sp.X; x < r.Max.X; x, sx = x+1, sx+1 {
+			p.set(x, y, from.at(sx, sy))
+		}
+	}
+}
+
+// expand5 and expand6 return the 16-bit component of a 5- or 6-bit value, like the color types do.
+func expand5(v uint32) uint32 {
+	v = v<<3 | v>>2
+	return v | v<<8
+}
+
+func expand6(v uint32) uint32 {
+	v = v<<2 | v>>4
+	return v | v<<8
+}
+
+type cbgr15Pixels struct {
+	rgb16Pixels
+}
+
+func (cbgr15Pixels) value(c color.Color) uint32 {
+	return uint32(pixel.CBGR15Model.Convert(c).(pixel.CBGR15).V)
+}
+
+func (cbgr15Pixels) convert(r, g, b uint32) uint32 {
+	return (b>>11)<<10 | (g>>11)<<5 | r>>11
+}
+
+func (cbgr15Pixels) rgba(v uint32) (r, g, b uint32) {
+	return expand5(v & 0x1f), expand5(v >> 5 & 0x1f), expand5(v >> 10 & 0x1f)
+}
+
+func (p cbgr15Pixels) copy(r image.Rectangle, src image.Image, sp image.Point) bool {
+	s, ok := src.(*pixel.CBGR15Image)
+	if ok {
+		p.copyFrom(r, newRGB16Pixels(&s.Buffer, s.Order, 0x7fff), sp)
+	}
+	return ok
+}
+
+type crgb15Pixels struct {
+	rgb16Pixels
+}
+
+func (crgb15Pixels) value(c color.Color) uint32 {
+	return uint32(pixel.CRGB15Model.Convert(c).(pixel.CRGB15).V)
+}
+
+func (crgb15Pixels) convert(r, g, b uint32) uint32 {
+	return (r>>11)<<10 | (g>>11)<<5 | b>>11
+}
+
+func (crgb15Pixels) rgba(v uint32) (r, g, b uint32) {
+	return expand5(v >> 10 & 0x1f), expand5(v >> 5 & 0x1f), expand5(v & 0x1f)
+}
+
+func (p crgb15Pixels) copy(r image.Rectangle, src image.Image, sp image.Point) bool {
+	s, ok := src.(*pixel.CRGB15Image)
+	if ok {
+		p.copyFrom(r, newRGB16Pixels(&s.Buffer, s.Order, 0x7fff), sp)
+	}
+	return ok
+}
+
+type cbgr16Pixels struct {
+	rgb16Pixels
+}
+
+func (cbgr16Pixels) value(c color.Color) uint32 {
+	return uint32(pixel.CBGR16Model.Convert(c).(pixel.CBGR16).V)
+}
+
+func (cbgr16Pixels) convert(r, g, b uint32) uint32 {
+	return (b>>11)<<11 | (g>>10)<<5 | r>>11
+}
+
+func (cbgr16Pixels) rgba(v uint32) (r, g, b uint32) {
+	return expand5(v & 0x1f), expand6(v >> 5 & 0x3f), expand5(v >> 11)
+}
+
+func (p cbgr16Pixels) copy(r image.Rectangle, src image.Image, sp image.Point) bool {
+	s, ok := src.(*pixel.CBGR16Image)
+	if ok {
+		p.copyFrom(r, newRGB16Pixels(&s.Buffer, s.Order, 0xffff), sp)
+	}
+	return ok
+}
+
+type crgb16Pixels struct {
+	rgb16Pixels
+}
+
+func (crgb16Pixels) value(c color.Color) uint32 {
+	return uint32(pixel.CRGB16Model.Convert(c).(pixel.CRGB16).V)
+}
+
+func (crgb16Pixels) convert(r, g, b uint32) uint32 {
+	return (r>>11)<<11 | (g>>10)<<5 | b>>11
+}
+
+func (crgb16Pixels) rgba(v uint32) (r, g, b uint32) {
+	return expand5(v >> 11), expand6(v >> 5 & 0x3f), expand5(v & 0x1f)
+}
+
+func (p crgb16Pixels) copy(r image.Rectangle, src image.Image, sp image.Point) bool {
+	s, ok := src.(*pixel.CRGB16Image)
+	if ok {
+		p.copyFrom(r, newRGB16Pixels(&s.Buffer, s.Order, 0xffff), sp)
+	}
+	return ok
+}
@@ -0,0 +1,95 @@
+package draw
+
+import (
+	"image"
+	"image/color"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/math/fixed"
+)
+
+// Align is the alignment of text in a rectangle, a horizontal alignment combined with a vertical alignment.
+// The zero Align is left and top aligned.
+type Align uint8
+
+// Horizontal alignments, relative to the bounds of the glyphs.
+const (
+	AlignLeft Align = iota
+	AlignCenter
+	AlignRight
+)
+
+// Vertical alignments, relative to the ascent and descent of the face.
+const (
+	AlignTop Align = iota << 2
+	AlignMiddle
+	AlignBottom
+)
+
+const alignHorizontal = 3
+
+// Text draws s with face in color c, starting with the dot at pt on the baseline, and returns the dot
+// after the last glyph. Runes missing from the face are drawn with its fallback glyph, if it has one.
+//
+// Glyphs are drawn with [DrawMask], so fonts with [image.Alpha] masks are drawn directly into pixel
+// images, and bitmap fonts stay crisp on monochrome displays.
+func Text(dst Image, pt image.Point, face font.Face, c color.Color, s string) image.Point {
+	return text(dst, dst.Bounds(), pt, face, c, s)
+}
+
+// TextAligned draws s with face in color c aligned in r, and returns the dot after the last glyph. Glyphs
+// outside r are clipped.
+func TextAligned(dst Image, r image.Rectangle, face font.Face, c color.Color, s string, align Align) image.Point {
+	var (
+		bounds, _ = MeasureText(face, s)
+		metrics   = face.Metrics()
+		ascent    = metrics.Ascent.Ceil()
+		descent   = metrics.Descent.Ceil()
+		pt        image.Point
+	)
+	switch align & alignHorizontal {
+	case AlignCenter:
+		pt.X = r.Min.X + (r.Dx()-bounds.Dx())/2 - bounds.Min.X
+	case AlignRight:
+		pt.X = r.Max.X - bounds.Max.X
+	default:
+		pt.X = r.Min.X - bounds.Min.X
+	}
+	switch align &^ alignHorizontal {
+	case AlignMiddle:
+		pt.Y = r.Min.Y + (r.Dy()-ascent-descent)/2 + ascent
+	case AlignBottom:
+		pt.Y = r.Max.Y - descent
+	default:
+		pt.Y = r.Min.Y + ascent
+	}
+	return text(dst, r.Intersect(dst.Bounds()), pt, face, c, s)
+}
+
+// MeasureText returns the bounds of the glyphs of s drawn with face, relative to the dot at the start of the
+// baseline, and the advance of the dot.
+func MeasureText(face font.Face, s string) (bounds image.Rectangle, advance int) {
+	b, a := font.BoundString(face, s)
+	return image.Rect(b.Min.X.Floor(), b.Min.Y.Floor(), b.Max.X.Ceil(), b.Max.Y.Ceil()), a.Round()
+}
+
+// text draws s clipped to clip.
+func text(dst Image, clip image.Rectangle, pt image.Point, face font.Face, c color.Color, s string) image.Point {
+	var (
+		src  = image.NewUniform(c)
+		dot  = fixed.P(pt.X, pt.Y)
+		prev = rune(-1)
+	)
+	for _, r := range s {
+		if prev >= 0 {
+			dot.X += face.Kern(prev, r)
+		}
+		dr, mask, mp, advance, _ := face.Glyph(dot, r)
+		if cr := dr.Intersect(clip); mask != nil && !cr.Empty() {
+			DrawMask(dst, cr, src, image.Point{}, mask, mp.Add(cr.Min.Sub(dr.Min)), Over)
+		}
+		dot.X += advance
+		prev = r
+	}
+	return image.Pt(dot.X.Round(), pt.Y)
+}
@@ -0,0 +1,101 @@
+package draw
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+
+	"github.com/BeatGlow/display/pixel"
+)
+
+func TestText(t *testing.T) {
+	face := basicfont.Face7x13
+	for _, dst := range destinations {
+		var (
+			got  = dst.New(64, 16)
+			want = dst.New(64, 16)
+		)
+		dot := Text(got, image.Pt(-3, 11), face, color.White, "Hello, ✓")
+		(&font.Drawer{
+			Dst:  opaque{want},
+			Src:  image.NewUniform(color.White),
+			Face: face,
+			Dot:  fixed.P(-3, 11),
+		}).DrawString("Hello, ✓")
+		if !equal(got, want) {
+			t.Errorf("%s: result differs from font.Drawer", dst.Name)
+		}
+		if want := image.Pt(-3+8*7, 11); dot != want {
+			t.Errorf("%s: expected the dot at %v, got %v", dst.Name, want, dot)
+		}
+	}
+}
+
+func TestMeasureText(t *testing.T) {
+	bounds, advance := MeasureText(basicfont.Face7x13, "abc")
+	if want := image.Rect(0, -11, 20, 2); bounds != want {
+		t.Errorf("expected bounds %v, got %v", want, bounds)
+	}
+	if advance != 21 {
+		t.Errorf("expected advance 21, got %d", advance)
+	}
+	if bounds, advance := MeasureText(basicfont.Face7x13, ""); !bounds.Empty() || advance != 0 {
+		t.Errorf("expected empty bounds for an empty string, got %v and %d", bounds, advance)
+	}
+}
+
+// ink returns the bounds of the set pixels of i.
+func ink(i image.Image) (r image.Rectangle) {
+	b := i.Bounds()
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			if i.At(x, y) == pixel.On {
+				r = r.Union(image.Rect(x, y, x+1, y+1))
+			}
+		}
+	}
+	return
+}
+
+func TestTextAligned(t *testing.T) {
+	// A face with solid 7×13 pixel glyphs, 11 pixels above the baseline.
+	var (
+		face = &basicfont.Face{
+			Advance: 7,
+			Width:   7,
+			Height:  13,
+			Ascent:  11,
+			Descent: 2,
+			Mask:    image.Opaque,
+			Ranges:  []basicfont.Range{{Low: '█', High: '█' + 1}},
+		}
+		r = image.Rect(10, 4, 50, 30)
+	)
+	for _, test := range []struct {
+		Align Align
+		Want  image.Rectangle
+	}{
+		{AlignLeft | AlignTop, image.Rect(10, 4, 24, 17)},
+		{AlignCenter | AlignTop, image.Rect(23, 4, 37, 17)},
+		{AlignRight | AlignTop, image.Rect(36, 4, 50, 17)},
+		{AlignLeft | AlignMiddle, image.Rect(10, 10, 24, 23)},
+		{AlignRight | AlignBottom, image.Rect(36, 17, 50, 30)},
+	} {
+		dst := pixel.NewMonoImage(64, 32)
+		TextAligned(dst, r, face, pixel.On, "██", test.Align)
+		if got := ink(dst); got != test.Want {
+			t.Errorf("align %#x: expected text at %v, got %v", test.Align, test.Want, got)
+		}
+	}
+
+	// Text is clipped to the rectangle.
+	dst := pixel.NewMonoImage(64, 32)
+	TextAligned(dst, image.Rect(2, 2, 12, 8), face, pixel.On, "██", AlignLeft|AlignMiddle)
+	if got, want := ink(dst), image.Rect(2, 2, 12, 8); got != want {
+		t.Errorf("expected text clipped to %v, got %v", want, got)
+	}
+}
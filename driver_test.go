@@ -0,0 +1,169 @@
+package display
+
+import (
+	"errors"
+	"image"
+	"reflect"
+	"testing"
+
+	"github.com/BeatGlow/display/conn"
+	"github.com/BeatGlow/display/conntest"
+	"github.com/BeatGlow/display/pixel"
+)
+
+func TestDriverTraffic(t *testing.T) {
+	tests := []struct {
+		Name string
+		// Size of a full frame in bytes.
+		FrameSize int
+		// Mode is the expected SPI mode, if any.
+		Mode conn.SPIMode
+	}{
+		{"ssd1306", 128 * 64 / 8, 0},
+		{"sh1106", 128 * 64 / 8, 0},
+		{"ssd1305", 128 * 32 / 8, 0},
+		{"ssd1322", 256 * 64 / 2, conn.SPIMode3},
+		{"sh1122", 256 * 64 / 2, conn.SPIMode0},
+		{"st7735", 128 * 160 * 2, conn.SPIMode3},
+		{"st7789", 240 * 240 * 2, conn.SPIMode3},
+		{"gp1287", 256 * 56 / 8, 0},
+		{"gu3000", 256 * 128 / 8, 0},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(it *testing.T) {
+			c := conntest.NewSPIRecorder()
+			d, err := Open(test.Name, c, &Config{})
+			if err != nil {
+				it.Fatal(err)
+			}
+			if c.Mode != test.Mode {
+				it.Errorf("expected SPI mode %d, got %d", test.Mode, c.Mode)
+			}
+			if len(c.Filter(conntest.OpCommand, conntest.OpData)) == 0 {
+				it.Error("expected init traffic")
+			}
+
+			c.Clear()
+			if err = d.Refresh(); err != nil {
+				it.Fatal(err)
+			}
+			if n := c.DataLen(); n < test.FrameSize {
+				it.Errorf("expected refresh to send at least %d bytes, got %d", test.FrameSize, n)
+			}
+
+			full := c.DataLen()
+			c.Clear()
+			if err = RefreshRect(d, image.Rect(8, 8, 16, 16)); err != nil {
+				it.Fatal(err)
+			}
+			if _, ok := d.(WindowRefresher); !ok {
+				it.Fatal("expected driver to support partial refreshes")
+			}
+			if n := c.DataLen(); test.Name != "gu3000" && n >= full {
+				it.Errorf("expected partial refresh to send less than %d bytes, got %d", full, n)
+			}
+		})
+	}
+}
+
+func TestDriverError(t *testing.T) {
+	c := conntest.NewRecorder()
+	c.Err = errors.New("test")
+	if _, err := SSD1306(c, &Config{}); !errors.Is(err, c.Err) {
+		t.Errorf("expected connection error, got %v", err)
+	}
+}
+
+func TestSSD1306RefreshRect(t *testing.T) {
+	c := conntest.NewRecorder()
+	d, err := SSD1306(c, &Config{Width: 128, Height: 32})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d.Set(20, 10, pixel.On)
+	c.Clear()
+	if err = d.(WindowRefresher).RefreshRect(image.Rect(18, 9, 22, 11)); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []conntest.Call{
+		{Op: conntest.OpCommand, Command: ssd1xxxSetColumnAddr, Data: []byte{18, 21, ssd1xxxSetPageAddr, 1, 1}},
+		{Op: conntest.OpData, Data: []byte{0x00, 0x00, 0x04, 0x00}},
+	}
+	calls := c.Calls()
+	for i := range calls {
+		calls[i].Time = want[0].Time
+	}
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("expected calls:\n%v\ngot:\n%v", want, calls)
+	}
+}
+
+func TestSSD1306Rotation(t *testing.T) {
+	c := conntest.NewRecorder()
+	d, err := SSD1306(c, &Config{Rotation: Rotate180})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var flipped bool
+	for _, call := range c.Filter(conntest.OpCommand) {
+		if call.Command == ssd1xxxSetRemap && len(call.Data) == 1 && call.Data[0] == ssd1xxxSetComScanInc {
+			flipped = true
+		}
+	}
+	if !flipped {
+		t.Error("expected segment remap and COM scan direction to be reversed")
+	}
+	if v := d.Bounds().Size(); !v.Eq(image.Pt(128, 64)) {
+		t.Errorf("expected size 128x64, got %s", v)
+	}
+
+	if err = d.SetRotation(Rotate90); err != nil {
+		t.Fatal(err)
+	}
+	if v := d.Bounds().Size(); !v.Eq(image.Pt(64, 128)) {
+		t.Errorf("expected size 64x128, got %s", v)
+	}
+}
+
+func TestSSD1305Rotation(t *testing.T) {
+	c := conntest.NewRecorder()
+	d, err := SSD1305(c, &Config{Rotation: Rotate180})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The 32 rows of the panel are the last rows of the 64 row RAM when the COM scan direction is reversed.
+	offset := func() []byte {
+		var data []byte
+		for _, call := range c.Filter(conntest.OpCommand) {
+			if call.Command == ssd1xxxSetDisplayOffset {
+				data = call.Data
+			}
+		}
+		return data
+	}
+	if v := offset(); string(v) != "\x20" {
+		t.Errorf("rotated 180°: expected display offset 32, got %v", v)
+	}
+
+	o := d.(Offsetter)
+	if err = o.SetOffset(0, 8); err != nil {
+		t.Fatal(err)
+	}
+	if v := offset(); string(v) != "\x28" {
+		t.Errorf("rotated 180° with offset: expected display offset 40, got %v", v)
+	}
+
+	if err = o.SetOffset(0, 0); err != nil {
+		t.Fatal(err)
+	}
+	if err = d.SetRotation(NoRotation); err != nil {
+		t.Fatal(err)
+	}
+	if v := offset(); string(v) != "\x00" {
+		t.Errorf("not rotated: expected display offset 0, got %v", v)
+	}
+}
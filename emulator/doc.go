@@ -0,0 +1,11 @@
+// Package emulator provides display controller emulators for testing display drivers without hardware.
+//
+// Each emulator implements the display.Conn and display.SPI interfaces, interprets the command and data
+// stream sent by a driver the way the controller would, and maintains a virtual graphics RAM. The Image
+// method returns what the panel would show, taking the controller's addressing, remap, scan direction and
+// offset settings into account.
+//
+// Emulators are available for page addressed monochrome controllers ([NewSSD1306], [NewSH1106] and
+// [NewSSD1305]), grayscale controllers ([NewSSD1322] and [NewSH1122]), TFT controllers ([NewST7735] and
+// [NewST7789]) and VFD controllers ([NewGP1294] and [NewGP1287]).
+package emulator
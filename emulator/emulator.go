@@ -0,0 +1,122 @@
+package emulator
+
+import (
+	"image"
+	"sync"
+
+	"periph.io/x/conn/v3/gpio"
+
+	"github.com/BeatGlow/display/conn"
+)
+
+// Emulator is a display controller emulator.
+type Emulator interface {
+	String() string
+
+	// Close the connection.
+	Close() error
+
+	// Reset sets the reset pin to the provided level, the controller is reset on a low level.
+	Reset(gpio.Level) error
+
+	// Command sends a command byte with optional arguments.
+	Command(byte, ...byte) error
+
+	// Data sends data bytes.
+	Data(...byte) error
+
+	// Interface is the underlying interface.
+	Interface() interface{}
+
+	// SetDataLow changes the data/command direction behaviour.
+	SetDataLow(bool)
+
+	// SetMode requests a SPI mode.
+	SetMode(mode conn.SPIMode) error
+
+	// SetMaxSpeed requests a SPI speed.
+	SetMaxSpeed(hz int) error
+
+	// Image returns what the panel would show.
+	Image() image.Image
+}
+
+// bus implements the connection calls that don't affect the controller state.
+type bus struct {
+	mu       sync.Mutex
+	dataLow  bool
+	mode     conn.SPIMode
+	maxSpeed int
+	closed   bool
+}
+
+func (b *bus) Close() error {
+	b.mu.Lock()
+	b.closed = true
+	b.mu.Unlock()
+	return nil
+}
+
+func (b *bus) SetDataLow(v bool) {
+	b.mu.Lock()
+	b.dataLow = v
+	b.mu.Unlock()
+}
+
+func (b *bus) SetMode(mode conn.SPIMode) error {
+	b.mu.Lock()
+	b.mode = mode
+	b.mu.Unlock()
+	return nil
+}
+
+func (b *bus) SetMaxSpeed(hz int) error {
+	b.mu.Lock()
+	b.maxSpeed = hz
+	b.mu.Unlock()
+	return nil
+}
+
+// Mode returns the last requested SPI mode.
+func (b *bus) Mode() conn.SPIMode {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.mode
+}
+
+// MaxSpeed returns the last requested SPI speed in Hz.
+func (b *bus) MaxSpeed() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.maxSpeed
+}
+
+// Closed returns if the connection was closed.
+func (b *bus) Closed() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.closed
+}
+
+// parser splits a command stream into commands with their arguments.
+type parser struct {
+	arity   func(byte) int
+	pending []byte
+}
+
+// feed adds a command byte and calls exec for every complete command.
+func (p *parser) feed(b byte, exec func(command byte, args []byte)) {
+	p.pending = append(p.pending, b)
+	if len(p.pending)-1 >= p.arity(p.pending[0]) {
+		exec(p.pending[0], p.pending[1:])
+		p.pending = p.pending[:0]
+	}
+}
+
+func (p *parser) reset() {
+	p.pending = p.pending[:0]
+}
+
+func gray(v uint8) uint8 {
+	return v<<4 | v
+}
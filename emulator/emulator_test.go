@@ -0,0 +1,399 @@
+package emulator_test
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/BeatGlow/display"
+	"github.com/BeatGlow/display/conntest"
+	"github.com/BeatGlow/display/emulator"
+)
+
+func TestDrivers(t *testing.T) {
+	tests := []struct {
+		Name      string
+		Driver    string
+		Emulator  func() emulator.Emulator
+		Width     int
+		Height    int
+		Rotations []display.Rotation
+	}{
+		{"ssd1306", "ssd1306", func() emulator.Emulator { return emulator.NewSSD1306(128, 64) }, 0, 0, allRotations},
+		{"ssd1306 128x32", "ssd1306", func() emulator.Emulator { return emulator.NewSSD1306(128, 32) }, 128, 32, allRotations},
+		{"sh1106", "sh1106", func() emulator.Emulator { return emulator.NewSH1106(128, 64) }, 0, 0, allRotations},
+		{"sh1106 128x32", "sh1106", func() emulator.Emulator { return emulator.NewSH1106(128, 32) }, 128, 32, allRotations},
+		{"ssd1305", "ssd1305", func() emulator.Emulator { return emulator.NewSSD1305(128, 32) }, 0, 0, allRotations},
+		{"ssd1305 128x64", "ssd1305", func() emulator.Emulator { return emulator.NewSSD1305(128, 64) }, 128, 64, allRotations},
+		{"ssd1322", "ssd1322", func() emulator.Emulator { return emulator.NewSSD1322(256, 64) }, 0, 0, allRotations},
+		{"sh1122", "sh1122", func() emulator.Emulator { return emulator.NewSH1122(256, 64) }, 0, 0, allRotations},
+		{"st7735", "st7735", func() emulator.Emulator { return emulator.NewST7735(128, 160) }, 0, 0, allRotations},
+		{"st7789", "st7789", func() emulator.Emulator { return emulator.NewST7789(240, 240) }, 0, 0, allRotations},
+		{"st7789 240x320", "st7789", func() emulator.Emulator { return emulator.NewST7789(240, 320) }, 240, 320, allRotations},
+		{"gp1294", "gp1294", func() emulator.Emulator { return emulator.NewGP1294(256, 48) }, 0, 0, allRotations},
+		{"gp1287", "gp1287", func() emulator.Emulator { return emulator.NewGP1287(256, 50) }, 0, 0, allRotations},
+	}
+	for _, test := range tests {
+		for _, rotation := range test.Rotations {
+			t.Run(test.Name+" "+rotation.String(), func(it *testing.T) {
+				it.Parallel()
+
+				var (
+					e      = test.Emulator()
+					config = &display.Config{
+						Width:     test.Width,
+						Height:    test.Height,
+						Rotation:  rotation,
+						Backlight: conntest.NewPin("BL"),
+					}
+				)
+				if swapped(test.Driver, rotation) && test.Width != 0 {
+					config.Width, config.Height = test.Height, test.Width
+				}
+				d, err := display.Open(test.Driver, e, config)
+				if err != nil {
+					it.Fatal(err)
+				}
+
+				fill(d, d.Bounds(), 1)
+				if err = d.Refresh(); err != nil {
+					it.Fatal(err)
+				}
+				compare(it, "refresh", d, e.Image(), rotation)
+
+				fill(d, image.Rect(5, 3, 37, 21), 2)
+				if err = display.RefreshRect(d, image.Rect(5, 3, 37, 21)); err != nil {
+					it.Fatal(err)
+				}
+				compare(it, "partial refresh", d, e.Image(), rotation)
+
+				if err = d.Refresh(); err != nil {
+					it.Fatal(err)
+				}
+				compare(it, "second refresh", d, e.Image(), rotation)
+			})
+		}
+	}
+}
+
+var allRotations = []display.Rotation{display.NoRotation, display.Rotate90, display.Rotate180, display.Rotate270}
+
+// swapped returns if the driver expects the rotated size, instead of the native panel size.
+func swapped(driver string, rotation display.Rotation) bool {
+	switch driver {
+	case "st7735", "st7789":
+		return rotation == display.Rotate90 || rotation == display.Rotate270
+	default:
+		return false
+	}
+}
+
+var palette = []color.Color{
+	color.Black,
+	color.White,
+	color.RGBA{R: 0xff, A: 0xff},
+	color.RGBA{G: 0xff, A: 0xff},
+	color.RGBA{B: 0xff, A: 0xff},
+	color.Gray{Y: 0x40},
+	color.Gray{Y: 0x80},
+	color.Gray{Y: 0xc0},
+}
+
+// fill r with a pseudo random pattern.
+func fill(d display.Display, r image.Rectangle, seed uint32) {
+	r = r.Intersect(d.Bounds())
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			v := uint32(x)*73856093 ^ uint32(y)*19349663 ^ seed*83492791
+			d.Set(x, y, palette[(v>>7)%uint32(len(palette))])
+		}
+	}
+}
+
+// compare the display frame buffer with the panel image.
+func compare(t *testing.T, name string, d display.Display, panel image.Image, rotation display.Rotation) {
+	t.Helper()
+
+	var (
+		b      = d.Bounds()
+		w, h   = panel.Bounds().Dx(), panel.Bounds().Dy()
+		errors int
+	)
+	if rotation == display.Rotate90 || rotation == display.Rotate270 {
+		if b.Dx() != h || b.Dy() != w {
+			t.Fatalf("%s: expected %dx%d display, got %dx%d", name, h, w, b.Dx(), b.Dy())
+		}
+	} else if b.Dx() != w || b.Dy() != h {
+		t.Fatalf("%s: expected %dx%d display, got %dx%d", name, w, h, b.Dx(), b.Dy())
+	}
+
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			px, py := x, y
+			switch rotation {
+			case display.Rotate90:
+				px, py = w-1-y, x
+			case display.Rotate180:
+				px, py = w-1-x, h-1-y
+			case display.Rotate270:
+				px, py = y, h-1-x
+			}
+			if want, got := rgb(d.At(x, y)), rgb(panel.At(px, py)); want != got {
+				if errors++; errors <= 5 {
+					t.Errorf("%s: pixel %d,%d (panel %d,%d): expected %06x, got %06x", name, x, y, px, py, want, got)
+				}
+			}
+		}
+	}
+	if errors > 5 {
+		t.Errorf("%s: %d pixels differ", name, errors)
+	}
+}
+
+func rgb(c color.Color) uint32 {
+	r, g, b, _ := c.RGBA()
+	return (r>>8)<<16 | (g>>8)<<8 | b>>8
+}
+
+func TestMonoAddressing(t *testing.T) {
+	e := emulator.NewSSD1306(128, 64)
+	_ = e.Command(
+		0xAF,       // display on
+		0xA1, 0xC8, // upright
+		0x20, 0x00, // horizontal addressing
+		0x21, 126, 127, // columns 126-127
+		0x22, 0, 1, // pages 0-1
+	)
+	_ = e.Data(0x01, 0x02, 0x80, 0x40)
+
+	i := e.Image()
+	for _, test := range []struct {
+		X, Y int
+		On   bool
+	}{
+		{126, 0, true},
+		{127, 1, true},
+		{126, 15, true},
+		{127, 14, true},
+		{125, 0, false},
+		{126, 1, false},
+	} {
+		if v := rgb(i.At(test.X, test.Y)) != 0; v != test.On {
+			t.Errorf("pixel %d,%d: expected %t, got %t", test.X, test.Y, test.On, v)
+		}
+	}
+}
+
+func TestVFD(t *testing.T) {
+	e := emulator.NewGP1294(256, 48)
+	rev := func(b byte) byte {
+		var r byte
+		for i := 0; i < 8; i++ {
+			r = r<<1 | (b>>i)&1
+		}
+		return r
+	}
+	// Write GRAM at 10,8 with 16 rows.
+	_ = e.Command(rev(0xF0), rev(10), rev(8), rev(15), rev(0x01), rev(0x80))
+	_ = e.Data(rev(0xff))
+
+	i := e.Image()
+	for _, test := range []struct {
+		X, Y int
+		On   bool
+	}{
+		{10, 8, true},
+		{10, 9, false},
+		{10, 23, true},
+		{11, 8, true},
+		{11, 15, true},
+		{11, 16, false},
+	} {
+		if v := rgb(i.At(test.X, test.Y)) != 0; v != test.On {
+			t.Errorf("pixel %d,%d: expected %t, got %t", test.X, test.Y, test.On, v)
+		}
+	}
+}
+
+func TestCapabilities(t *testing.T) {
+	tests := []struct {
+		Driver   string
+		Emulator func() emulator.Emulator
+	}{
+		{"ssd1306", func() emulator.Emulator { return emulator.NewSSD1306(128, 64) }},
+		{"sh1106", func() emulator.Emulator { return emulator.NewSH1106(128, 64) }},
+		{"ssd1305", func() emulator.Emulator { return emulator.NewSSD1305(128, 32) }},
+		{"ssd1322", func() emulator.Emulator { return emulator.NewSSD1322(256, 64) }},
+		{"sh1122", func() emulator.Emulator { return emulator.NewSH1122(256, 64) }},
+		{"gp1294", func() emulator.Emulator { return emulator.NewGP1294(256, 48) }},
+		{"gp1287", func() emulator.Emulator { return emulator.NewGP1287(256, 50) }},
+	}
+	for _, test := range tests {
+		t.Run(test.Driver, func(it *testing.T) {
+			e := test.Emulator()
+			d, err := display.Open(test.Driver, e, &display.Config{Backlight: conntest.NewPin("BL")})
+			if err != nil {
+				it.Fatal(err)
+			}
+			d.Set(3, 10, color.White)
+			if err = d.Refresh(); err != nil {
+				it.Fatal(err)
+			}
+
+			if s, ok := d.(display.Scroller); ok {
+				if err = s.Scroll(8); err != nil {
+					it.Fatal(err)
+				}
+				if i := e.Image(); rgb(i.At(3, 2)) == 0 || rgb(i.At(3, 10)) != 0 {
+					it.Error("scroll: expected pixel 3,10 to move to 3,2")
+				}
+				if err = s.Scroll(0); err != nil {
+					it.Fatal(err)
+				}
+			}
+
+			if o, ok := d.(display.Offsetter); ok {
+				if err = o.SetOffset(0, 8); err != nil {
+					it.Fatal(err)
+				}
+				if i := e.Image(); rgb(i.At(3, 2)) == 0 || rgb(i.At(3, 10)) != 0 {
+					it.Error("offset: expected pixel 3,10 to move to 3,2")
+				}
+				if err = o.SetOffset(1, 0); errors.Is(err, errors.ErrUnsupported) {
+					err = nil
+				} else if i := e.Image(); err == nil && (rgb(i.At(2, 10)) == 0 || rgb(i.At(3, 10)) != 0) {
+					it.Error("offset: expected pixel 3,10 to move to 2,10")
+				}
+				if err != nil {
+					it.Fatal(err)
+				}
+				if err = o.SetOffset(0, 0); err != nil {
+					it.Fatal(err)
+				}
+			}
+
+			inv, ok := d.(display.Inverter)
+			if !ok {
+				it.Fatal("expected driver to be an Inverter")
+			}
+			if err = inv.SetInvert(true); err != nil {
+				it.Fatal(err)
+			}
+			if i := e.Image(); rgb(i.At(3, 10)) != 0 || rgb(i.At(0, 0)) == 0 {
+				it.Error("invert: expected inverted pixels")
+			}
+			if err = inv.SetInvert(false); err != nil {
+				it.Fatal(err)
+			}
+			if i := e.Image(); rgb(i.At(3, 10)) == 0 || rgb(i.At(0, 0)) != 0 {
+				it.Error("normal: expected normal pixels")
+			}
+		})
+	}
+}
+
+func TestSSD1306Effects(t *testing.T) {
+	e := emulator.NewSSD1306(128, 64)
+	d, err := display.Open("ssd1306", e, &display.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.Set(3, 10, color.White)
+	if err = d.Refresh(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = d.(display.Zoomer).SetZoom(true); err != nil {
+		t.Fatal(err)
+	}
+	if i := e.Image(); rgb(i.At(3, 20)) == 0 || rgb(i.At(3, 21)) == 0 || rgb(i.At(3, 10)) != 0 {
+		t.Error("zoom: expected pixel 3,10 to show at 3,20 and 3,21")
+	}
+	if err = d.(display.Zoomer).SetZoom(false); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = d.(display.HardwareScroller).StartScroll(display.HardwareScroll{EndPage: 7}); err != nil {
+		t.Fatal(err)
+	}
+	if !e.Scrolling() {
+		t.Error("expected scrolling to be activated")
+	}
+	if err = d.(display.HardwareScroller).StopScroll(); err != nil {
+		t.Fatal(err)
+	}
+	if e.Scrolling() {
+		t.Error("expected scrolling to be deactivated")
+	}
+
+	if err = d.(display.Fader).SetFade(display.Blink, 16); err != nil {
+		t.Fatal(err)
+	}
+	if v := e.Fade(); v != 0x31 {
+		t.Errorf("expected fade setting 0x31, got %#02x", v)
+	}
+}
+
+func TestTFTCapabilities(t *testing.T) {
+	for _, driver := range []string{"st7735", "st7789"} {
+		t.Run(driver, func(it *testing.T) {
+			var e *emulator.TFT
+			if driver == "st7735" {
+				e = emulator.NewST7735(128, 160)
+			} else {
+				e = emulator.NewST7789(240, 240)
+			}
+			d, err := display.Open(driver, e, &display.Config{})
+			if err != nil {
+				it.Fatal(err)
+			}
+			initial := e.Inversion()
+
+			if driver == "st7789" {
+				if err = d.SetContrast(0x40); err != nil {
+					it.Fatal(err)
+				}
+				if v := e.Brightness(); v != 0x40 {
+					it.Errorf("expected brightness 0x40, got %#02x", v)
+				}
+			}
+
+			if err = d.(display.Inverter).SetInvert(true); err != nil {
+				it.Fatal(err)
+			}
+			if e.Inversion() == initial {
+				it.Error("expected display inversion to toggle")
+			}
+			if err = d.(display.Inverter).SetInvert(false); err != nil {
+				it.Fatal(err)
+			}
+			if e.Inversion() != initial {
+				it.Error("expected display inversion to be restored")
+			}
+
+			if err = d.(display.Sleeper).SetSleep(true); err != nil {
+				it.Fatal(err)
+			}
+			if e.On() {
+				it.Error("expected display to sleep")
+			}
+			if err = d.(display.Sleeper).SetSleep(false); err != nil {
+				it.Fatal(err)
+			}
+			if !e.On() {
+				it.Error("expected display to wake up")
+			}
+		})
+	}
+}
+
+// Interface checks
+var (
+	_ display.SPI       = (emulator.Emulator)(nil)
+	_ emulator.Emulator = (*emulator.Mono)(nil)
+	_ emulator.Emulator = (*emulator.SSD1322)(nil)
+	_ emulator.Emulator = (*emulator.SH1122)(nil)
+	_ emulator.Emulator = (*emulator.TFT)(nil)
+	_ emulator.Emulator = (*emulator.VFD)(nil)
+)
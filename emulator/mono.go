@@ -0,0 +1,330 @@
+package emulator
+
+import (
+	"fmt"
+	"image"
+
+	"periph.io/x/conn/v3/gpio"
+)
+
+// Monochrome controller commands.
+const (
+	monoSetLowColumn     = 0x00
+	monoSetHighColumn    = 0x10
+	monoSetMemoryMode    = 0x20
+	monoSetColumnAddr    = 0x21
+	monoSetPageAddr      = 0x22
+	monoSetFadeBlink     = 0x23
+	monoDeactivateScroll = 0x2E
+	monoActivateScroll   = 0x2F
+	monoSetStartLine     = 0x40
+	monoSetContrast      = 0x81
+	monoSetRemap         = 0xA0
+	monoSetAllOnResume   = 0xA4
+	monoSetAllOn         = 0xA5
+	monoSetNormal        = 0xA6
+	monoSetInvert        = 0xA7
+	monoSetMultiplex     = 0xA8
+	monoSetDisplayOff    = 0xAE
+	monoSetDisplayOn     = 0xAF
+	monoSetPage          = 0xB0
+	monoSetComScanInc    = 0xC0
+	monoSetComScanDec    = 0xC8
+	monoSetDisplayOffset = 0xD3
+	monoSetZoomIn        = 0xD6
+)
+
+// Memory addressing modes.
+const (
+	monoHorizontalMode = iota
+	monoVerticalMode
+	monoPageMode
+)
+
+const (
+	monoRows  = 64
+	monoPages = monoRows / 8
+)
+
+var (
+	// ssd1306Arity are the number of argument bytes per SSD1306 command.
+	ssd1306Arity = map[byte]int{
+		0x20: 1, 0x21: 2, 0x22: 2, 0x23: 1, 0x26: 6, 0x27: 6, 0x29: 5, 0x2A: 5, 0x81: 1, 0x8D: 1, 0xA3: 2,
+		0xA8: 1, 0xD3: 1, 0xD5: 1, 0xD6: 1, 0xD9: 1, 0xDA: 1, 0xDB: 1,
+	}
+
+	// ssd1305Arity are the number of argument bytes per SSD1305 command.
+	ssd1305Arity = map[byte]int{
+		0x20: 1, 0x21: 2, 0x22: 2, 0x26: 6, 0x27: 6, 0x29: 5, 0x2A: 5, 0x81: 1, 0x82: 1, 0x91: 4, 0x92: 3,
+		0xA3: 2, 0xA8: 1, 0xAD: 1, 0xD3: 1, 0xD5: 1, 0xD8: 1, 0xD9: 1, 0xDA: 1, 0xDB: 1,
+	}
+
+	// sh1106Arity are the number of argument bytes per SH1106 command.
+	sh1106Arity = map[byte]int{
+		0x81: 1, 0xA8: 1, 0xAD: 1, 0xD3: 1, 0xD5: 1, 0xD9: 1, 0xDA: 1, 0xDB: 1,
+	}
+)
+
+// Mono emulates a page addressed monochrome OLED controller, such as the SSD1306, SH1106 and SSD1305.
+//
+// The panel is assumed to be mounted such that the default driver settings (segment remap enabled and COM
+// scan direction reversed) show an upright image.
+//
+// On the SH1106 the display offset maps the display start line to a COM line, and panels with fewer than 64
+// rows are wired to the COM lines in the middle.
+type Mono struct {
+	bus
+	parser
+	name          string
+	width, height int
+	columns       int
+	colOffset     int
+	addressing    bool
+	comOffset     bool // display offset moves the COM lines instead of the RAM rows
+	comStart      int  // COM line of the first panel row
+	ram           []byte
+
+	// Registers.
+	mode                 int
+	column, page         int
+	colStart, colEnd     int
+	pageStart, pageEnd   int
+	startLine, offset    int
+	multiplex            int
+	segRemap, comScanDec bool
+	allOn, invert, on    bool
+	contrast             byte
+	fade                 byte
+	scrolling, zoom      bool
+}
+
+// NewSSD1306 emulates a SSD1306 controller driving a panel of the provided size.
+func NewSSD1306(width, height int) *Mono {
+	return newMono("SSD1306", width, height, 128, ssd1306Arity, true)
+}
+
+// NewSH1106 emulates a SH1106 controller driving a panel of the provided size.
+func NewSH1106(width, height int) *Mono {
+	e := newMono("SH1106", width, height, 132, sh1106Arity, false)
+	e.comOffset = true
+	if height < monoRows {
+		e.comStart = (monoRows - height - 1) / 2
+	}
+	return e
+}
+
+// NewSSD1305 emulates a SSD1305 controller driving a panel of the provided size.
+func NewSSD1305(width, height int) *Mono {
+	return newMono("SSD1305", width, height, 132, ssd1305Arity, true)
+}
+
+func newMono(name string, width, height, columns int, arity map[byte]int, addressing bool) *Mono {
+	e := &Mono{
+		parser:     parser{arity: func(command byte) int { return arity[command] }},
+		name:       name,
+		width:      width,
+		height:     height,
+		columns:    columns,
+		colOffset:  (columns - width) / 2,
+		addressing: addressing,
+		ram:        make([]byte, columns*monoPages),
+	}
+	e.reset()
+	return e
+}
+
+func (e *Mono) reset() {
+	e.parser.reset()
+	e.mode = monoPageMode
+	e.column, e.page = 0, 0
+	e.colStart, e.colEnd = 0, e.columns-1
+	e.pageStart, e.pageEnd = 0, monoPages-1
+	e.startLine, e.offset = 0, 0
+	e.multiplex = monoRows
+	e.segRemap, e.comScanDec = false, false
+	e.allOn, e.invert, e.on = false, false, false
+	e.contrast = 0x7F
+	e.fade = 0
+	e.scrolling, e.zoom = false, false
+}
+
+func (e *Mono) String() string {
+	return fmt.Sprintf("%s emulator %dx%d", e.name, e.width, e.height)
+}
+
+// Interface returns the emulator.
+func (e *Mono) Interface() interface{} {
+	return e
+}
+
+// Reset the controller registers on a low level, the RAM contents are retained.
+func (e *Mono) Reset(level gpio.Level) error {
+	if level == gpio.Low {
+		e.mu.Lock()
+		e.reset()
+		e.mu.Unlock()
+	}
+	return nil
+}
+
+// Command interprets a command stream, commands may span multiple calls.
+func (e *Mono) Command(command byte, args ...byte) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.feed(command, e.exec)
+	for _, arg := range args {
+		e.feed(arg, e.exec)
+	}
+	return nil
+}
+
+func (e *Mono) exec(command byte, args []byte) {
+	switch {
+	case command <= 0x0F:
+		e.column = e.column&0xF0 | int(command&0x0F)
+	case command <= 0x1F:
+		e.column = int(command&0x0F)<<4 | e.column&0x0F
+	case command == monoSetMemoryMode && e.addressing:
+		if mode := int(args[0] & 3); mode <= monoPageMode {
+			e.mode = mode
+		}
+	case command == monoSetColumnAddr && e.addressing:
+		e.colStart, e.colEnd = int(args[0])%e.columns, int(args[1])%e.columns
+		e.column = e.colStart
+	case command == monoSetPageAddr && e.addressing:
+		e.pageStart, e.pageEnd = int(args[0]&7), int(args[1]&7)
+		e.page = e.pageStart
+	case command >= monoSetStartLine && command <= 0x7F:
+		e.startLine = int(command & 0x3F)
+	case command == monoSetContrast:
+		e.contrast = args[0]
+	case command == monoSetRemap, command == monoSetRemap|1:
+		e.segRemap = command&1 == 1
+	case command == monoSetAllOnResume, command == monoSetAllOn:
+		e.allOn = command == monoSetAllOn
+	case command == monoSetNormal, command == monoSetInvert:
+		e.invert = command == monoSetInvert
+	case command == monoSetMultiplex:
+		if ratio := int(args[0]&0x3F) + 1; ratio >= 16 {
+			e.multiplex = ratio
+		}
+	case command == monoSetDisplayOff, command == monoSetDisplayOn:
+		e.on = command == monoSetDisplayOn
+	case command >= monoSetPage && command <= monoSetPage|7:
+		e.page = int(command & 7)
+	case command == monoSetComScanInc, command == monoSetComScanDec:
+		e.comScanDec = command == monoSetComScanDec
+	case command == monoSetDisplayOffset:
+		e.offset = int(args[0] & 0x3F)
+	case command == monoDeactivateScroll, command == monoActivateScroll:
+		e.scrolling = command == monoActivateScroll
+	case command == monoSetFadeBlink && len(args) == 1:
+		e.fade = args[0] & 0x3F
+	case command == monoSetZoomIn && len(args) == 1:
+		e.zoom = args[0]&1 == 1
+	}
+}
+
+// Data writes to the RAM and advances the address pointers according to the addressing mode.
+func (e *Mono) Data(data ...byte) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for _, b := range data {
+		if e.column < e.columns {
+			e.ram[e.page*e.columns+e.column] = b
+		}
+		switch e.mode {
+		case monoHorizontalMode:
+			if e.column++; e.column > e.colEnd {
+				e.column = e.colStart
+				if e.page++; e.page > e.pageEnd {
+					e.page = e.pageStart
+				}
+			}
+		case monoVerticalMode:
+			if e.page++; e.page > e.pageEnd {
+				e.page = e.pageStart
+				if e.column++; e.column > e.colEnd {
+					e.column = e.colStart
+				}
+			}
+		default:
+			if e.column++; e.column >= e.columns {
+				e.column = 0
+			}
+		}
+	}
+	return nil
+}
+
+// Contrast returns the contrast level.
+func (e *Mono) Contrast() byte {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.contrast
+}
+
+// Scrolling returns if continuous scrolling is activated. Scrolling itself is not emulated.
+func (e *Mono) Scrolling() bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.scrolling
+}
+
+// Fade returns the fade out and blinking setting. Fading itself is not emulated.
+func (e *Mono) Fade() byte {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.fade
+}
+
+// On returns if the display is switched on.
+func (e *Mono) On() bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.on
+}
+
+// Image returns what the panel would show, as an 8-bit gray image with pixels either on or off.
+func (e *Mono) Image() image.Image {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	i := image.NewGray(image.Rect(0, 0, e.width, e.height))
+	if !e.on {
+		return i
+	}
+	for y := 0; y < e.height; y++ {
+		scan := y
+		if e.comOffset {
+			scan = ((e.comStart+y-e.offset)%monoRows + monoRows) % monoRows
+		}
+		if !e.comScanDec {
+			scan = e.multiplex - 1 - scan
+		}
+		if scan < 0 || scan >= e.multiplex {
+			continue
+		}
+		if e.zoom {
+			scan /= 2
+		}
+		row := scan + e.startLine
+		if !e.comOffset {
+			row += e.offset
+		}
+		row %= monoRows
+		for x := 0; x < e.width; x++ {
+			column := e.colOffset + x
+			if !e.segRemap {
+				column = e.columns - 1 - column
+			}
+			on := e.ram[(row>>3)*e.columns+column]&(1<<(row&7)) != 0
+			if on != e.invert || e.allOn {
+				i.Pix[y*i.Stride+x] = 0xFF
+			}
+		}
+	}
+	return i
+}
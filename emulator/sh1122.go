@@ -0,0 +1,200 @@
+package emulator
+
+import (
+	"fmt"
+	"image"
+
+	"periph.io/x/conn/v3/gpio"
+)
+
+// SH1122 commands.
+const (
+	sh1122SetRowAddress = 0xB0
+)
+
+const (
+	sh1122Columns = 256
+	sh1122Rows    = 64
+	sh1122Stride  = sh1122Columns / 2
+)
+
+// sh1122Arity are the number of argument bytes per SH1122 command.
+var sh1122Arity = map[byte]int{
+	0x81: 1, 0xA8: 1, 0xAD: 1, 0xB0: 1, 0xD3: 1, 0xD5: 1, 0xD9: 1, 0xDB: 1, 0xDC: 1,
+}
+
+// SH1122 emulates a SH1122 4-bit grayscale OLED controller.
+//
+// The panel is assumed to be mounted such that the default driver settings (no segment remap and normal
+// COM scan direction) show an upright image.
+type SH1122 struct {
+	bus
+	parser
+	width, height int
+	colOffset     int
+	ram           []byte
+
+	// Registers.
+	column, row          int
+	startLine, offset    int
+	multiplex            int
+	segRemap, comScanDec bool
+	allOn, invert, on    bool
+	contrast             byte
+}
+
+// NewSH1122 emulates a SH1122 controller driving a panel of the provided size.
+func NewSH1122(width, height int) *SH1122 {
+	e := &SH1122{
+		parser:    parser{arity: func(command byte) int { return sh1122Arity[command] }},
+		width:     width,
+		height:    height,
+		colOffset: (sh1122Columns - width) / 2,
+		ram:       make([]byte, sh1122Stride*sh1122Rows),
+	}
+	e.reset()
+	return e
+}
+
+func (e *SH1122) reset() {
+	e.parser.reset()
+	e.column, e.row = 0, 0
+	e.startLine, e.offset = 0, 0
+	e.multiplex = sh1122Rows
+	e.segRemap, e.comScanDec = false, false
+	e.allOn, e.invert, e.on = false, false, false
+	e.contrast = 0x80
+}
+
+func (e *SH1122) String() string {
+	return fmt.Sprintf("SH1122 emulator %dx%d", e.width, e.height)
+}
+
+// Interface returns the emulator.
+func (e *SH1122) Interface() interface{} {
+	return e
+}
+
+// Reset the controller registers on a low level, the RAM contents are retained.
+func (e *SH1122) Reset(level gpio.Level) error {
+	if level == gpio.Low {
+		e.mu.Lock()
+		e.reset()
+		e.mu.Unlock()
+	}
+	return nil
+}
+
+// Command interprets a command stream, commands may span multiple calls.
+func (e *SH1122) Command(command byte, args ...byte) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.feed(command, e.exec)
+	for _, arg := range args {
+		e.feed(arg, e.exec)
+	}
+	return nil
+}
+
+func (e *SH1122) exec(command byte, args []byte) {
+	switch {
+	case command <= 0x0F:
+		e.column = e.column&0x70 | int(command&0x0F)
+	case command <= 0x17:
+		e.column = int(command&0x07)<<4 | e.column&0x0F
+	case command >= monoSetStartLine && command <= 0x7F:
+		e.startLine = int(command & 0x3F)
+	case command == monoSetContrast:
+		e.contrast = args[0]
+	case command == monoSetRemap, command == monoSetRemap|1:
+		e.segRemap = command&1 == 1
+	case command == monoSetAllOnResume, command == monoSetAllOn:
+		e.allOn = command == monoSetAllOn
+	case command == monoSetNormal, command == monoSetInvert:
+		e.invert = command == monoSetInvert
+	case command == monoSetMultiplex:
+		if ratio := int(args[0]&0x3F) + 1; ratio >= 16 {
+			e.multiplex = ratio
+		}
+	case command == monoSetDisplayOff, command == monoSetDisplayOn:
+		e.on = command == monoSetDisplayOn
+	case command == sh1122SetRowAddress:
+		e.row = int(args[0]) % sh1122Rows
+	case command == monoSetComScanInc, command == monoSetComScanDec:
+		e.comScanDec = command == monoSetComScanDec
+	case command == monoSetDisplayOffset:
+		e.offset = int(args[0] & 0x3F)
+	}
+}
+
+// Data writes to the RAM, every column address holds 2 pixels. The row address is incremented when the
+// column address wraps around.
+func (e *SH1122) Data(data ...byte) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for _, b := range data {
+		e.ram[e.row*sh1122Stride+e.column] = b
+		if e.column++; e.column >= sh1122Stride {
+			e.column = 0
+			e.row = (e.row + 1) % sh1122Rows
+		}
+	}
+	return nil
+}
+
+// Contrast returns the contrast level.
+func (e *SH1122) Contrast() byte {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.contrast
+}
+
+// On returns if the display is switched on.
+func (e *SH1122) On() bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.on
+}
+
+// Image returns what the panel would show, as an 8-bit gray image.
+func (e *SH1122) Image() image.Image {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	i := image.NewGray(image.Rect(0, 0, e.width, e.height))
+	if !e.on {
+		return i
+	}
+	for y := 0; y < e.height; y++ {
+		scan := y
+		if e.comScanDec {
+			scan = e.multiplex - 1 - y
+		}
+		if scan < 0 || scan >= e.multiplex {
+			continue
+		}
+		row := (scan + e.startLine + e.offset) % sh1122Rows
+		for x := 0; x < e.width; x++ {
+			column := e.colOffset + x
+			if e.segRemap {
+				column = sh1122Columns - 1 - column
+			}
+			v := e.ram[row*sh1122Stride+column/2]
+			if column&1 == 0 {
+				v >>= 4
+			} else {
+				v &= 0xF
+			}
+			switch {
+			case e.allOn:
+				v = 0xF
+			case e.invert:
+				v ^= 0xF
+			}
+			i.Pix[y*i.Stride+x] = gray(v)
+		}
+	}
+	return i
+}
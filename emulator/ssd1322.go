@@ -0,0 +1,260 @@
+package emulator
+
+import (
+	"fmt"
+	"image"
+
+	"periph.io/x/conn/v3/gpio"
+)
+
+// SSD1322 commands.
+const (
+	ssd1322SetColumnAddress = 0x15
+	ssd1322WriteRAM         = 0x5C
+	ssd1322SetRowAddress    = 0x75
+	ssd1322SetRemap         = 0xA0
+	ssd1322SetStartLine     = 0xA1
+	ssd1322SetDisplayOffset = 0xA2
+	ssd1322SetDisplayAllOff = 0xA4
+	ssd1322SetDisplayAllOn  = 0xA5
+	ssd1322SetNormal        = 0xA6
+	ssd1322SetInverse       = 0xA7
+	ssd1322SetDisplayOff    = 0xAE
+	ssd1322SetDisplayOn     = 0xAF
+	ssd1322SetContrast      = 0xC1
+	ssd1322SetMultiplex     = 0xCA
+)
+
+// SSD1322 re-map and dual COM line mode bits.
+const (
+	ssd1322VerticalIncrement = 1 << 0
+	ssd1322ColumnRemap       = 1 << 1
+	ssd1322NibbleRemap       = 1 << 2
+	ssd1322ComScanDec        = 1 << 4
+)
+
+const (
+	ssd1322Columns = 480 // pixels, addressed in groups of 4
+	ssd1322Rows    = 128
+	ssd1322Stride  = ssd1322Columns / 2
+)
+
+// SSD1322 emulates a SSD1322 4-bit grayscale OLED controller.
+//
+// The panel is assumed to be connected to the center segments, and to be mounted such that the default
+// driver settings (nibble remap enabled and COM scan direction reversed) show an upright image.
+type SSD1322 struct {
+	bus
+	width, height int
+	colOffset     int
+	ram           []byte
+
+	// Registers.
+	command           byte
+	remap             byte
+	column, row       int
+	colStart, colEnd  int
+	rowStart, rowEnd  int
+	nibble            int
+	startLine, offset int
+	multiplex         int
+	mode              byte
+	on                bool
+	contrast          byte
+}
+
+// NewSSD1322 emulates a SSD1322 controller driving a panel of the provided size.
+func NewSSD1322(width, height int) *SSD1322 {
+	e := &SSD1322{
+		width:     width,
+		height:    height,
+		colOffset: (ssd1322Columns - width) / 2,
+		ram:       make([]byte, ssd1322Stride*ssd1322Rows),
+	}
+	e.reset()
+	return e
+}
+
+func (e *SSD1322) reset() {
+	e.command = 0
+	e.remap = 0
+	e.column, e.row, e.nibble = 0, 0, 0
+	e.colStart, e.colEnd = 0, ssd1322Columns/4-1
+	e.rowStart, e.rowEnd = 0, ssd1322Rows-1
+	e.startLine, e.offset = 0, 0
+	e.multiplex = ssd1322Rows
+	e.mode = ssd1322SetNormal
+	e.on = false
+	e.contrast = 0x7F
+}
+
+func (e *SSD1322) String() string {
+	return fmt.Sprintf("SSD1322 emulator %dx%d", e.width, e.height)
+}
+
+// Interface returns the emulator.
+func (e *SSD1322) Interface() interface{} {
+	return e
+}
+
+// Reset the controller registers on a low level, the RAM contents are retained.
+func (e *SSD1322) Reset(level gpio.Level) error {
+	if level == gpio.Low {
+		e.mu.Lock()
+		e.reset()
+		e.mu.Unlock()
+	}
+	return nil
+}
+
+// Command interprets a command, the arguments are sent with the command.
+func (e *SSD1322) Command(command byte, args ...byte) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.command = command
+	arg := func(i int) int {
+		if i < len(args) {
+			return int(args[i])
+		}
+		return 0
+	}
+	switch command {
+	case ssd1322SetColumnAddress:
+		e.colStart, e.colEnd = arg(0)%(ssd1322Columns/4), arg(1)%(ssd1322Columns/4)
+		e.column, e.nibble = e.colStart, 0
+	case ssd1322SetRowAddress:
+		e.rowStart, e.rowEnd = arg(0)%ssd1322Rows, arg(1)%ssd1322Rows
+		e.row, e.nibble = e.rowStart, 0
+	case ssd1322WriteRAM:
+		e.nibble = 0
+	case ssd1322SetRemap:
+		e.remap = byte(arg(0))
+	case ssd1322SetStartLine:
+		e.startLine = arg(0) % ssd1322Rows
+	case ssd1322SetDisplayOffset:
+		e.offset = arg(0) % ssd1322Rows
+	case ssd1322SetDisplayAllOff, ssd1322SetDisplayAllOn, ssd1322SetNormal, ssd1322SetInverse:
+		e.mode = command
+	case ssd1322SetDisplayOff, ssd1322SetDisplayOn:
+		e.on = command == ssd1322SetDisplayOn
+	case ssd1322SetContrast:
+		e.contrast = byte(arg(0))
+	case ssd1322SetMultiplex:
+		if ratio := arg(0)&0x7F + 1; ratio >= 16 {
+			e.multiplex = ratio
+		}
+	}
+	return nil
+}
+
+// Data writes to the RAM after a write RAM command, every column address holds 4 pixels in 2 bytes.
+func (e *SSD1322) Data(data ...byte) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.command != ssd1322WriteRAM {
+		return nil
+	}
+	for _, b := range data {
+		for _, v := range [2]byte{b >> 4, b & 0xF} {
+			e.set(e.column*4+e.pixel(e.nibble), e.row, v)
+			e.nibble++
+		}
+		if e.nibble < 4 {
+			continue
+		}
+		e.nibble = 0
+		if e.remap&ssd1322VerticalIncrement != 0 {
+			if e.row++; e.row > e.rowEnd {
+				e.row = e.rowStart
+				if e.column++; e.column > e.colEnd {
+					e.column = e.colStart
+				}
+			}
+		} else {
+			if e.column++; e.column > e.colEnd {
+				e.column = e.colStart
+				if e.row++; e.row > e.rowEnd {
+					e.row = e.rowStart
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// pixel returns the pixel within a column address for the n-th written nibble.
+func (e *SSD1322) pixel(n int) int {
+	if e.remap&ssd1322NibbleRemap != 0 {
+		return n
+	}
+	return 3 - n
+}
+
+func (e *SSD1322) set(x, y int, v byte) {
+	off := y*ssd1322Stride + x/2
+	if x&1 == 0 {
+		e.ram[off] = e.ram[off]&0x0F | v<<4
+	} else {
+		e.ram[off] = e.ram[off]&0xF0 | v
+	}
+}
+
+func (e *SSD1322) at(x, y int) byte {
+	v := e.ram[y*ssd1322Stride+x/2]
+	if x&1 == 0 {
+		return v >> 4
+	}
+	return v & 0xF
+}
+
+// Contrast returns the contrast current.
+func (e *SSD1322) Contrast() byte {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.contrast
+}
+
+// On returns if the display is switched on.
+func (e *SSD1322) On() bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.on
+}
+
+// Image returns what the panel would show, as an 8-bit gray image.
+func (e *SSD1322) Image() image.Image {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	i := image.NewGray(image.Rect(0, 0, e.width, e.height))
+	if !e.on || e.mode == ssd1322SetDisplayAllOff {
+		return i
+	}
+	for y := 0; y < e.height; y++ {
+		scan := y
+		if e.remap&ssd1322ComScanDec == 0 {
+			scan = e.multiplex - 1 - y
+		}
+		if scan < 0 || scan >= e.multiplex {
+			continue
+		}
+		row := (scan + e.startLine + e.offset) % ssd1322Rows
+		for x := 0; x < e.width; x++ {
+			column := e.colOffset + x
+			if e.remap&ssd1322ColumnRemap != 0 {
+				column = ssd1322Columns - 1 - column
+			}
+			v := e.at(column, row)
+			switch e.mode {
+			case ssd1322SetDisplayAllOn:
+				v = 0xF
+			case ssd1322SetInverse:
+				v ^= 0xF
+			}
+			i.Pix[y*i.Stride+x] = gray(v)
+		}
+	}
+	return i
+}
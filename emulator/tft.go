@@ -0,0 +1,283 @@
+package emulator
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+
+	"periph.io/x/conn/v3/gpio"
+)
+
+// TFT controller commands.
+const (
+	tftSWRESET = 0x01
+	tftSLPIN   = 0x10
+	tftSLPOUT  = 0x11
+	tftINVOFF  = 0x20
+	tftINVON   = 0x21
+	tftDISPOFF = 0x28
+	tftDISPON  = 0x29
+	tftCASET   = 0x2A
+	tftRASET   = 0x2B
+	tftRAMWR   = 0x2C
+	tftMADCTL  = 0x36
+	tftCOLMOD  = 0x3A
+	tftWRDISBV = 0x51
+	tftWRCTRLD = 0x53
+)
+
+// Memory Data Access Control (MADCTL) bits.
+const (
+	tftMADCTLBGR = 1 << 3
+	tftMADCTLMV  = 1 << 5
+	tftMADCTLMX  = 1 << 6
+	tftMADCTLMY  = 1 << 7
+)
+
+// TFT emulates a TFT controller with CASET/RASET/RAMWR addressing, such as the ST7735 and ST7789.
+//
+// The panel is connected to the top left of the RAM. Display inversion is tracked but not applied to the
+// image, because IPS panels need inversion enabled to show the correct colors.
+type TFT struct {
+	bus
+	name          string
+	width, height int
+	ramWidth      int
+	ramHeight     int
+	ram           []color.RGBA
+
+	// Registers.
+	command          byte
+	params           []byte
+	colStart, colEnd int
+	rowStart, rowEnd int
+	column, row      int
+	madctl           byte
+	colmod           byte
+	brightness       byte
+	ctrlDisplay      byte
+	sleep            bool
+	inversion        bool
+	on               bool
+}
+
+// NewST7735 emulates a ST7735 controller driving a panel of the provided (portrait) size.
+func NewST7735(width, height int) *TFT {
+	return newTFT("ST7735", width, height, 128, 160)
+}
+
+// NewST7789 emulates a ST7789 controller driving a panel of the provided (portrait) size.
+func NewST7789(width, height int) *TFT {
+	return newTFT("ST7789", width, height, 240, 320)
+}
+
+func newTFT(name string, width, height, ramWidth, ramHeight int) *TFT {
+	e := &TFT{
+		name:      name,
+		width:     width,
+		height:    height,
+		ramWidth:  ramWidth,
+		ramHeight: ramHeight,
+		ram:       make([]color.RGBA, ramWidth*ramHeight),
+	}
+	for i := range e.ram {
+		e.ram[i] = color.RGBA{A: 0xFF}
+	}
+	e.reset()
+	return e
+}
+
+func (e *TFT) reset() {
+	e.command, e.params = 0, e.params[:0]
+	e.colStart, e.colEnd = 0, e.ramWidth-1
+	e.rowStart, e.rowEnd = 0, e.ramHeight-1
+	e.column, e.row = 0, 0
+	e.madctl = 0
+	e.colmod = 0x66
+	e.brightness, e.ctrlDisplay = 0, 0
+	e.sleep, e.inversion, e.on = true, false, false
+}
+
+func (e *TFT) String() string {
+	return fmt.Sprintf("%s emulator %dx%d", e.name, e.width, e.height)
+}
+
+// Interface returns the emulator.
+func (e *TFT) Interface() interface{} {
+	return e
+}
+
+// Reset the controller registers on a low level, the RAM contents are retained.
+func (e *TFT) Reset(level gpio.Level) error {
+	if level == gpio.Low {
+		e.mu.Lock()
+		e.reset()
+		e.mu.Unlock()
+	}
+	return nil
+}
+
+// Command starts a new command, the arguments are handled as if they were sent as data.
+func (e *TFT) Command(command byte, args ...byte) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.command, e.params = command, e.params[:0]
+	switch command {
+	case tftSWRESET:
+		e.reset()
+	case tftSLPIN, tftSLPOUT:
+		e.sleep = command == tftSLPIN
+	case tftINVOFF, tftINVON:
+		e.inversion = command == tftINVON
+	case tftDISPOFF, tftDISPON:
+		e.on = command == tftDISPON
+	case tftRAMWR:
+		e.column, e.row = e.colStart, e.rowStart
+	}
+	for _, arg := range args {
+		e.data(arg)
+	}
+	return nil
+}
+
+// Data sends command parameters, or pixels after a memory write command.
+func (e *TFT) Data(data ...byte) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for _, b := range data {
+		e.data(b)
+	}
+	return nil
+}
+
+func (e *TFT) data(b byte) {
+	e.params = append(e.params, b)
+	switch e.command {
+	case tftCASET:
+		if len(e.params) == 4 {
+			e.colStart = int(e.params[0])<<8 | int(e.params[1])
+			e.colEnd = int(e.params[2])<<8 | int(e.params[3])
+		}
+	case tftRASET:
+		if len(e.params) == 4 {
+			e.rowStart = int(e.params[0])<<8 | int(e.params[1])
+			e.rowEnd = int(e.params[2])<<8 | int(e.params[3])
+		}
+	case tftMADCTL:
+		e.madctl = b
+	case tftCOLMOD:
+		e.colmod = b
+	case tftWRDISBV:
+		e.brightness = b
+	case tftWRCTRLD:
+		e.ctrlDisplay = b
+	case tftRAMWR:
+		if len(e.params) < e.pixelSize() {
+			return
+		}
+		e.write(e.pixel())
+		e.params = e.params[:0]
+	}
+}
+
+// pixelSize returns the number of bytes per pixel for the interface pixel format.
+func (e *TFT) pixelSize() int {
+	if e.colmod&7 == 5 {
+		return 2
+	}
+	return 3
+}
+
+// pixel decodes a 16-bit (5-6-5) or 18-bit (6-6-6) pixel.
+func (e *TFT) pixel() color.RGBA {
+	var r, g, b uint8
+	if p := e.params; len(p) == 2 {
+		v := uint16(p[0])<<8 | uint16(p[1])
+		r = uint8(v>>11) << 3
+		g = uint8(v>>5&0x3F) << 2
+		b = uint8(v&0x1F) << 3
+		r |= r >> 5
+		g |= g >> 6
+		b |= b >> 5
+	} else {
+		r = p[0]&0xFC | p[0]>>6
+		g = p[1]&0xFC | p[1]>>6
+		b = p[2]&0xFC | p[2]>>6
+	}
+	if e.madctl&tftMADCTLBGR != 0 {
+		r, b = b, r
+	}
+	return color.RGBA{R: r, G: g, B: b, A: 0xFF}
+}
+
+// write a pixel at the current address, mapped through the memory data access control, and advance.
+func (e *TFT) write(c color.RGBA) {
+	x, y := e.column, e.row
+	if e.madctl&tftMADCTLMV != 0 {
+		x, y = y, x
+	}
+	if e.madctl&tftMADCTLMX != 0 {
+		x = e.ramWidth - 1 - x
+	}
+	if e.madctl&tftMADCTLMY != 0 {
+		y = e.ramHeight - 1 - y
+	}
+	if x >= 0 && x < e.ramWidth && y >= 0 && y < e.ramHeight {
+		e.ram[y*e.ramWidth+x] = c
+	}
+
+	if e.column++; e.column > e.colEnd {
+		e.column = e.colStart
+		if e.row++; e.row > e.rowEnd {
+			e.row = e.rowStart
+		}
+	}
+}
+
+// Inversion returns if display inversion is enabled.
+func (e *TFT) Inversion() bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.inversion
+}
+
+// Brightness returns the display brightness value, if brightness control is enabled. Otherwise the
+// brightness is 0xFF.
+func (e *TFT) Brightness() byte {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.ctrlDisplay&0x20 == 0 {
+		return 0xFF
+	}
+	return e.brightness
+}
+
+// On returns if the display is switched on and out of sleep mode.
+func (e *TFT) On() bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.on && !e.sleep
+}
+
+// Image returns what the panel would show.
+func (e *TFT) Image() image.Image {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	var (
+		i  = image.NewRGBA(image.Rect(0, 0, e.width, e.height))
+		on = e.on && !e.sleep
+	)
+	for y := 0; y < e.height; y++ {
+		for x := 0; x < e.width; x++ {
+			c := color.RGBA{A: 0xFF}
+			if on && x < e.ramWidth && y < e.ramHeight {
+				c = e.ram[y*e.ramWidth+x]
+			}
+			i.SetRGBA(x, y, c)
+		}
+	}
+	return i
+}
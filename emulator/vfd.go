@@ -0,0 +1,204 @@
+package emulator
+
+import (
+	"fmt"
+	"image"
+	"math/bits"
+
+	"periph.io/x/conn/v3/gpio"
+)
+
+// VFD controller commands.
+const (
+	vfdClearGRAM   = 0x55
+	vfdStandby     = 0x61
+	vfdWakeUp      = 0x62
+	vfdDisplayOn   = 0x6D
+	vfdDisplayMode = 0x80
+	vfdDimming     = 0xA0
+	vfdReset       = 0xAA
+	vfdOffset      = 0xC0
+	vfdWriteGRAM   = 0xF0
+)
+
+const (
+	vfdColumns = 256
+	vfdRows    = 64
+	vfdStride  = vfdRows / 8
+)
+
+// VFD emulates a Noritake Itron VFD controller with column oriented graphics RAM, such as the GP1294 and
+// GP1287.
+//
+// These controllers expect the least significant bit first, so every byte is bit reversed on the wire.
+// The emulator reverses the bytes back before interpreting them.
+type VFD struct {
+	bus
+	name          string
+	width, height int
+	ram           []byte
+
+	// Registers.
+	command    byte
+	params     []byte
+	x, y, rows int
+	offset     int
+	dx, dy     int
+	dimming    uint16
+	invert, on bool
+}
+
+// NewGP1294 emulates a GP1294 controller driving a panel of the provided size.
+func NewGP1294(width, height int) *VFD {
+	return newVFD("GP1294", width, height)
+}
+
+// NewGP1287 emulates a GP1287 controller driving a panel of the provided size.
+func NewGP1287(width, height int) *VFD {
+	return newVFD("GP1287", width, height)
+}
+
+func newVFD(name string, width, height int) *VFD {
+	e := &VFD{
+		name:   name,
+		width:  width,
+		height: height,
+		ram:    make([]byte, vfdColumns*vfdStride),
+	}
+	e.reset()
+	return e
+}
+
+func (e *VFD) reset() {
+	e.command, e.params = 0, e.params[:0]
+	e.x, e.y, e.rows, e.offset = 0, 0, 0, 0
+	e.dx, e.dy = 0, 0
+	e.dimming = 0
+	e.invert, e.on = false, true
+}
+
+func (e *VFD) String() string {
+	return fmt.Sprintf("%s emulator %dx%d", e.name, e.width, e.height)
+}
+
+// Interface returns the emulator.
+func (e *VFD) Interface() interface{} {
+	return e
+}
+
+// Reset the controller registers on a low level, the RAM contents are retained.
+func (e *VFD) Reset(level gpio.Level) error {
+	if level == gpio.Low {
+		e.mu.Lock()
+		e.reset()
+		e.mu.Unlock()
+	}
+	return nil
+}
+
+// Command interprets a command with its arguments, a write GRAM command may be followed by pixel data.
+func (e *VFD) Command(command byte, args ...byte) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.command, e.params = bits.Reverse8(command), e.params[:0]
+	switch e.command {
+	case vfdClearGRAM:
+		clear(e.ram)
+	case vfdStandby:
+		e.on = false
+	case vfdWakeUp, vfdDisplayOn:
+		e.on = true
+	case vfdReset:
+		e.reset()
+	}
+	for _, arg := range args {
+		e.data(bits.Reverse8(arg))
+	}
+	return nil
+}
+
+// Data sends pixel data after a write GRAM command, other data is ignored.
+func (e *VFD) Data(data ...byte) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for _, b := range data {
+		e.data(bits.Reverse8(b))
+	}
+	return nil
+}
+
+func (e *VFD) data(b byte) {
+	if e.command == vfdWriteGRAM && len(e.params) == 3 {
+		e.write(b)
+		return
+	}
+
+	e.params = append(e.params, b)
+	switch e.command {
+	case vfdWriteGRAM:
+		if len(e.params) == 3 {
+			e.x, e.y, e.rows, e.offset = int(e.params[0]), int(e.params[1]), int(e.params[2])+1, 0
+		}
+	case vfdDisplayMode:
+		e.invert = b&1 == 1
+	case vfdDimming:
+		if len(e.params) == 2 {
+			e.dimming = uint16(e.params[0])<<8 | uint16(e.params[1])
+		}
+	case vfdOffset:
+		if len(e.params) == 2 {
+			e.dx, e.dy = int(e.params[0]), int(e.params[1])%vfdRows
+		}
+	}
+}
+
+// write 8 vertical pixels, the least significant bit is the top pixel. Columns are written top to bottom.
+func (e *VFD) write(b byte) {
+	if row := e.y + e.offset*8; e.x < vfdColumns && row < vfdRows {
+		e.ram[e.x*vfdStride+row/8] = b
+	}
+	if e.offset++; e.offset*8 >= e.rows {
+		e.offset = 0
+		e.x++
+	}
+}
+
+// On returns if the display is switched on.
+func (e *VFD) On() bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.on
+}
+
+// Dimming returns the dimming level.
+func (e *VFD) Dimming() uint16 {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.dimming
+}
+
+// Image returns what the panel would show, as an 8-bit gray image with pixels either on or off.
+func (e *VFD) Image() image.Image {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	i := image.NewGray(image.Rect(0, 0, e.width, e.height))
+	if !e.on {
+		return i
+	}
+	for y := 0; y < e.height && y < vfdRows; y++ {
+		for x := 0; x < e.width && x < vfdColumns; x++ {
+			var (
+				column = (x + e.dx) % vfdColumns
+				row    = (y + e.dy) % vfdRows
+				on     = e.ram[column*vfdStride+row/8]&(1<<(row&7)) != 0
+			)
+			if on != e.invert {
+				i.Pix[y*i.Stride+x] = 0xFF
+			}
+		}
+	}
+	return i
+}
@@ -10,5 +10,5 @@ require (
 
 require (
 	golang.org/x/image v0.24.0
-	golang.org/x/text v0.22.0 // indirect
+	golang.org/x/text v0.22.0
 )
@@ -2,6 +2,7 @@ package display
 
 import (
 	"fmt"
+	"image"
 	"time"
 
 	"github.com/BeatGlow/display/pixel"
@@ -28,9 +29,21 @@ const (
 	gp1278InvertDisplay         = 0x01
 )
 
+func init() {
+	Register(Driver{
+		Name:    "gp1287",
+		Aliases: []string{"gp1278"},
+		Buses:   SPIBus,
+		Width:   gp1278DefaultWidth,
+		Height:  gp1278DefaultHeight,
+		New:     GP1278,
+	})
+}
+
 type gp1278 struct {
 	monoDisplay
 	pageSize int
+	invert   bool
 }
 
 // GP1278 is a driver for GP1287AI/BI VFD displays.
@@ -71,10 +84,7 @@ func (d *gp1278) Close() error {
 
 func (d *gp1278) command(command byte, args ...byte) (err error) {
 	// Swap bits, since the driver expects LSB first.
-	for i, v := range args {
-		args[i] = rev8tab[v]
-	}
-	return d.c.Command(rev8tab[command], args...)
+	return d.c.Command(rev8tab[command], reverse(args)...)
 }
 
 func (d *gp1278) commands(commands ...[]byte) (err error) {
@@ -88,10 +98,7 @@ func (d *gp1278) commands(commands ...[]byte) (err error) {
 
 func (d *gp1278) data(data ...byte) error {
 	// Swap bits, since the driver expects LSB first.
-	for i, v := range data {
-		data[i] = rev8tab[v]
-	}
-	return d.c.Data(data...)
+	return d.c.Data(reverse(data)...)
 }
 
 func (d *gp1278) init(config *Config) (err error) {
@@ -99,6 +106,7 @@ func (d *gp1278) init(config *Config) (err error) {
 	if err = d.monoDisplay.init(config); err != nil {
 		return
 	}
+	d.setSoftwareRotation(config.Rotation)
 
 	// init display
 	if err = d.commands([][]byte{
@@ -137,40 +145,77 @@ func (d *gp1278) Show(show bool) (err error) {
 			return
 		}
 		time.Sleep(1 * time.Millisecond) // Wait for internal oscillator to stabilize
-		return d.command(gp1278SetDisplayMode, gp1278NormalDisplay)
+		return d.SetInvert(d.invert)
 	} else {
 		return d.command(gp127Standby)
 	}
 }
 
+// SetRotation rotates the display in software.
+func (d *gp1278) SetRotation(rotation Rotation) error {
+	d.setSoftwareRotation(rotation)
+	return nil
+}
+
 func (d *gp1278) SetContrast(level uint8) error {
 	level16 := uint16(level) * 4 // in range [0..1023]
 	return d.command(gp1278SetDimming, uint8(level16>>8), uint8(level16))
 }
 
+// Refresh redraws the whole display.
 func (d *gp1278) Refresh() error {
+	return d.RefreshRect(d.Bounds())
+}
+
+// RefreshRect redraws the pages and columns covering r.
+func (d *gp1278) RefreshRect(r image.Rectangle) error {
+	if r = d.bufferRect(r); r.Empty() {
+		return nil
+	}
+
 	pix := d.Image.(*pixel.MonoVerticalLSBImage).Pix
-	for page := 0; page < d.pageSize; page++ {
+	for page := r.Min.Y >> 3; page < (r.Max.Y+7)>>3; page++ {
 		offset := uint8(page) * 8
 		if err := d.command(
 			gp1278WriteGRAM,
-			0x00,         // x
-			byte(offset), // y
-			0x07,         // columns (all)
+			byte(r.Min.X), // x
+			byte(offset),  // y
+			0x07,          // columns (all)
 		); err != nil {
 			return err
 		}
-		var (
-			off = page * d.width
-			end = off + d.width
-		)
-		if err := d.data(pix[off:end]...); err != nil {
+		off := page * d.width
+		if err := d.data(pix[off+r.Min.X : off+r.Max.X]...); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
+// SetInvert toggles inverted pixels.
+func (d *gp1278) SetInvert(invert bool) error {
+	mode := byte(gp1278NormalDisplay)
+	if invert {
+		mode = gp1278InvertDisplay
+	}
+	d.invert = invert
+	return d.command(gp1278SetDisplayMode, mode)
+}
+
+// Info describes the GP1287 controller.
+func (d *gp1278) Info() Info {
+	return d.describe("GP1287", d)
+}
+
+// reverse returns a copy of b with the bits of every byte reversed, leaving b untouched.
+func reverse(b []byte) []byte {
+	r := make([]byte, len(b))
+	for i, v := range b {
+		r[i] = rev8tab[v]
+	}
+	return r
+}
+
 // rev8tab from math/bits/bits_tables.go
 const rev8tab = "" +
 	"\x00\x80\x40\xc0\x20\xa0\x60\xe0\x10\x90\x50\xd0\x30\xb0\x70\xf0" +
@@ -189,3 +234,10 @@ const rev8tab = "" +
 	"\x0b\x8b\x4b\xcb\x2b\xab\x6b\xeb\x1b\x9b\x5b\xdb\x3b\xbb\x7b\xfb" +
 	"\x07\x87\x47\xc7\x27\xa7\x67\xe7\x17\x97\x57\xd7\x37\xb7\x77\xf7" +
 	"\x0f\x8f\x4f\xcf\x2f\xaf\x6f\xef\x1f\x9f\x5f\xdf\x3f\xbf\x7f\xff"
+
+// Interface checks
+var (
+	_ WindowRefresher = (*gp1278)(nil)
+	_ Inverter        = (*gp1278)(nil)
+	_ Describer       = (*gp1278)(nil)
+)
@@ -1,6 +1,7 @@
 package display
 
 import (
+	"context"
 	"fmt"
 	"image"
 	"image/color"
@@ -14,6 +15,7 @@ import (
 const (
 	gp1294DefaultWidth      = 256
 	gp1294DefaultHeight     = 48
+	gp1294RAMRows           = 64
 	gp1264DefaultBrightness = 0x0028
 )
 
@@ -30,10 +32,21 @@ const (
 	gp1294WriteGRAM     = 0xF0
 )
 
+func init() {
+	Register(Driver{
+		Name:   "gp1294",
+		Buses:  SPIBus,
+		Width:  gp1294DefaultWidth,
+		Height: gp1294DefaultHeight,
+		New:    GP1294,
+	})
+}
+
 type gp1294 struct {
 	conn          Conn
 	spiConn       *conn.SPI
 	width, height int
+	rotation      Rotation
 	pix           []byte
 	pageSize      int
 }
@@ -51,7 +64,7 @@ func GP1294(c Conn, config *Config) (Display, error) {
 		}
 	}
 
-	if s, ok := c.(*spiConn); ok {
+	if s, ok := c.(SPI); ok {
 		if err := s.SetMode(conn.SPIMode0); err != nil {
 			return nil, err
 		}
@@ -69,6 +82,7 @@ func GP1294(c Conn, config *Config) (Display, error) {
 
 	d.height = config.Height
 	d.width = config.Width
+	d.rotation = config.Rotation & 3
 	d.pix = make([]byte, (d.width*d.height)/8)
 
 	if err := d.init(config); err != nil {
@@ -164,7 +178,11 @@ func (d *gp1294) clear() error {
 	)...)
 }
 
+// Bounds of the display, taking the rotation into account.
 func (d *gp1294) Bounds() image.Rectangle {
+	if d.rotation == Rotate90 || d.rotation == Rotate270 {
+		return image.Rect(0, 0, d.height, d.width)
+	}
 	return image.Rect(0, 0, d.width, d.height)
 }
 
@@ -176,9 +194,10 @@ func (d *gp1294) At(x, y int) color.Color {
 	if !image.Pt(x, y).In(d.Bounds()) {
 		return color.Transparent
 	}
+	x, y = rotatePoint(d.rotation, x, y, d.width, d.height)
 
 	offset := x*(d.height/8) + y/8
-	if d.pix[offset]&(1<<y%8) != 0 {
+	if d.pix[offset]&(1<<(y%8)) != 0 {
 		return pixel.On
 	}
 	return pixel.Off
@@ -188,6 +207,7 @@ func (d *gp1294) Set(x, y int, c color.Color) {
 	if !image.Pt(x, y).In(d.Bounds()) {
 		return
 	}
+	x, y = rotatePoint(d.rotation, x, y, d.width, d.height)
 
 	offset := x*(d.height/8) + y/8
 	if pixel.MonoModel.Convert(c).(pixel.Mono).On {
@@ -202,7 +222,9 @@ func (d *gp1294) SetContrast(level uint8) error {
 	return d.Command(gp1294Brightness, byte(value), byte(value>>8))
 }
 
-func (gp1294) SetRotation(_ Rotation) error {
+// SetRotation rotates the display in software.
+func (d *gp1294) SetRotation(rotation Rotation) error {
+	d.rotation = rotation & 3
 	return nil
 }
 
@@ -214,9 +236,73 @@ func (d *gp1294) Show(show bool) error {
 	}
 }
 
+// Refresh redraws the whole display.
 func (d *gp1294) Refresh() error {
+	return d.RefreshRect(d.Bounds())
+}
+
+// RefreshRect redraws the columns and rows covering r. Rows are addressed in groups of 8 pixels.
+func (d *gp1294) RefreshRect(r image.Rectangle) error {
+	if r = rotateRect(d.rotation, r, d.width, d.height).Intersect(image.Rect(0, 0, d.width, d.height)); r.Empty() {
+		return nil
+	}
+
+	var (
+		y0     = r.Min.Y &^ 7
+		y1     = (r.Max.Y + 7) &^ 7
+		stride = d.height / 8
+		pix    = d.pix
+	)
+	if y0 != 0 || y1 != d.height {
+		// Columns are stored vertically, so partial columns need to be gathered.
+		pix = make([]byte, 0, r.Dx()*(y1-y0)/8)
+		for x := r.Min.X; x < r.Max.X; x++ {
+			off := x * stride
+			pix = append(pix, d.pix[off+y0/8:off+y1/8]...)
+		}
+	} else {
+		pix = pix[r.Min.X*stride : r.Max.X*stride]
+	}
+
 	return d.command(gp1294WriteGRAM, append([]byte{
-		0, 0, byte(d.height) - 1},
-		d.pix...,
+		byte(r.Min.X), byte(y0), byte(y1-y0) - 1},
+		pix...,
 	)...)
 }
+
+// SetInvert toggles inverted pixels, using the same display mode as the GP1287.
+func (d *gp1294) SetInvert(invert bool) error {
+	var mode byte
+	if invert {
+		mode = 0x01
+	}
+	return d.command(gp1294DisplayMode, mode)
+}
+
+// SetOffset sets the display area offset, the RAM has 256 columns and 64 rows.
+func (d *gp1294) SetOffset(x, y int) error {
+	return d.command(gp1294DisplayOffset, byte(x), startLine(y, gp1294RAMRows))
+}
+
+func (d *gp1294) bindContext(ctx context.Context) func() {
+	return bindConn(&d.conn, ctx)
+}
+
+// Info describes the GP1294 controller.
+func (d *gp1294) Info() Info {
+	return Info{
+		Controller:   "GP1294",
+		Width:        d.width,
+		Height:       d.height,
+		Depth:        1,
+		Capabilities: Capabilities(d),
+	}
+}
+
+// Interface checks
+var (
+	_ WindowRefresher = (*gp1294)(nil)
+	_ Inverter        = (*gp1294)(nil)
+	_ Describer       = (*gp1294)(nil)
+	_ Offsetter       = (*gp1294)(nil)
+)
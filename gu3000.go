@@ -2,6 +2,7 @@ package display
 
 import (
 	"fmt"
+	"image"
 
 	"github.com/BeatGlow/display/pixel"
 )
@@ -17,6 +18,16 @@ const (
 	gu3000BrightnessLevel     = 0x58
 )
 
+func init() {
+	Register(Driver{
+		Name:   "gu3000",
+		Buses:  ParallelBus,
+		Width:  gu3000DefaultWidth,
+		Height: gu3000DefaultHeight,
+		New:    GU3000,
+	})
+}
+
 type gu3000 struct {
 	monoDisplay
 	dad uint8 // data address
@@ -49,6 +60,7 @@ func (d *gu3000) init(config *Config) error {
 	if err := d.monoDisplay.init(config); err != nil {
 		return err
 	}
+	d.setSoftwareRotation(config.Rotation)
 
 	if err := d.flush(); err != nil {
 		return err
@@ -76,6 +88,12 @@ func (d *gu3000) setDisplayStartAddress(addr uint16) (err error) {
 	return d.Command(gu3000DisplayStartAddress, byte(addr&0xff), byte(addr>>8))
 }
 
+// SetRotation rotates the display in software.
+func (d *gu3000) SetRotation(rotation Rotation) error {
+	d.setSoftwareRotation(rotation)
+	return nil
+}
+
 func (d *gu3000) SetContrast(level uint8) error {
 	return d.Command(gu3000BrightnessLevel, level)
 }
@@ -87,3 +105,19 @@ func (d *gu3000) Refresh() error {
 	}
 	return d.c.Data(d.Image.(*pixel.MonoVerticalLSBImage).Pix...)
 }
+
+// RefreshRect redraws the display. The GU3000 is always redrawn completely.
+func (d *gu3000) RefreshRect(_ image.Rectangle) error {
+	return d.Refresh()
+}
+
+// Info describes the GU3000 controller.
+func (d *gu3000) Info() Info {
+	return d.describe("GU3000", d)
+}
+
+// Interface checks
+var (
+	_ WindowRefresher = (*gu3000)(nil)
+	_ Describer       = (*gu3000)(nil)
+)
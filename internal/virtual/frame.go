@@ -0,0 +1,152 @@
+// Package virtual implements the frame buffer of displays that are not backed by hardware.
+package virtual
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+
+	"github.com/BeatGlow/display"
+	"github.com/BeatGlow/display/pixel"
+)
+
+// Default display size.
+const (
+	DefaultWidth  = 128
+	DefaultHeight = 64
+)
+
+// ErrModel is returned for color models that can't be emulated.
+var ErrModel = errors.New("display: unsupported color model")
+
+// Frame is a frame buffer using one of the pixel color models, rotated in software.
+//
+// The width and height are the native size of the panel, the bounds are swapped when rotated by 90° or
+// 270°, like drivers that rotate in software.
+type Frame struct {
+	image    pixel.Image
+	model    color.Model
+	rotation display.Rotation
+}
+
+// NewFrame returns a frame buffer for the color model, one of [pixel.MonoModel] (default),
+// [pixel.Gray2Model], [pixel.Gray4Model] or [pixel.CRGB16Model]. The size defaults to
+// DefaultWidth×DefaultHeight.
+func NewFrame(model color.Model, config *display.Config) (*Frame, error) {
+	if config == nil {
+		config = new(display.Config)
+	}
+
+	width, height := config.Width, config.Height
+	if width == 0 {
+		width = DefaultWidth
+	}
+	if height == 0 {
+		height = DefaultHeight
+	}
+
+	f := &Frame{
+		model:    model,
+		rotation: config.Rotation & 3,
+	}
+	switch model {
+	case nil, pixel.MonoModel:
+		f.model = pixel.MonoModel
+		f.image = pixel.NewMonoImage(width, height)
+	case pixel.Gray2Model:
+		f.image = pixel.NewGray2Image(width, height)
+	case pixel.Gray4Model:
+		f.image = pixel.NewGray4Image(width, height)
+	case pixel.CRGB16Model:
+		f.image = pixel.NewCRGB16Image(width, height)
+	default:
+		return nil, ErrModel
+	}
+	return f, nil
+}
+
+// ModelName returns a description of the color model.
+func ModelName(m color.Model) string {
+	switch m {
+	case pixel.Gray2Model:
+		return "2-bit gray"
+	case pixel.Gray4Model:
+		return "4-bit gray"
+	case pixel.CRGB16Model:
+		return "16-bit RGB"
+	default:
+		return "monochrome"
+	}
+}
+
+// ColorModel returns the color model of the frame buffer.
+func (f *Frame) ColorModel() color.Model {
+	return f.model
+}
+
+// Bounds of the display, taking the rotation into account.
+func (f *Frame) Bounds() image.Rectangle {
+	b := f.image.Bounds()
+	if f.rotation == display.Rotate90 || f.rotation == display.Rotate270 {
+		return image.Rect(0, 0, b.Dy(), b.Dx())
+	}
+	return b
+}
+
+// point maps (x, y) on the display to the frame buffer.
+func (f *Frame) point(x, y int) (int, int) {
+	b := f.image.Bounds()
+	switch f.rotation {
+	case display.Rotate90:
+		return b.Dx() - 1 - y, x
+	case display.Rotate180:
+		return b.Dx() - 1 - x, b.Dy() - 1 - y
+	case display.Rotate270:
+		return y, b.Dy() - 1 - x
+	default:
+		return x, y
+	}
+}
+
+func (f *Frame) At(x, y int) color.Color {
+	return f.image.At(f.point(x, y))
+}
+
+func (f *Frame) Set(x, y int, c color.Color) {
+	x, y = f.point(x, y)
+	f.image.Set(x, y, c)
+}
+
+// FrameBuffer returns the unrotated frame buffer.
+func (f *Frame) FrameBuffer() draw.Image {
+	return f.image
+}
+
+// Clear the frame buffer.
+func (f *Frame) Clear() {
+	f.image.Clear()
+}
+
+// Fill the frame buffer with a single color.
+func (f *Frame) Fill(c color.Color) {
+	f.image.Fill(c)
+}
+
+// Rotation returns the current rotation.
+func (f *Frame) Rotation() display.Rotation {
+	return f.rotation
+}
+
+// SetRotation adjusts the rotation, the frame buffer contents are not rotated.
+func (f *Frame) SetRotation(rotation display.Rotation) error {
+	f.rotation = rotation & 3
+	return nil
+}
+
+// String returns the native size and color model.
+func (f *Frame) String() string {
+	b := f.image.Bounds()
+	return fmt.Sprintf("%dx%d %s", b.Dx(), b.Dy(), ModelName(f.model))
+}
@@ -4,8 +4,7 @@ import (
 	"encoding/binary"
 	"image"
 	"image/color"
-
-	"github.com/BeatGlow/display/draw"
+	"image/draw"
 )
 
 type Image interface {
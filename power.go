@@ -0,0 +1,319 @@
+package display
+
+import (
+	"fmt"
+	"hash/fnv"
+	"image"
+	"image/draw"
+	"slices"
+	"sync"
+	"time"
+)
+
+// PowerState is the power state of a display managed by a [PowerManager].
+type PowerState uint8
+
+// Power states, from active to deepest idle state.
+const (
+	PowerOn          PowerState = iota // Display is on at normal contrast
+	PowerDimmed                        // Display is on at reduced contrast
+	PowerScreensaver                   // Display shows the screensaver
+	PowerBlanked                       // Display is switched off
+	PowerSleep                         // Controller is in sleep mode
+)
+
+func (s PowerState) String() string {
+	switch s {
+	case PowerOn:
+		return "on"
+	case PowerDimmed:
+		return "dimmed"
+	case PowerScreensaver:
+		return "screensaver"
+	case PowerBlanked:
+		return "blanked"
+	case PowerSleep:
+		return "sleep"
+	default:
+		return fmt.Sprintf("power(%d)", s)
+	}
+}
+
+// PowerConfig is the [PowerManager] configuration. Timeouts are measured from the last activity, a zero
+// timeout disables the state.
+type PowerConfig struct {
+	// DimAfter is the idle time after which the contrast is reduced to DimContrast.
+	DimAfter time.Duration
+
+	// DimContrast is the contrast level while dimmed.
+	DimContrast uint8
+
+	// Contrast is the contrast level while active, zero selects 0xFF. Calling SetContrast on the power
+	// manager changes it.
+	Contrast uint8
+
+	// BlankAfter is the idle time after which the display is switched off, or the screensaver is shown.
+	BlankAfter time.Duration
+
+	// SleepAfter is the idle time after which the controller enters sleep mode, for displays that are a
+	// [Sleeper]. Other displays are switched off.
+	SleepAfter time.Duration
+
+	// Screensaver draws into the display instead of switching it off after BlankAfter. It is called on every
+	// refresh while the screensaver is shown, the display is refreshed after it returns. The frame shown
+	// before the screensaver is restored on Touch.
+	Screensaver func(Display) error
+
+	// OnChange is called after the power state changes.
+	OnChange func(from, to PowerState)
+}
+
+// PowerManager is a Display that dims, blanks or puts the controller to sleep when idle, and restores it
+// on activity.
+//
+// Activity is a Refresh with changed frame contents, or a call to Touch. The power state is evaluated on
+// every refresh, so applications should keep calling Refresh while idle; refreshes with unchanged contents
+// don't count as activity, and aren't sent to the display while it's switched off.
+//
+// Applications that keep drawing while the screensaver is shown should redraw the whole frame, because the
+// screensaver draws into the same frame buffer.
+type PowerManager struct {
+	Display
+	config PowerConfig
+	now    func() time.Time
+
+	mu      sync.Mutex // guards touched and state
+	touched bool
+	state   PowerState
+
+	activity  time.Time // time of the last activity
+	hash      uint64    // hash of the last application frame
+	saverHash uint64    // hash of the last screensaver frame
+	saved     func()    // restores the frame shown before the screensaver
+	hidden    bool      // display switched off with Show
+	dimmed    bool      // contrast reduced
+	asleep    bool      // controller in sleep mode
+	initial   bool      // first refresh done
+}
+
+// NewPowerManager manages the power state of d.
+func NewPowerManager(d Display, config *PowerConfig) *PowerManager {
+	if config == nil {
+		config = new(PowerConfig)
+	}
+	m := &PowerManager{
+		Display: d,
+		config:  *config,
+		now:     time.Now,
+	}
+	if m.config.Contrast == 0 {
+		m.config.Contrast = 0xFF
+	}
+	m.activity = m.now()
+	return m
+}
+
+// Touch registers activity, such as user input. The display is restored on the next Refresh. Touch may be
+// called from any goroutine.
+func (m *PowerManager) Touch() {
+	m.mu.Lock()
+	m.touched = true
+	m.mu.Unlock()
+}
+
+// State returns the current power state, it may be called from any goroutine.
+func (m *PowerManager) State() PowerState {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.state
+}
+
+// SetContrast sets the contrast level while active. The display contrast is only changed if it's not dimmed.
+func (m *PowerManager) SetContrast(level uint8) error {
+	m.config.Contrast = level
+	if m.dimmed {
+		return nil
+	}
+	return m.Display.SetContrast(level)
+}
+
+// Refresh evaluates the power state and redraws the display, unless it's switched off.
+func (m *PowerManager) Refresh() error {
+	return m.refresh(m.Bounds(), true)
+}
+
+// RefreshRect evaluates the power state and redraws the part of the display inside r, unless it's switched
+// off.
+func (m *PowerManager) RefreshRect(r image.Rectangle) error {
+	return m.refresh(r, false)
+}
+
+func (m *PowerManager) refresh(r image.Rectangle, all bool) (err error) {
+	m.mu.Lock()
+	touched, from := m.touched, m.state
+	m.touched = false
+	m.mu.Unlock()
+
+	var (
+		now  = m.now()
+		hash = frameHash(m.Display)
+		// While the screensaver is shown, the frame is either the screensaver frame or the application
+		// frame redrawn over it; only other frames are changes.
+		changed = !m.initial || hash != m.hash && (from != PowerScreensaver || hash != m.saverHash)
+	)
+	if changed {
+		m.hash, m.initial = hash, true
+	}
+	if touched || changed {
+		m.activity = now
+	}
+
+	to := m.target(now.Sub(m.activity))
+	if to != from {
+		if to < PowerScreensaver && m.saved != nil {
+			if !changed {
+				// Show the frame from before the screensaver, unless the application drew a new frame.
+				m.saved()
+			}
+			m.saved = nil
+		}
+		if err = m.enter(to); err != nil {
+			return
+		}
+		m.mu.Lock()
+		m.state = to
+		m.mu.Unlock()
+		if m.config.OnChange != nil {
+			m.config.OnChange(from, to)
+		}
+		if to < PowerScreensaver && from >= PowerScreensaver {
+			// The frame was not sent while switched off.
+			all = true
+		}
+	}
+
+	switch to {
+	case PowerScreensaver:
+		if err = m.config.Screensaver(m.Display); err != nil {
+			return
+		}
+		m.saverHash = frameHash(m.Display)
+		return m.Display.Refresh()
+	case PowerBlanked, PowerSleep:
+		return nil
+	}
+	if all {
+		return m.Display.Refresh()
+	}
+	return RefreshRect(m.Display, r)
+}
+
+// target returns the power state after being idle for the provided duration.
+func (m *PowerManager) target(idle time.Duration) PowerState {
+	switch {
+	case m.config.SleepAfter > 0 && idle >= m.config.SleepAfter:
+		return PowerSleep
+	case m.config.BlankAfter > 0 && idle >= m.config.BlankAfter:
+		if m.config.Screensaver != nil {
+			return PowerScreensaver
+		}
+		return PowerBlanked
+	case m.config.DimAfter > 0 && idle >= m.config.DimAfter:
+		return PowerDimmed
+	default:
+		return PowerOn
+	}
+}
+
+// enter switches the display to the provided state.
+func (m *PowerManager) enter(state PowerState) (err error) {
+	if m.asleep && state != PowerSleep {
+		s, _ := As[Sleeper](m.Display)
+		if err = s.SetSleep(false); err != nil {
+			return
+		}
+		m.asleep = false
+	}
+	if m.hidden && state < PowerBlanked {
+		if err = m.Display.Show(true); err != nil {
+			return
+		}
+		m.hidden = false
+	}
+
+	if dim := state >= PowerDimmed && m.config.DimAfter > 0; dim != m.dimmed {
+		contrast := m.config.Contrast
+		if dim {
+			contrast = m.config.DimContrast
+		}
+		if err = m.Display.SetContrast(contrast); err != nil {
+			return
+		}
+		m.dimmed = dim
+	}
+
+	switch state {
+	case PowerScreensaver:
+		if m.saved == nil {
+			m.saved = saveFrame(m.Display)
+		}
+	case PowerSleep:
+		if s, ok := As[Sleeper](m.Display); ok {
+			if err = s.SetSleep(true); err != nil {
+				return
+			}
+			m.asleep = true
+			return
+		}
+		fallthrough
+	case PowerBlanked:
+		if !m.hidden {
+			if err = m.Display.Show(false); err != nil {
+				return
+			}
+			m.hidden = true
+		}
+	}
+	return
+}
+
+// Unwrap returns the managed display.
+func (m *PowerManager) Unwrap() Display {
+	return m.Display
+}
+
+// frameHash returns a hash of the display contents.
+func frameHash(d Display) uint64 {
+	h := fnv.New64a()
+	if buffer, _, _ := frameBuffer(d); buffer != nil {
+		h.Write(buffer.Pix)
+		return h.Sum64()
+	}
+	var (
+		b     = d.Bounds()
+		frame = image.NewRGBA(b)
+	)
+	draw.Draw(frame, b, d, b.Min, draw.Src)
+	h.Write(frame.Pix)
+	return h.Sum64()
+}
+
+// saveFrame returns a function that restores the current display contents.
+func saveFrame(d Display) (restore func()) {
+	if buffer, _, _ := frameBuffer(d); buffer != nil {
+		pix := slices.Clone(buffer.Pix)
+		return func() { copy(buffer.Pix, pix) }
+	}
+	var (
+		b     = d.Bounds()
+		frame = image.NewRGBA(b)
+	)
+	draw.Draw(frame, b, d, b.Min, draw.Src)
+	return func() { draw.Draw(d, b, frame, b.Min, draw.Src) }
+}
+
+// Interface checks
+var (
+	_ WindowRefresher = (*PowerManager)(nil)
+	_ Wrapper         = (*PowerManager)(nil)
+)
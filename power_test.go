@@ -0,0 +1,255 @@
+package display
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/BeatGlow/display/conntest"
+	"github.com/BeatGlow/display/pixel"
+)
+
+func newPowerManager(d Display, config *PowerConfig) (*PowerManager, *testClock) {
+	var (
+		clock = &testClock{t: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+		m     = NewPowerManager(d, config)
+	)
+	m.now = clock.now
+	m.activity = clock.t
+	return m, clock
+}
+
+func TestPowerManager(t *testing.T) {
+	c := conntest.NewRecorder()
+	d, err := Open("ssd1306", c, &Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var changes []PowerState
+	m, clock := newPowerManager(d, &PowerConfig{
+		DimAfter:    10 * time.Second,
+		DimContrast: 0x10,
+		Contrast:    0xCF,
+		BlankAfter:  20 * time.Second,
+		SleepAfter:  30 * time.Second,
+		OnChange:    func(_, to PowerState) { changes = append(changes, to) },
+	})
+
+	for i, step := range []struct {
+		After    time.Duration
+		Touch    bool
+		Draw     bool
+		Want     PowerState
+		Commands []byte
+		Data     bool
+	}{
+		{0, false, false, PowerOn, nil, true},
+		{9 * time.Second, false, false, PowerOn, nil, true},
+		{time.Second, false, false, PowerDimmed, []byte{ssd1xxxSetContrast}, true},
+		{10 * time.Second, false, false, PowerBlanked, []byte{ssd1xxxSetDisplayOff}, false},
+		{10 * time.Second, false, false, PowerSleep, nil, false},
+		{time.Second, true, false, PowerOn, []byte{ssd1xxxSetDisplayOn, ssd1xxxSetContrast}, true},
+		{9 * time.Second, false, true, PowerOn, nil, true},
+		{9 * time.Second, false, false, PowerOn, nil, true},
+		{time.Second, false, false, PowerDimmed, []byte{ssd1xxxSetContrast}, true},
+		{time.Second, false, true, PowerOn, []byte{ssd1xxxSetContrast}, true},
+	} {
+		clock.t = clock.t.Add(step.After)
+		if step.Touch {
+			m.Touch()
+		}
+		if step.Draw {
+			m.Set(i, 0, pixel.On)
+		}
+		c.Clear()
+		if err = m.Refresh(); err != nil {
+			t.Fatal(err)
+		}
+
+		if state := m.State(); state != step.Want {
+			t.Errorf("step %d: expected state %s, got %s", i, step.Want, state)
+		}
+		var commands []byte
+		for _, call := range c.Filter(conntest.OpCommand) {
+			switch call.Command {
+			case ssd1xxxSetContrast, ssd1xxxSetDisplayOff, ssd1xxxSetDisplayOn:
+				commands = append(commands, call.Command)
+			}
+		}
+		if string(commands) != string(step.Commands) {
+			t.Errorf("step %d: expected commands %#02x, got %#02x", i, step.Commands, commands)
+		}
+		if data := c.DataLen() > 0; data != step.Data {
+			t.Errorf("step %d: expected data %t, got %t", i, step.Data, data)
+		}
+	}
+
+	want := []PowerState{PowerDimmed, PowerBlanked, PowerSleep, PowerOn, PowerDimmed, PowerOn}
+	if !reflect.DeepEqual(changes, want) {
+		t.Errorf("expected changes %s, got %s", want, changes)
+	}
+
+	// The active contrast is restored after dimming.
+	if err = m.SetContrast(0x80); err != nil {
+		t.Fatal(err)
+	}
+	clock.t = clock.t.Add(10 * time.Second)
+	if err = m.Refresh(); err != nil {
+		t.Fatal(err)
+	}
+	c.Clear()
+	if err = m.SetContrast(0x90); err != nil {
+		t.Fatal(err)
+	}
+	if calls := c.Calls(); len(calls) != 0 {
+		t.Errorf("expected no contrast change while dimmed, got %v", calls)
+	}
+	m.Touch()
+	if err = m.Refresh(); err != nil {
+		t.Fatal(err)
+	}
+	if calls := c.Filter(conntest.OpCommand); len(calls) == 0 || calls[0].Command != ssd1xxxSetContrast || calls[0].Data[0] != 0x90 {
+		t.Errorf("expected contrast 0x90 to be restored, got %v", calls)
+	}
+}
+
+func TestPowerManagerSleep(t *testing.T) {
+	t.Run("driver", func(t *testing.T) { testPowerManagerSleep(t, func(d Display) Display { return d }) })
+	t.Run("wrapped", func(t *testing.T) {
+		testPowerManagerSleep(t, func(d Display) Display { return TrackDamage(ProtectBurnIn(d, nil)) })
+	})
+}
+
+func testPowerManagerSleep(t *testing.T, wrap func(Display) Display) {
+	c := conntest.NewSPIRecorder()
+	d, err := Open("st7789", c, &Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, clock := newPowerManager(wrap(d), &PowerConfig{BlankAfter: 5 * time.Second, SleepAfter: 10 * time.Second})
+	if err = m.Refresh(); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, step := range []struct {
+		After   time.Duration
+		Touch   bool
+		Command byte
+	}{
+		{5 * time.Second, false, st7789DISPOFF},
+		{5 * time.Second, false, st7789SLPIN},
+		{0, true, st7789SLPOUT},
+	} {
+		clock.t = clock.t.Add(step.After)
+		if step.Touch {
+			m.Touch()
+		}
+		c.Clear()
+		if err = m.Refresh(); err != nil {
+			t.Fatal(err)
+		}
+		if calls := c.Filter(conntest.OpCommand); len(calls) == 0 || calls[0].Command != step.Command {
+			t.Errorf("%s: expected command %#02x, got %v", m.State(), step.Command, calls)
+		}
+	}
+}
+
+func TestPowerManagerScreensaver(t *testing.T) {
+	var (
+		white = pixel.Gray4{Y: 15}
+		d     = &frameDisplay{testDisplay: &testDisplay{baseDisplay: baseDisplay{Image: pixel.NewGray4Image(32, 16)}}}
+		saves int
+	)
+	m, clock := newPowerManager(d, &PowerConfig{
+		BlankAfter: 10 * time.Second,
+		Screensaver: func(d Display) error {
+			saves++
+			d.Clear()
+			d.Set(saves, 0, white)
+			return nil
+		},
+	})
+
+	m.Set(5, 5, white)
+	if err := m.Refresh(); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 1; i <= 2; i++ {
+		clock.t = clock.t.Add(10 * time.Second)
+		if err := m.Refresh(); err != nil {
+			t.Fatal(err)
+		}
+		frame := d.frames[len(d.frames)-1]
+		if m.State() != PowerScreensaver || saves != i || frame.At(i, 0) != white || frame.At(5, 5) == white {
+			t.Fatalf("expected screensaver frame %d, state %s", i, m.State())
+		}
+	}
+
+	// Touch restores the frame from before the screensaver.
+	m.Touch()
+	if err := m.Refresh(); err != nil {
+		t.Fatal(err)
+	}
+	if frame := d.frames[len(d.frames)-1]; m.State() != PowerOn || frame.At(5, 5) != white || frame.At(2, 0) == white {
+		t.Errorf("expected the frame to be restored, state %s", m.State())
+	}
+
+	// Drawing a new frame stops the screensaver.
+	clock.t = clock.t.Add(10 * time.Second)
+	if err := m.Refresh(); err != nil {
+		t.Fatal(err)
+	}
+	m.Clear()
+	m.Set(7, 7, white)
+	if err := m.Refresh(); err != nil {
+		t.Fatal(err)
+	}
+	if frame := d.frames[len(d.frames)-1]; m.State() != PowerOn || frame.At(7, 7) != white || frame.At(3, 0) == white {
+		t.Errorf("expected the new frame to be shown, state %s", m.State())
+	}
+}
+
+func TestPowerManagerScreensaverRedraw(t *testing.T) {
+	var (
+		white = pixel.Gray4{Y: 15}
+		d     = &frameDisplay{testDisplay: &testDisplay{baseDisplay: baseDisplay{Image: pixel.NewGray4Image(32, 16)}}}
+		saves int
+	)
+	m, clock := newPowerManager(d, &PowerConfig{
+		BlankAfter: 10 * time.Second,
+		Screensaver: func(d Display) error {
+			saves++
+			d.Clear()
+			d.Set(saves, 0, white)
+			return nil
+		},
+	})
+
+	// The application redraws the same frame every tick, which doesn't stop the screensaver.
+	for i := 0; i < 5; i++ {
+		m.Clear()
+		m.Set(5, 5, white)
+		if err := m.Refresh(); err != nil {
+			t.Fatal(err)
+		}
+		if want := PowerScreensaver; i > 0 && m.State() != want {
+			t.Fatalf("tick %d: expected state %s, got %s", i, want, m.State())
+		}
+		clock.t = clock.t.Add(10 * time.Second)
+	}
+	if saves != 4 {
+		t.Errorf("expected the screensaver to draw 4 frames, got %d", saves)
+	}
+
+	// A different frame stops it.
+	m.Clear()
+	m.Set(7, 7, white)
+	if err := m.Refresh(); err != nil {
+		t.Fatal(err)
+	}
+	if frame := d.frames[len(d.frames)-1]; m.State() != PowerOn || frame.At(7, 7) != white {
+		t.Errorf("expected the new frame to be shown, state %s", m.State())
+	}
+}
@@ -0,0 +1,12 @@
+// Package preview serves the content of a display over HTTP.
+//
+// A [Server] wraps a display and captures the frame buffer on every refresh, in addition to forwarding the
+// refresh to the hardware. The captured frames are served as:
+//
+//	/              an HTML page showing the live stream
+//	/snapshot.png  the current frame as PNG image
+//	/stream.mjpeg  a Motion JPEG stream, with a new frame for every refresh
+//
+// Frames are scaled up with nearest neighbour interpolation, so small displays remain legible. The scale
+// factor can be changed per request with the scale query parameter, for example /snapshot.png?scale=1.
+package preview
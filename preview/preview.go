@@ -0,0 +1,285 @@
+package preview
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"strconv"
+	"sync"
+
+	xdraw "golang.org/x/image/draw"
+
+	"github.com/BeatGlow/display"
+)
+
+// Defaults.
+const (
+	// DefaultWidth is the width frames are scaled up to, if no scale factor is configured.
+	DefaultWidth = 512
+
+	// DefaultQuality is the JPEG quality of the stream.
+	DefaultQuality = 90
+
+	// MaxScale is the largest supported scale factor.
+	MaxScale = 16
+)
+
+// Server is a display that serves its content over HTTP.
+type Server struct {
+	display.Display
+
+	// Scale is the default scale factor, if zero frames are scaled up to about DefaultWidth pixels.
+	Scale int
+
+	// Quality is the JPEG quality of the stream, defaults to DefaultQuality.
+	Quality int
+
+	mu      sync.Mutex
+	frame   *image.RGBA
+	changed chan struct{} // closed when a new frame is captured
+	closed  bool
+	cache   map[cacheKey][]byte // encoded images of the current frame
+	mux     *http.ServeMux
+}
+
+type cacheKey struct {
+	format string
+	scale  int
+}
+
+// New returns a display that serves the content of d over HTTP.
+func New(d display.Display) *Server {
+	s := &Server{
+		Display: d,
+		changed: make(chan struct{}),
+		cache:   make(map[cacheKey][]byte),
+		mux:     http.NewServeMux(),
+	}
+	s.mux.HandleFunc("/{$}", s.serveIndex)
+	s.mux.HandleFunc("/snapshot.png", s.serveSnapshot)
+	s.mux.HandleFunc("/stream.mjpeg", s.serveStream)
+	s.capture()
+	return s
+}
+
+// ListenAndServe serves the display on the TCP network address addr.
+func (s *Server) ListenAndServe(addr string) error {
+	return http.ListenAndServe(addr, s)
+}
+
+func (s *Server) String() string {
+	return "preview " + s.Display.String()
+}
+
+// Close the display, this also ends all running streams.
+func (s *Server) Close() error {
+	s.mu.Lock()
+	if !s.closed {
+		s.closed = true
+		close(s.changed)
+	}
+	s.mu.Unlock()
+	return s.Display.Close()
+}
+
+// Refresh redraws the display and captures the frame.
+func (s *Server) Refresh() error {
+	err := s.Display.Refresh()
+	s.capture()
+	return err
+}
+
+// RefreshRect redraws part of the display, if supported by the display, and captures the frame.
+func (s *Server) RefreshRect(r image.Rectangle) error {
+	err := display.RefreshRect(s.Display, r)
+	s.capture()
+	return err
+}
+
+// capture the display content. Captures happen on the goroutine that draws to the display, so the
+// HTTP handlers never access the display.
+func (s *Server) capture() {
+	var (
+		b     = s.Display.Bounds()
+		frame = image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	)
+	draw.Draw(frame, frame.Rect, s.Display, b.Min, draw.Src)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return
+	}
+	s.frame = frame
+	clear(s.cache)
+	close(s.changed)
+	s.changed = make(chan struct{})
+}
+
+// Frame returns the last captured frame and a channel that is closed when the next frame is captured.
+func (s *Server) Frame() (image.Image, <-chan struct{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.frame, s.changed
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// scale returns the scale factor for a request.
+func (s *Server) scale(r *http.Request, width int) (int, error) {
+	if v := r.URL.Query().Get("scale"); v != "" {
+		scale, err := strconv.Atoi(v)
+		if err != nil || scale < 1 || scale > MaxScale {
+			return 0, fmt.Errorf("invalid scale %q", v)
+		}
+		return scale, nil
+	}
+	if s.Scale > 0 {
+		return min(s.Scale, MaxScale), nil
+	}
+	return max(1, min(DefaultWidth/max(width, 1), MaxScale)), nil
+}
+
+// encode the current frame, encoded frames are cached until the next frame is captured. Captured frames are
+// never modified, so they are encoded without holding the lock, which would block refreshes.
+func (s *Server) encode(format string, scale int) (b []byte, changed <-chan struct{}, err error) {
+	key := cacheKey{format, scale}
+	s.mu.Lock()
+	src, changed := s.frame, s.changed
+	b = s.cache[key]
+	s.mu.Unlock()
+	if b != nil {
+		return b, changed, nil
+	}
+
+	var (
+		dst = image.NewRGBA(image.Rect(0, 0, src.Rect.Dx()*scale, src.Rect.Dy()*scale))
+		buf = new(bytes.Buffer)
+	)
+	xdraw.NearestNeighbor.Scale(dst, dst.Rect, src, src.Rect, xdraw.Src, nil)
+	if format == "png" {
+		err = png.Encode(buf, dst)
+	} else {
+		quality := s.Quality
+		if quality <= 0 {
+			quality = DefaultQuality
+		}
+		err = jpeg.Encode(buf, dst, &jpeg.Options{Quality: quality})
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	s.mu.Lock()
+	if s.frame == src {
+		s.cache[key] = buf.Bytes()
+	}
+	s.mu.Unlock()
+	return buf.Bytes(), changed, nil
+}
+
+func (s *Server) serveIndex(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_, _ = fmt.Fprintf(w, indexPage, s.Display.String())
+}
+
+const indexPage = `<!DOCTYPE html>
+<html>
+<head><title>%[1]s</title></head>
+<body style="background:#222;color:#ccc;font-family:sans-serif">
+<p>%[1]s</p>
+<img src="stream.mjpeg" style="image-rendering:pixelated">
+</body>
+</html>
+`
+
+func (s *Server) serveSnapshot(w http.ResponseWriter, r *http.Request) {
+	scale, err := s.scale(r, s.frameWidth())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	b, _, err := s.encode("png", scale)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "image/png")
+	w.Header().Set("Cache-Control", "no-store")
+	_, _ = w.Write(b)
+}
+
+func (s *Server) serveStream(w http.ResponseWriter, r *http.Request) {
+	scale, err := s.scale(r, s.frameWidth())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	mw := multipart.NewWriter(w)
+	defer func() { _ = mw.Close() }()
+	w.Header().Set("Content-Type", "multipart/x-mixed-replace; boundary="+mw.Boundary())
+	w.Header().Set("Cache-Control", "no-store")
+	flusher, _ := w.(http.Flusher)
+	for {
+		b, changed, err := s.encode("jpeg", scale)
+		if err != nil {
+			return
+		}
+		if err = writePart(mw, b); err != nil {
+			return
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+
+		select {
+		case <-changed:
+			if s.isClosed() {
+				return
+			}
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+func writePart(mw *multipart.Writer, b []byte) error {
+	part, err := mw.CreatePart(textproto.MIMEHeader{
+		"Content-Type":   {"image/jpeg"},
+		"Content-Length": {strconv.Itoa(len(b))},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(part, bytes.NewReader(b))
+	return err
+}
+
+func (s *Server) frameWidth() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.frame.Rect.Dx()
+}
+
+func (s *Server) isClosed() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.closed
+}
+
+// Interface checks
+var (
+	_ display.Display         = (*Server)(nil)
+	_ display.WindowRefresher = (*Server)(nil)
+	_ http.Handler            = (*Server)(nil)
+)
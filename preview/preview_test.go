@@ -0,0 +1,164 @@
+package preview_test
+
+import (
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/BeatGlow/display"
+	"github.com/BeatGlow/display/pixel"
+	"github.com/BeatGlow/display/preview"
+	"github.com/BeatGlow/display/snapshot"
+)
+
+func newServer(t *testing.T) (*preview.Server, *httptest.Server) {
+	t.Helper()
+
+	d, err := snapshot.NewFile("", &snapshot.Config{Config: display.Config{Width: 128, Height: 64}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := preview.New(d)
+	ts := httptest.NewServer(s)
+	t.Cleanup(ts.Close)
+	return s, ts
+}
+
+func TestSnapshot(t *testing.T) {
+	s, ts := newServer(t)
+	s.Set(1, 0, pixel.On)
+	if err := s.Refresh(); err != nil {
+		t.Fatal(err)
+	}
+	if n := s.Display.(*snapshot.File).Frames(); n != 1 {
+		t.Errorf("expected refresh to be forwarded, got %d frames", n)
+	}
+
+	for _, test := range []struct {
+		Query  string
+		Width  int
+		Height int
+		Scale  int
+	}{
+		{"", 512, 256, 4},
+		{"?scale=1", 128, 64, 1},
+		{"?scale=2", 256, 128, 2},
+	} {
+		res, err := http.Get(ts.URL + "/snapshot.png" + test.Query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		i, err := png.Decode(res.Body)
+		_ = res.Body.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if b := i.Bounds(); b.Dx() != test.Width || b.Dy() != test.Height {
+			t.Errorf("%q: expected %dx%d image, got %s", test.Query, test.Width, test.Height, b)
+		}
+		for _, p := range [][2]int{{test.Scale, 0}, {test.Scale*2 - 1, test.Scale - 1}} {
+			if v := color.GrayModel.Convert(i.At(p[0], p[1])).(color.Gray).Y; v != 0xFF {
+				t.Errorf("%q: expected pixel %d,%d to be on", test.Query, p[0], p[1])
+			}
+		}
+		if v := color.GrayModel.Convert(i.At(test.Scale*2, 0)).(color.Gray).Y; v != 0 {
+			t.Errorf("%q: expected pixel %d,0 to be off", test.Query, test.Scale*2)
+		}
+	}
+
+	res, err := http.Get(ts.URL + "/snapshot.png?scale=100")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = res.Body.Close()
+	if res.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected status %d for invalid scale, got %d", http.StatusBadRequest, res.StatusCode)
+	}
+}
+
+func TestStream(t *testing.T) {
+	s, ts := newServer(t)
+
+	res, err := http.Get(ts.URL + "/stream.mjpeg?scale=1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = res.Body.Close() }()
+
+	mediaType, params, err := mime.ParseMediaType(res.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/x-mixed-replace" {
+		t.Fatalf("unexpected content type %q", res.Header.Get("Content-Type"))
+	}
+
+	var (
+		mr   = multipart.NewReader(res.Body, params["boundary"])
+		next = func() *multipart.Part {
+			part, err := mr.NextPart()
+			if err != nil {
+				t.Fatal(err)
+			}
+			return part
+		}
+	)
+
+	// The current frame is sent immediately.
+	i, err := jpeg.Decode(next())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := color.GrayModel.Convert(i.At(64, 32)).(color.Gray).Y; v > 0x10 {
+		t.Errorf("expected blank frame, got %#02x", v)
+	}
+
+	s.Fill(pixel.On)
+	if err = s.Refresh(); err != nil {
+		t.Fatal(err)
+	}
+	part := next()
+	if v := part.Header.Get("Content-Type"); v != "image/jpeg" {
+		t.Errorf("expected image/jpeg part, got %q", v)
+	}
+	if i, err = jpeg.Decode(part); err != nil {
+		t.Fatal(err)
+	}
+	if v := color.GrayModel.Convert(i.At(64, 32)).(color.Gray).Y; v < 0xF0 {
+		t.Errorf("expected filled frame, got %#02x", v)
+	}
+
+	// Closing the display ends the stream.
+	if err = s.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = mr.NextPart(); err != io.EOF {
+		t.Errorf("expected stream to end, got %v", err)
+	}
+}
+
+func TestIndex(t *testing.T) {
+	_, ts := newServer(t)
+
+	res, err := http.Get(ts.URL + "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := io.ReadAll(res.Body)
+	_ = res.Body.Close()
+	if !strings.Contains(string(b), "stream.mjpeg") {
+		t.Errorf("expected index page to show the stream, got %q", b)
+	}
+
+	if res, err = http.Get(ts.URL + "/other"); err != nil {
+		t.Fatal(err)
+	}
+	_ = res.Body.Close()
+	if res.StatusCode != http.StatusNotFound {
+		t.Errorf("expected status %d, got %d", http.StatusNotFound, res.StatusCode)
+	}
+}
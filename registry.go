@@ -0,0 +1,148 @@
+package display
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Bus is a set of bus types a display can be connected to.
+type Bus uint8
+
+// Supported buses.
+const (
+	I2CBus Bus = 1 << iota
+	SPIBus
+	ParallelBus
+)
+
+func (b Bus) String() string {
+	var names []string
+	if b&I2CBus != 0 {
+		names = append(names, "i2c")
+	}
+	if b&SPIBus != 0 {
+		names = append(names, "spi")
+	}
+	if b&ParallelBus != 0 {
+		names = append(names, "parallel")
+	}
+	if len(names) == 0 {
+		return "none"
+	}
+	return strings.Join(names, "|")
+}
+
+// Driver describes a display driver.
+type Driver struct {
+	// Name of the driver, typically the controller name in lower case.
+	Name string
+
+	// Aliases are alternative names for the driver.
+	Aliases []string
+
+	// Buses the driver can be used with.
+	Buses Bus
+
+	// Width is the default display width in pixels.
+	Width int
+
+	// Height is the default display height in pixels.
+	Height int
+
+	// New opens a display on the provided connection.
+	New func(Conn, *Config) (Display, error)
+}
+
+var (
+	driversMu sync.RWMutex
+	drivers   = make(map[string]*Driver) // by name and alias
+)
+
+// Register makes a display driver available by its name and aliases. Names are case insensitive.
+//
+// Register panics if the driver has no name or constructor, or if any of its names are already registered.
+func Register(driver Driver) {
+	if driver.Name == "" {
+		panic("display: Register driver without a name")
+	}
+	if driver.New == nil {
+		panic("display: Register driver " + driver.Name + " without a constructor")
+	}
+
+	driversMu.Lock()
+	defer driversMu.Unlock()
+
+	names := append([]string{driver.Name}, driver.Aliases...)
+	for _, name := range names {
+		if _, dup := drivers[strings.ToLower(name)]; dup {
+			panic("display: Register called twice for driver " + name)
+		}
+	}
+	for _, name := range names {
+		drivers[strings.ToLower(name)] = &driver
+	}
+}
+
+// Drivers returns all registered drivers, sorted by name.
+func Drivers() []Driver {
+	driversMu.RLock()
+	defer driversMu.RUnlock()
+
+	list := make([]Driver, 0, len(drivers))
+	for name, driver := range drivers {
+		if strings.EqualFold(name, driver.Name) {
+			list = append(list, *driver)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Name < list[j].Name
+	})
+	return list
+}
+
+// Lookup a driver by its name or one of its aliases.
+func Lookup(name string) (Driver, bool) {
+	driversMu.RLock()
+	defer driversMu.RUnlock()
+
+	driver, ok := drivers[strings.ToLower(name)]
+	if !ok {
+		return Driver{}, false
+	}
+	return *driver, true
+}
+
+// Open a display using the driver registered by name.
+func Open(name string, c Conn, config *Config) (Display, error) {
+	driver, ok := Lookup(name)
+	if !ok {
+		return nil, fmt.Errorf("%w %q", ErrUnknownDriver, name)
+	}
+	if bus := connBus(c); bus != 0 && driver.Buses&bus == 0 {
+		return nil, fmt.Errorf("display: driver %s does not support %s bus", driver.Name, bus)
+	}
+	if config == nil {
+		config = new(Config)
+	}
+	return driver.New(c, config)
+}
+
+// connBus returns the bus type of the connections in this package, or 0 if unknown.
+func connBus(c Conn) Bus {
+	switch c := c.(type) {
+	case *contextConn:
+		return connBus(c.Conn)
+	case *contextSPI:
+		return connBus(c.Conn)
+	case *i2cConn:
+		return I2CBus
+	case *spiConn:
+		return SPIBus
+	case *parallelConn:
+		return ParallelBus
+	default:
+		return 0
+	}
+}
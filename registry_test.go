@@ -0,0 +1,84 @@
+package display
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestLookup(t *testing.T) {
+	tests := []struct {
+		Name string
+		Want string
+	}{
+		{"ssd1306", "ssd1306"},
+		{"SSD1322", "ssd1322"},
+		{"st7789v", "st7789"},
+		{"GP1278", "gp1287"},
+	}
+	for _, test := range tests {
+		driver, ok := Lookup(test.Name)
+		if !ok {
+			t.Errorf("expected driver %q to be registered", test.Name)
+			continue
+		}
+		if driver.Name != test.Want {
+			t.Errorf("expected %q to resolve to driver %q, got %q", test.Name, test.Want, driver.Name)
+		}
+		if driver.New == nil || driver.Buses == 0 || driver.Width == 0 || driver.Height == 0 {
+			t.Errorf("driver %q is incomplete: %+v", driver.Name, driver)
+		}
+	}
+
+	if _, ok := Lookup("ssd9999"); ok {
+		t.Error("expected unknown driver lookup to fail")
+	}
+}
+
+func TestDrivers(t *testing.T) {
+	drivers := Drivers()
+	if len(drivers) < 10 {
+		t.Fatalf("expected at least 10 drivers, got %d", len(drivers))
+	}
+	for i := 1; i < len(drivers); i++ {
+		if drivers[i-1].Name >= drivers[i].Name {
+			t.Errorf("expected drivers sorted by name, got %q before %q", drivers[i-1].Name, drivers[i].Name)
+		}
+	}
+}
+
+func TestRegister(t *testing.T) {
+	var opened bool
+	Register(Driver{
+		Name:    "test-register",
+		Aliases: []string{"test-register-alias"},
+		New: func(Conn, *Config) (Display, error) {
+			opened = true
+			return nil, nil
+		},
+	})
+
+	if _, err := Open("Test-Register-Alias", nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if !opened {
+		t.Error("expected Open to call the driver constructor")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected duplicate Register to panic")
+		}
+	}()
+	Register(Driver{
+		Name: "test-register-alias",
+		New: func(Conn, *Config) (Display, error) {
+			return nil, nil
+		},
+	})
+}
+
+func TestOpenUnknown(t *testing.T) {
+	if _, err := Open("ssd9999", nil, nil); !errors.Is(err, ErrUnknownDriver) {
+		t.Errorf("expected ErrUnknownDriver, got %v", err)
+	}
+}
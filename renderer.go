@@ -0,0 +1,350 @@
+package display
+
+import (
+	"context"
+	"errors"
+	"image"
+	"image/color"
+	"image/draw"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/BeatGlow/display/pixel"
+)
+
+// ErrRendererClosed is returned after closing a [Renderer].
+var ErrRendererClosed = errors.New("display: renderer closed")
+
+// RendererConfig is the [Renderer] configuration.
+type RendererConfig struct {
+	// FrameRate is the maximum number of frames per second, zero means unlimited.
+	FrameRate float64
+
+	// OnError is called on the render goroutine when a refresh fails, if set.
+	OnError func(error)
+}
+
+// Renderer owns a display on a dedicated goroutine, and offers a back buffer that is safe to draw into from
+// multiple goroutines.
+//
+// Drawing is done off screen in the back buffer, until Present copies it to the front buffer for the render
+// goroutine to draw. If the render goroutine is still busy with a previous frame, presented frames that were
+// not drawn yet are dropped, only the latest frame is drawn.
+//
+// The display must not be used directly while the renderer is running, use Do instead.
+type Renderer struct {
+	d        Display
+	interval time.Duration
+	onError  func(error)
+
+	mu      sync.Mutex // guards the back buffer
+	back    draw.Image
+	backPix []byte
+
+	frontMu sync.Mutex // guards the front buffer and statistics
+	front   []byte
+	pending bool
+	frames  int
+	dropped int
+	err     error
+	closed  bool
+
+	flush  func(front []byte) // copies the front buffer to the display, on the render goroutine
+	wake   chan struct{}
+	do     chan renderRequest
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+type renderRequest struct {
+	fn  func(Display) error
+	err chan error
+}
+
+// NewRenderer starts a render goroutine for d. The back buffer starts with the current display contents.
+func NewRenderer(d Display, config *RendererConfig) *Renderer {
+	if config == nil {
+		config = new(RendererConfig)
+	}
+	r := &Renderer{
+		d:       d,
+		onError: config.OnError,
+		wake:    make(chan struct{}, 1),
+		do:      make(chan renderRequest),
+		done:    make(chan struct{}),
+	}
+	if config.FrameRate > 0 {
+		r.interval = time.Duration(float64(time.Second) / config.FrameRate)
+	}
+	r.ctx, r.cancel = context.WithCancel(context.Background())
+
+	if o, ok := d.(offscreener); ok {
+		if back, src, dst := o.offscreen(); back != nil {
+			r.back, r.backPix = back, src.Pix
+			r.flush = func(front []byte) { copy(dst.Pix, front) }
+		}
+	}
+	if r.back == nil {
+		// Draw into an RGBA image, which is copied to the display pixel by pixel.
+		var (
+			b    = d.Bounds()
+			back = image.NewRGBA(b)
+		)
+		draw.Draw(back, b, d, b.Min, draw.Src)
+		r.back, r.backPix = back, back.Pix
+		r.flush = func(front []byte) {
+			i := &image.RGBA{Pix: front, Stride: back.Stride, Rect: b}
+			for y := b.Min.Y; y < b.Max.Y; y++ {
+				for x := b.Min.X; x < b.Max.X; x++ {
+					d.Set(x, y, i.RGBAAt(x, y))
+				}
+			}
+		}
+	}
+	r.front = slices.Clone(r.backPix)
+
+	go r.run()
+	return r
+}
+
+// Display returns the display owned by the renderer.
+func (r *Renderer) Display() Display {
+	return r.d
+}
+
+// ColorModel returns the color model of the display.
+func (r *Renderer) ColorModel() color.Model {
+	return r.d.ColorModel()
+}
+
+// Bounds of the back buffer.
+func (r *Renderer) Bounds() image.Rectangle {
+	return r.back.Bounds()
+}
+
+// At returns the color of the pixel at (x, y) in the back buffer.
+func (r *Renderer) At(x, y int) color.Color {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.back.At(x, y)
+}
+
+// Set the color of the pixel at (x, y) in the back buffer.
+func (r *Renderer) Set(x, y int, c color.Color) {
+	r.mu.Lock()
+	r.back.Set(x, y, r.d.ColorModel().Convert(c))
+	r.mu.Unlock()
+}
+
+// Draw calls fn with exclusive access to the back buffer, which is faster than calling Set for every
+// pixel. The back buffer must not be retained after fn returns.
+func (r *Renderer) Draw(fn func(draw.Image)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	fn(r.back)
+}
+
+// Clear the back buffer.
+func (r *Renderer) Clear() {
+	r.Fill(color.Black)
+}
+
+// Fill the back buffer with a single color.
+func (r *Renderer) Fill(c color.Color) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	draw.Draw(r.back, r.back.Bounds(), image.NewUniform(r.d.ColorModel().Convert(c)), image.Point{}, draw.Src)
+}
+
+// Present copies the back buffer to the front buffer and wakes up the render goroutine. A previously
+// presented frame that was not drawn yet is dropped.
+//
+// Present returns the error of the last failed refresh, if any, and clears it.
+func (r *Renderer) Present() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.frontMu.Lock()
+	defer r.frontMu.Unlock()
+
+	if r.closed {
+		return ErrRendererClosed
+	}
+	copy(r.front, r.backPix)
+	if r.pending {
+		r.dropped++
+	}
+	r.pending = true
+
+	select {
+	case r.wake <- struct{}{}:
+	default:
+	}
+
+	err := r.err
+	r.err = nil
+	return err
+}
+
+// Do calls fn with the display on the render goroutine, after presented frames are drawn, and returns its
+// error. Use Do for hardware access other than drawing, such as changing the contrast.
+//
+// Changing the display rotation or size is not supported.
+func (r *Renderer) Do(fn func(Display) error) error {
+	req := renderRequest{fn: fn, err: make(chan error, 1)}
+	select {
+	case r.do <- req:
+		return <-req.err
+	case <-r.done:
+		return ErrRendererClosed
+	}
+}
+
+// Stats returns the number of drawn frames, and the number of frames that were presented but dropped.
+func (r *Renderer) Stats() (frames, dropped int) {
+	r.frontMu.Lock()
+	defer r.frontMu.Unlock()
+	return r.frames, r.dropped
+}
+
+// Close stops the render goroutine and closes the display. A refresh in progress is abandoned, a
+// presented frame that was not drawn yet is dropped.
+func (r *Renderer) Close() error {
+	r.frontMu.Lock()
+	if r.closed {
+		r.frontMu.Unlock()
+		return ErrRendererClosed
+	}
+	r.closed = true
+	r.frontMu.Unlock()
+
+	r.cancel()
+	<-r.done
+	return r.d.Close()
+}
+
+func (r *Renderer) run() {
+	defer close(r.done)
+
+	var next time.Time // earliest time for the next frame
+	for {
+		select {
+		case <-r.ctx.Done():
+			return
+		case req := <-r.do:
+			// Draw presented frames first, so that fn sees them.
+			select {
+			case <-r.wake:
+				if !r.frame(&next) {
+					req.err <- ErrRendererClosed
+					return
+				}
+			default:
+			}
+			req.err <- req.fn(r.d)
+		case <-r.wake:
+			if !r.frame(&next) {
+				return
+			}
+		}
+	}
+}
+
+// frame draws the front buffer after waiting for the frame interval, it returns false if the renderer
+// was closed.
+func (r *Renderer) frame(next *time.Time) bool {
+	if wait := time.Until(*next); wait > 0 {
+		timer := time.NewTimer(wait)
+		select {
+		case <-r.ctx.Done():
+			timer.Stop()
+			return false
+		case <-timer.C:
+		}
+	}
+	*next = time.Now().Add(r.interval)
+
+	r.frontMu.Lock()
+	if !r.pending {
+		// The frame was already drawn, it was presented again while waiting for the previous frame.
+		r.frontMu.Unlock()
+		return true
+	}
+	r.flush(r.front)
+	r.pending = false
+	r.frames++
+	r.frontMu.Unlock()
+
+	if err := RefreshContext(r.ctx, r.d); err != nil {
+		if r.ctx.Err() != nil {
+			return false
+		}
+		r.frontMu.Lock()
+		r.err = err
+		r.frontMu.Unlock()
+		if r.onError != nil {
+			r.onError(err)
+		}
+	}
+	return true
+}
+
+// offscreener is a Display that can create an off screen copy of its frame buffer.
+type offscreener interface {
+	// offscreen returns an image with the same bounds, color model and rotation as the display, the pixel
+	// buffer of that image and the pixel buffer of the display. All return values are nil if the frame
+	// buffer can't be copied.
+	offscreen() (i draw.Image, src, dst *pixel.Buffer)
+}
+
+func (d *baseDisplay) offscreen() (draw.Image, *pixel.Buffer, *pixel.Buffer) {
+	var (
+		clone    draw.Image
+		src, dst *pixel.Buffer
+	)
+	switch i := d.Image.(type) {
+	case *pixel.MonoVerticalLSBImage:
+		c := *i
+		clone, src, dst = &c, &c.Buffer, &i.Buffer
+	case *pixel.MonoImage:
+		c := *i
+		clone, src, dst = &c, &c.Buffer, &i.Buffer
+	case *pixel.Gray2Image:
+		c := *i
+		clone, src, dst = &c, &c.Buffer, &i.Buffer
+	case *pixel.Gray4Image:
+		c := *i
+		clone, src, dst = &c, &c.Buffer, &i.Buffer
+	case *pixel.CBGR15Image:
+		c := *i
+		clone, src, dst = &c, &c.Buffer, &i.Buffer
+	case *pixel.CBGR16Image:
+		c := *i
+		clone, src, dst = &c, &c.Buffer, &i.Buffer
+	case *pixel.CRGB15Image:
+		c := *i
+		clone, src, dst = &c, &c.Buffer, &i.Buffer
+	case *pixel.CRGB16Image:
+		c := *i
+		clone, src, dst = &c, &c.Buffer, &i.Buffer
+	default:
+		return nil, nil, nil
+	}
+	src.Pix = slices.Clone(dst.Pix)
+	return &baseDisplay{Image: clone, rotation: d.rotation, transform: d.transform}, src, dst
+}
+
+func (d *damageTracker) offscreen() (draw.Image, *pixel.Buffer, *pixel.Buffer) {
+	if o, ok := d.Display.(offscreener); ok {
+		return o.offscreen()
+	}
+	return nil, nil, nil
+}
+
+// Interface checks
+var (
+	_ draw.Image  = (*Renderer)(nil)
+	_ offscreener = (*baseDisplay)(nil)
+	_ offscreener = (*damageTracker)(nil)
+)
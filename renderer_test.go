@@ -0,0 +1,286 @@
+package display
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	"image/draw"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/BeatGlow/display/conntest"
+)
+
+// slowConn delays every data call.
+type slowConn struct {
+	*conntest.Recorder
+	delay time.Duration
+}
+
+func (c *slowConn) Data(data ...byte) error {
+	time.Sleep(c.delay)
+	return c.Recorder.Data(data...)
+}
+
+func TestRenderer(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Rotation Rotation
+	}{
+		{"ssd1306", NoRotation},
+		{"ssd1322", Rotate90},
+		{"st7789", Rotate270},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(it *testing.T) {
+			c := conntest.NewSPIRecorder()
+			d, err := Open(test.Name, c, &Config{Rotation: test.Rotation})
+			if err != nil {
+				it.Fatal(err)
+			}
+			r := NewRenderer(d, nil)
+			defer r.Close()
+
+			if r.Bounds() != d.Bounds() {
+				it.Fatalf("expected bounds %s, got %s", d.Bounds(), r.Bounds())
+			}
+
+			// Draw rows concurrently, presenting after each row.
+			var (
+				b  = r.Bounds()
+				wg sync.WaitGroup
+			)
+			for y := b.Min.Y; y < b.Min.Y+8; y++ {
+				wg.Add(1)
+				go func(y int) {
+					defer wg.Done()
+					for x := b.Min.X; x < b.Max.X; x += 2 {
+						r.Set(x, y, color.White)
+					}
+					if err := r.Present(); err != nil {
+						it.Error(err)
+					}
+				}(y)
+			}
+			wg.Wait()
+			if err = r.Present(); err != nil {
+				it.Fatal(err)
+			}
+
+			white := d.ColorModel().Convert(color.White)
+			if err = r.Do(func(d Display) error {
+				for y := b.Min.Y; y < b.Min.Y+8; y++ {
+					for x := b.Min.X; x < b.Max.X; x++ {
+						want := color.Color(color.Black)
+						if x%2 == 0 {
+							want = white
+						}
+						if !sameColor(d.At(x, y), d.ColorModel().Convert(want)) {
+							return errors.New("unexpected pixel at " + image.Pt(x, y).String())
+						}
+					}
+				}
+				return nil
+			}); err != nil {
+				it.Fatal(err)
+			}
+			if n := c.DataLen(); n == 0 {
+				it.Error("expected frames to be sent")
+			}
+		})
+	}
+}
+
+func sameColor(a, b color.Color) bool {
+	r0, g0, b0, a0 := a.RGBA()
+	r1, g1, b1, a1 := b.RGBA()
+	return r0 == r1 && g0 == g1 && b0 == b1 && a0 == a1
+}
+
+func TestRendererDraw(t *testing.T) {
+	d, err := Open("ssd1306", conntest.NewRecorder(), &Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := NewRenderer(d, nil)
+	defer r.Close()
+
+	r.Draw(func(i draw.Image) {
+		draw.Draw(i, image.Rect(0, 0, 16, 16), image.White, image.Point{}, draw.Src)
+	})
+	if !sameColor(r.At(15, 15), color.White) || !sameColor(r.At(16, 16), color.Black) {
+		t.Error("expected square to be drawn in the back buffer")
+	}
+	if !sameColor(d.At(0, 0), color.Black) {
+		t.Error("expected display to be unchanged before present")
+	}
+
+	r.Fill(color.White)
+	if err = r.Present(); err != nil {
+		t.Fatal(err)
+	}
+	r.Clear()
+	if err = r.Do(func(d Display) error {
+		if !sameColor(d.At(100, 50), color.White) {
+			return errors.New("expected presented frame to be drawn")
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRendererDropFrames(t *testing.T) {
+	c := &slowConn{Recorder: conntest.NewRecorder()}
+	d, err := Open("ssd1306", c, &Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.delay = 20 * time.Millisecond
+	r := NewRenderer(d, nil)
+	defer r.Close()
+
+	const presented = 10
+	for i := 0; i < presented; i++ {
+		r.Set(i, 0, color.White)
+		if err = r.Present(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err = r.Do(func(d Display) error {
+		if !sameColor(d.At(presented-1, 0), color.White) {
+			return errors.New("expected the last frame to be drawn")
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	frames, dropped := r.Stats()
+	if dropped == 0 {
+		t.Errorf("expected frames to be dropped, drew %d frames", frames)
+	}
+	if frames+dropped != presented {
+		t.Errorf("expected %d drawn and dropped frames, got %d drawn and %d dropped", presented, frames, dropped)
+	}
+}
+
+func TestRendererFrameRate(t *testing.T) {
+	d, err := Open("ssd1306", conntest.NewRecorder(), &Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := NewRenderer(d, &RendererConfig{FrameRate: 20})
+	defer r.Close()
+
+	nop := func(Display) error { return nil }
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if err = r.Present(); err != nil {
+			t.Fatal(err)
+		}
+		if err = r.Do(nop); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 100*time.Millisecond {
+		t.Errorf("expected 3 frames at 20 fps to take at least 100ms, took %s", elapsed)
+	}
+	if frames, _ := r.Stats(); frames != 3 {
+		t.Errorf("expected 3 frames, got %d", frames)
+	}
+}
+
+func TestRendererPresentWhileWaiting(t *testing.T) {
+	d, err := Open("ssd1306", conntest.NewRecorder(), &Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := NewRenderer(d, &RendererConfig{FrameRate: 10})
+	defer r.Close()
+
+	nop := func(Display) error { return nil }
+	if err = r.Present(); err != nil {
+		t.Fatal(err)
+	}
+	if err = r.Do(nop); err != nil {
+		t.Fatal(err)
+	}
+
+	// The second frame waits for the frame interval, the third replaces it while waiting.
+	if err = r.Present(); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(20 * time.Millisecond)
+	if err = r.Present(); err != nil {
+		t.Fatal(err)
+	}
+	if err = r.Do(nop); err != nil {
+		t.Fatal(err)
+	}
+	if frames, dropped := r.Stats(); frames != 2 || dropped != 1 {
+		t.Errorf("expected 2 frames and 1 dropped frame, got %d frames and %d dropped", frames, dropped)
+	}
+}
+
+func TestRendererClose(t *testing.T) {
+	c := &slowConn{Recorder: conntest.NewRecorder()}
+	d, err := Open("ssd1306", c, &Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var refreshErr error
+	r := NewRenderer(d, &RendererConfig{OnError: func(err error) { refreshErr = err }})
+
+	// Close abandons the frame in progress.
+	c.delay = 50 * time.Millisecond
+	if err = r.Present(); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(10 * time.Millisecond)
+	start := time.Now()
+	if err = r.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("expected close to abandon the refresh, took %s", elapsed)
+	}
+	if refreshErr != nil {
+		t.Errorf("expected no refresh error for an abandoned frame, got %v", refreshErr)
+	}
+	if calls := c.Filter(conntest.OpClose); len(calls) != 1 {
+		t.Errorf("expected the display to be closed, got %d close calls", len(calls))
+	}
+
+	if err = r.Present(); !errors.Is(err, ErrRendererClosed) {
+		t.Errorf("expected %v, got %v", ErrRendererClosed, err)
+	}
+	if err = r.Do(func(Display) error { return nil }); !errors.Is(err, ErrRendererClosed) {
+		t.Errorf("expected %v, got %v", ErrRendererClosed, err)
+	}
+	if err = r.Close(); !errors.Is(err, ErrRendererClosed) {
+		t.Errorf("expected %v, got %v", ErrRendererClosed, err)
+	}
+}
+
+func TestRendererError(t *testing.T) {
+	c := conntest.NewRecorder()
+	d, err := Open("ssd1306", c, &Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.Err = errors.New("bus error")
+	r := NewRenderer(d, nil)
+	defer r.Close()
+
+	if err = r.Present(); err != nil {
+		t.Fatal(err)
+	}
+	if err = r.Do(func(Display) error { return nil }); err != nil {
+		t.Fatal(err)
+	}
+	if err = r.Present(); !errors.Is(err, c.Err) {
+		t.Errorf("expected %v, got %v", c.Err, err)
+	}
+}
@@ -0,0 +1,273 @@
+package rfb
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"io"
+	"net"
+	"sync"
+	"time"
+)
+
+// Client is a minimal VNC client that keeps a copy of the remote frame buffer. It supports the raw and
+// DesktopSize encodings, without authentication.
+type Client struct {
+	// Name of the desktop.
+	Name string
+
+	c      net.Conn
+	r      *bufio.Reader
+	mu     sync.Mutex // protects writes
+	format PixelFormat
+	frame  *image.RGBA
+}
+
+// Dial connects to a VNC server on the TCP network address addr.
+func Dial(addr string) (*Client, error) {
+	c, err := net.Dial("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	client, err := NewClient(c)
+	if err != nil {
+		_ = c.Close()
+		return nil, err
+	}
+	return client, nil
+}
+
+// NewClient performs the handshake on an established connection.
+func NewClient(c net.Conn) (*Client, error) {
+	client := &Client{
+		c: c,
+		r: bufio.NewReader(c),
+	}
+	if err := client.handshake(); err != nil {
+		return nil, err
+	}
+	return client, nil
+}
+
+func (c *Client) handshake() (err error) {
+	b := make([]byte, 12)
+	if _, err = io.ReadFull(c.r, b); err != nil {
+		return
+	}
+	var version int
+	if version, err = parseVersion(b); err != nil {
+		return
+	}
+	if _, err = io.WriteString(c.c, versionString(version)); err != nil {
+		return
+	}
+
+	if version == version33 {
+		var security uint32
+		if err = binary.Read(c.r, binary.BigEndian, &security); err != nil {
+			return
+		}
+		if security != securityNone {
+			return fmt.Errorf("%w %d", ErrSecurity, security)
+		}
+	} else {
+		var n byte
+		if n, err = c.r.ReadByte(); err != nil {
+			return
+		}
+		if n == securityInvalid {
+			return ErrSecurity
+		}
+		if b, err = c.read(int(n)); err != nil {
+			return
+		}
+		var supported bool
+		for _, security := range b {
+			supported = supported || security == securityNone
+		}
+		if !supported {
+			return fmt.Errorf("%w: %v", ErrSecurity, b)
+		}
+		if _, err = c.c.Write([]byte{securityNone}); err != nil {
+			return
+		}
+		if version == version38 {
+			var result uint32
+			if err = binary.Read(c.r, binary.BigEndian, &result); err != nil {
+				return
+			}
+			if result != 0 {
+				return fmt.Errorf("%w: security handshake failed", ErrSecurity)
+			}
+		}
+	}
+
+	// ClientInit, request a shared session.
+	if _, err = c.c.Write([]byte{1}); err != nil {
+		return
+	}
+	if b, err = c.read(24); err != nil {
+		return
+	}
+	var (
+		width  = int(binary.BigEndian.Uint16(b[0:]))
+		height = int(binary.BigEndian.Uint16(b[2:]))
+		n      = int(binary.BigEndian.Uint32(b[20:]))
+	)
+	c.format = unmarshalPixelFormat(b[4:20])
+	c.frame = image.NewRGBA(image.Rect(0, 0, width, height))
+	if b, err = c.read(n); err != nil {
+		return
+	}
+	c.Name = string(b)
+	return
+}
+
+func (c *Client) read(n int) ([]byte, error) {
+	b := make([]byte, n)
+	_, err := io.ReadFull(c.r, b)
+	return b, err
+}
+
+func (c *Client) write(b []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, err := c.c.Write(b)
+	return err
+}
+
+// SetDeadline sets the read and write deadlines of the connection.
+func (c *Client) SetDeadline(t time.Time) error {
+	return c.c.SetDeadline(t)
+}
+
+// Close the connection.
+func (c *Client) Close() error {
+	return c.c.Close()
+}
+
+// PixelFormat returns the pixel format used by the server.
+func (c *Client) PixelFormat() PixelFormat {
+	return c.format
+}
+
+// Image returns the copy of the remote frame buffer, it is updated by ReadUpdate.
+func (c *Client) Image() *image.RGBA {
+	return c.frame
+}
+
+// SetPixelFormat requests the server to use another pixel format.
+func (c *Client) SetPixelFormat(format PixelFormat) error {
+	if !format.valid() {
+		return ErrPixelFormat
+	}
+	if err := c.write(append([]byte{clientSetPixelFormat, 0, 0, 0}, format.marshal()...)); err != nil {
+		return err
+	}
+	c.format = format
+	return nil
+}
+
+// SetEncodings sets the encodings supported by the client, in order of preference.
+func (c *Client) SetEncodings(encodings ...int32) error {
+	b := []byte{clientSetEncodings, 0}
+	b = binary.BigEndian.AppendUint16(b, uint16(len(encodings)))
+	for _, encoding := range encodings {
+		b = binary.BigEndian.AppendUint32(b, uint32(encoding))
+	}
+	return c.write(b)
+}
+
+// RequestUpdate requests an update of the frame buffer inside r. An incremental update is only sent when
+// the frame buffer has changed.
+func (c *Client) RequestUpdate(incremental bool, r image.Rectangle) error {
+	b := []byte{clientFramebufferUpdateRequest, boolByte(incremental)}
+	w := toRect(r)
+	b = binary.BigEndian.AppendUint16(b, w.X)
+	b = binary.BigEndian.AppendUint16(b, w.Y)
+	b = binary.BigEndian.AppendUint16(b, w.W)
+	b = binary.BigEndian.AppendUint16(b, w.H)
+	return c.write(b)
+}
+
+// KeyEvent sends a key press or release.
+func (c *Client) KeyEvent(event KeyEvent) error {
+	b := []byte{clientKeyEvent, boolByte(event.Down), 0, 0}
+	return c.write(binary.BigEndian.AppendUint32(b, event.Key))
+}
+
+// PointerEvent sends a pointer event.
+func (c *Client) PointerEvent(event PointerEvent) error {
+	b := []byte{clientPointerEvent, event.Buttons}
+	b = binary.BigEndian.AppendUint16(b, uint16(event.X))
+	b = binary.BigEndian.AppendUint16(b, uint16(event.Y))
+	return c.write(b)
+}
+
+// ReadUpdate reads server messages until a frame buffer update is received, it returns the updated
+// rectangles. Bell and cut text messages are ignored.
+func (c *Client) ReadUpdate() (rects []image.Rectangle, err error) {
+	for {
+		var kind byte
+		if kind, err = c.r.ReadByte(); err != nil {
+			return
+		}
+		switch kind {
+		case serverFramebufferUpdate:
+			return c.readUpdate()
+		case serverBell:
+		case serverCutText:
+			var b []byte
+			if b, err = c.read(7); err != nil {
+				return
+			}
+			if _, err = c.r.Discard(int(binary.BigEndian.Uint32(b[3:]))); err != nil {
+				return
+			}
+		default:
+			return nil, fmt.Errorf("%w %d", ErrMessage, kind)
+		}
+	}
+}
+
+func (c *Client) readUpdate() (rects []image.Rectangle, err error) {
+	var b []byte
+	if b, err = c.read(3); err != nil {
+		return
+	}
+	n := int(binary.BigEndian.Uint16(b[1:]))
+	for i := 0; i < n; i++ {
+		var (
+			header   rect
+			encoding int32
+		)
+		if err = binary.Read(c.r, binary.BigEndian, &header); err != nil {
+			return
+		}
+		if err = binary.Read(c.r, binary.BigEndian, &encoding); err != nil {
+			return
+		}
+
+		r := header.Rectangle()
+		switch encoding {
+		case EncodingRaw:
+			size := c.format.Size()
+			if b, err = c.read(r.Dx() * r.Dy() * size); err != nil {
+				return
+			}
+			for y := r.Min.Y; y < r.Max.Y; y++ {
+				for x := r.Min.X; x < r.Max.X; x++ {
+					c.frame.SetRGBA(x, y, c.format.Decode(b))
+					b = b[size:]
+				}
+			}
+		case EncodingDesktopSize:
+			c.frame = image.NewRGBA(image.Rect(0, 0, r.Dx(), r.Dy()))
+		default:
+			return nil, fmt.Errorf("%w %d", ErrEncoding, encoding)
+		}
+		rects = append(rects, r)
+	}
+	return
+}
@@ -0,0 +1,16 @@
+// Package rfb implements a minimal RFB (VNC) server and client.
+//
+// The [Server] wraps a display and exposes its content as a VNC framebuffer, so standard VNC clients can act
+// as a remote viewer. The frame buffer is captured on every refresh. After the first update only the
+// changed parts of the frame are sent, in tiles of 16×16 pixels.
+//
+// The server supports protocol versions 3.3, 3.7 and 3.8, without authentication, and the raw encoding.
+// Clients may request any true color pixel format, the default pixel format depends on the color model
+// of the display. If the client supports the DesktopSize pseudo encoding, the frame buffer is resized when
+// the display is rotated.
+//
+// Keyboard and pointer events are passed to the event handlers of the server, unless the server is read
+// only.
+//
+// The [Client] is a simple client for testing, it keeps a copy of the remote frame buffer.
+package rfb
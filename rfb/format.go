@@ -0,0 +1,161 @@
+package rfb
+
+import (
+	"encoding/binary"
+	"image/color"
+
+	"github.com/BeatGlow/display/pixel"
+)
+
+// PixelFormat describes how pixel values are sent over the wire.
+type PixelFormat struct {
+	BitsPerPixel uint8
+	Depth        uint8
+	BigEndian    bool
+	TrueColor    bool
+	RedMax       uint16
+	GreenMax     uint16
+	BlueMax      uint16
+	RedShift     uint8
+	GreenShift   uint8
+	BlueShift    uint8
+}
+
+// Common pixel formats.
+var (
+	// PixelFormat32 is a 24-bit color format with 32 bits per pixel.
+	PixelFormat32 = PixelFormat{32, 24, false, true, 255, 255, 255, 16, 8, 0}
+
+	// PixelFormatRGB565 is a 16-bit 5-6-5 RGB format.
+	PixelFormatRGB565 = PixelFormat{16, 16, false, true, 31, 63, 31, 11, 5, 0}
+
+	// PixelFormatBGR233 is an 8-bit 2-3-3 BGR format.
+	PixelFormatBGR233 = PixelFormat{8, 8, false, true, 7, 7, 3, 0, 3, 6}
+)
+
+// PixelFormatFor returns the pixel format that best matches a color model.
+func PixelFormatFor(model color.Model) PixelFormat {
+	switch model {
+	case pixel.MonoModel:
+		return PixelFormatBGR233
+	case pixel.Gray2Model, pixel.Gray4Model, pixel.CRGB15Model, pixel.CRGB16Model, pixel.CBGR15Model, pixel.CBGR16Model:
+		return PixelFormatRGB565
+	default:
+		return PixelFormat32
+	}
+}
+
+// Size returns the number of bytes per pixel.
+func (pf PixelFormat) Size() int {
+	return int(pf.BitsPerPixel+7) / 8
+}
+
+// valid returns if the pixel format can be used by the server.
+func (pf PixelFormat) valid() bool {
+	switch pf.BitsPerPixel {
+	case 8, 16, 32:
+		return pf.TrueColor
+	default:
+		return false
+	}
+}
+
+// Encode appends the pixel value of c to b.
+func (pf PixelFormat) Encode(b []byte, c color.RGBA) []byte {
+	v := scale(c.R, pf.RedMax)<<pf.RedShift |
+		scale(c.G, pf.GreenMax)<<pf.GreenShift |
+		scale(c.B, pf.BlueMax)<<pf.BlueShift
+	switch pf.BitsPerPixel {
+	case 8:
+		return append(b, byte(v))
+	case 16:
+		if pf.BigEndian {
+			return binary.BigEndian.AppendUint16(b, uint16(v))
+		}
+		return binary.LittleEndian.AppendUint16(b, uint16(v))
+	default:
+		if pf.BigEndian {
+			return binary.BigEndian.AppendUint32(b, v)
+		}
+		return binary.LittleEndian.AppendUint32(b, v)
+	}
+}
+
+// Decode a pixel value.
+func (pf PixelFormat) Decode(b []byte) color.RGBA {
+	var v uint32
+	switch pf.BitsPerPixel {
+	case 8:
+		v = uint32(b[0])
+	case 16:
+		if pf.BigEndian {
+			v = uint32(binary.BigEndian.Uint16(b))
+		} else {
+			v = uint32(binary.LittleEndian.Uint16(b))
+		}
+	default:
+		if pf.BigEndian {
+			v = binary.BigEndian.Uint32(b)
+		} else {
+			v = binary.LittleEndian.Uint32(b)
+		}
+	}
+	return color.RGBA{
+		R: unscale(v>>pf.RedShift, pf.RedMax),
+		G: unscale(v>>pf.GreenShift, pf.GreenMax),
+		B: unscale(v>>pf.BlueShift, pf.BlueMax),
+		A: 0xFF,
+	}
+}
+
+// scale an 8-bit component to the range 0-max.
+func scale(v uint8, max uint16) uint32 {
+	return (uint32(v)*uint32(max) + 127) / 255
+}
+
+// unscale a component in the range 0-max to 8 bits.
+func unscale(v uint32, max uint16) uint8 {
+	if max == 0 {
+		return 0
+	}
+	return uint8(((v&uint32(max))*255 + uint32(max)/2) / uint32(max))
+}
+
+// marshal the pixel format to its 16 byte wire representation.
+func (pf PixelFormat) marshal() []byte {
+	b := make([]byte, 16)
+	b[0] = pf.BitsPerPixel
+	b[1] = pf.Depth
+	b[2] = boolByte(pf.BigEndian)
+	b[3] = boolByte(pf.TrueColor)
+	binary.BigEndian.PutUint16(b[4:], pf.RedMax)
+	binary.BigEndian.PutUint16(b[6:], pf.GreenMax)
+	binary.BigEndian.PutUint16(b[8:], pf.BlueMax)
+	b[10] = pf.RedShift
+	b[11] = pf.GreenShift
+	b[12] = pf.BlueShift
+	return b
+}
+
+// unmarshalPixelFormat parses the 16 byte wire representation of a pixel format.
+func unmarshalPixelFormat(b []byte) PixelFormat {
+	return PixelFormat{
+		BitsPerPixel: b[0],
+		Depth:        b[1],
+		BigEndian:    b[2] != 0,
+		TrueColor:    b[3] != 0,
+		RedMax:       binary.BigEndian.Uint16(b[4:]),
+		GreenMax:     binary.BigEndian.Uint16(b[6:]),
+		BlueMax:      binary.BigEndian.Uint16(b[8:]),
+		RedShift:     b[10],
+		GreenShift:   b[11],
+		BlueShift:    b[12],
+	}
+}
+
+func boolByte(v bool) byte {
+	if v {
+		return 1
+	}
+	return 0
+}
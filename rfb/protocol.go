@@ -0,0 +1,99 @@
+package rfb
+
+import (
+	"errors"
+	"fmt"
+	"image"
+)
+
+// Errors.
+var (
+	ErrVersion     = errors.New("rfb: unsupported protocol version")
+	ErrSecurity    = errors.New("rfb: unsupported security type")
+	ErrPixelFormat = errors.New("rfb: unsupported pixel format")
+	ErrMessage     = errors.New("rfb: unsupported message")
+	ErrEncoding    = errors.New("rfb: unsupported encoding")
+)
+
+// Protocol versions.
+const (
+	version33 = 3
+	version37 = 7
+	version38 = 8
+)
+
+// Security types.
+const (
+	securityInvalid = 0
+	securityNone    = 1
+)
+
+// Client to server messages.
+const (
+	clientSetPixelFormat           = 0
+	clientSetEncodings             = 2
+	clientFramebufferUpdateRequest = 3
+	clientKeyEvent                 = 4
+	clientPointerEvent             = 5
+	clientCutText                  = 6
+)
+
+// Server to client messages.
+const (
+	serverFramebufferUpdate   = 0
+	serverSetColourMapEntries = 1
+	serverBell                = 2
+	serverCutText             = 3
+)
+
+// Encodings.
+const (
+	EncodingRaw         int32 = 0
+	EncodingDesktopSize int32 = -223
+)
+
+// KeyEvent is a key press or release, the key is a X Window System keysym.
+type KeyEvent struct {
+	Down bool
+	Key  uint32
+}
+
+// PointerEvent is a pointer movement or button press, Buttons is a bit mask of pressed buttons.
+type PointerEvent struct {
+	Buttons uint8
+	X, Y    int
+}
+
+func versionString(minor int) string {
+	return fmt.Sprintf("RFB 003.%03d\n", minor)
+}
+
+// parseVersion parses a protocol version message and returns the minor version.
+func parseVersion(b []byte) (minor int, err error) {
+	var major int
+	if _, err = fmt.Sscanf(string(b), "RFB %03d.%03d\n", &major, &minor); err != nil || major != 3 {
+		return 0, fmt.Errorf("%w %q", ErrVersion, b)
+	}
+	switch {
+	case minor >= version38:
+		return version38, nil
+	case minor == version37:
+		return version37, nil
+	default:
+		// Unknown minor versions must be handled as 3.3.
+		return version33, nil
+	}
+}
+
+// rect is a rectangle in the wire format.
+type rect struct {
+	X, Y, W, H uint16
+}
+
+func toRect(r image.Rectangle) rect {
+	return rect{uint16(r.Min.X), uint16(r.Min.Y), uint16(r.Dx()), uint16(r.Dy())}
+}
+
+func (r rect) Rectangle() image.Rectangle {
+	return image.Rect(int(r.X), int(r.Y), int(r.X)+int(r.W), int(r.Y)+int(r.H))
+}
@@ -0,0 +1,291 @@
+package rfb_test
+
+import (
+	"bufio"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/BeatGlow/display"
+	"github.com/BeatGlow/display/pixel"
+	"github.com/BeatGlow/display/rfb"
+	"github.com/BeatGlow/display/snapshot"
+)
+
+func newServer(t *testing.T, model color.Model, width, height int) (*rfb.Server, string) {
+	t.Helper()
+
+	d, err := snapshot.NewFile("", &snapshot.Config{
+		Config: display.Config{Width: width, Height: height},
+		Model:  model,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := rfb.New(d)
+	go func() { _ = s.Serve(l) }()
+	t.Cleanup(func() { _ = s.Close() })
+	return s, l.Addr().String()
+}
+
+func dial(t *testing.T, addr string) *rfb.Client {
+	t.Helper()
+
+	c, err := rfb.Dial(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = c.Close() })
+	_ = c.SetDeadline(time.Now().Add(5 * time.Second))
+	return c
+}
+
+// compare the client frame buffer with the display.
+func compare(t *testing.T, c *rfb.Client, d display.Display) {
+	t.Helper()
+
+	var (
+		i = c.Image()
+		b = d.Bounds()
+	)
+	if i.Rect.Size() != b.Size() {
+		t.Fatalf("expected %s frame buffer, got %s", b.Size(), i.Rect.Size())
+	}
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			r, g, b, _ := d.At(x, y).RGBA()
+			want := color.RGBA{R: uint8(r >> 8), G: uint8(g >> 8), B: uint8(b >> 8), A: 0xFF}
+			if got := i.RGBAAt(x, y); !similar(got, want) {
+				t.Fatalf("pixel %d,%d: expected %v, got %v", x, y, want, got)
+			}
+		}
+	}
+}
+
+// similar compares colors, allowing for the precision loss of the pixel format.
+func similar(a, b color.RGBA) bool {
+	diff := func(a, b uint8) bool { return a-b < 9 || b-a < 9 }
+	return diff(a.R, b.R) && diff(a.G, b.G) && diff(a.B, b.B)
+}
+
+func TestServer(t *testing.T) {
+	s, addr := newServer(t, pixel.CRGB16Model, 64, 32)
+	s.Name = "kiosk"
+	for y := 0; y < 32; y++ {
+		for x := 0; x < 64; x++ {
+			s.Set(x, y, color.RGBA{R: uint8(x * 4), G: uint8(y * 8), B: 0x80, A: 0xFF})
+		}
+	}
+	if err := s.Refresh(); err != nil {
+		t.Fatal(err)
+	}
+
+	c := dial(t, addr)
+	if c.Name != "kiosk" {
+		t.Errorf("expected name %q, got %q", "kiosk", c.Name)
+	}
+	if v := c.PixelFormat(); v != rfb.PixelFormatRGB565 {
+		t.Errorf("expected RGB565 pixel format, got %+v", v)
+	}
+
+	// Full update.
+	if err := c.RequestUpdate(false, c.Image().Rect); err != nil {
+		t.Fatal(err)
+	}
+	rects, err := c.ReadUpdate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rects) != 1 || rects[0] != image.Rect(0, 0, 64, 32) {
+		t.Errorf("expected full update, got %v", rects)
+	}
+	compare(t, c, s)
+
+	// Incremental update, only the changed tile is sent.
+	s.Set(40, 20, color.White)
+	if err = s.Refresh(); err != nil {
+		t.Fatal(err)
+	}
+	if err = c.RequestUpdate(true, c.Image().Rect); err != nil {
+		t.Fatal(err)
+	}
+	if rects, err = c.ReadUpdate(); err != nil {
+		t.Fatal(err)
+	}
+	if len(rects) != 1 || rects[0] != image.Rect(32, 16, 48, 32) {
+		t.Errorf("expected update of changed tile, got %v", rects)
+	}
+	compare(t, c, s)
+
+	// An incremental update waits for the next frame.
+	if err = c.RequestUpdate(true, c.Image().Rect); err != nil {
+		t.Fatal(err)
+	}
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		s.Set(0, 0, color.White)
+		s.Set(20, 0, color.White)
+		_ = s.Refresh()
+	}()
+	if rects, err = c.ReadUpdate(); err != nil {
+		t.Fatal(err)
+	}
+	if len(rects) != 1 || rects[0] != image.Rect(0, 0, 32, 16) {
+		t.Errorf("expected update of changed tiles, got %v", rects)
+	}
+	compare(t, c, s)
+}
+
+func TestPixelFormats(t *testing.T) {
+	for _, test := range []struct {
+		Name   string
+		Model  color.Model
+		Format rfb.PixelFormat
+	}{
+		{"mono", pixel.MonoModel, rfb.PixelFormatBGR233},
+		{"gray4", pixel.Gray4Model, rfb.PixelFormatRGB565},
+		{"gray4 32 bits", pixel.Gray4Model, rfb.PixelFormat32},
+		{"rgb big endian", pixel.CRGB16Model, rfb.PixelFormat{32, 24, true, true, 255, 255, 255, 0, 8, 16}},
+	} {
+		t.Run(test.Name, func(it *testing.T) {
+			s, addr := newServer(it, test.Model, 20, 10)
+			for y := 0; y < 10; y++ {
+				for x := 0; x < 20; x++ {
+					s.Set(x, y, color.Gray{Y: uint8(x*13 + y*3)})
+				}
+			}
+			s.Set(3, 3, color.RGBA{R: 0xFF, A: 0xFF})
+			if err := s.Refresh(); err != nil {
+				it.Fatal(err)
+			}
+
+			c := dial(it, addr)
+			if c.PixelFormat() != rfb.PixelFormatFor(test.Model) {
+				it.Errorf("expected default pixel format for model, got %+v", c.PixelFormat())
+			}
+			if err := c.SetPixelFormat(test.Format); err != nil {
+				it.Fatal(err)
+			}
+			if err := c.RequestUpdate(false, c.Image().Rect); err != nil {
+				it.Fatal(err)
+			}
+			if _, err := c.ReadUpdate(); err != nil {
+				it.Fatal(err)
+			}
+			compare(it, c, s)
+		})
+	}
+}
+
+func TestDesktopSize(t *testing.T) {
+	s, addr := newServer(t, pixel.MonoModel, 32, 16)
+	c := dial(t, addr)
+	if err := c.SetEncodings(rfb.EncodingRaw, rfb.EncodingDesktopSize); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.SetRotation(display.Rotate90); err != nil {
+		t.Fatal(err)
+	}
+	s.Set(0, 20, pixel.On)
+	if err := s.Refresh(); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.RequestUpdate(true, c.Image().Rect); err != nil {
+		t.Fatal(err)
+	}
+	rects, err := c.ReadUpdate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rects) != 2 || rects[0] != image.Rect(0, 0, 16, 32) {
+		t.Errorf("expected desktop size and full update, got %v", rects)
+	}
+	compare(t, c, s)
+}
+
+func TestEvents(t *testing.T) {
+	var (
+		s, addr  = newServer(t, pixel.MonoModel, 32, 16)
+		keys     = make(chan rfb.KeyEvent, 1)
+		pointers = make(chan rfb.PointerEvent, 2)
+	)
+	s.OnKey = func(e rfb.KeyEvent) { keys <- e }
+	s.OnPointer = func(e rfb.PointerEvent) { pointers <- e }
+
+	c := dial(t, addr)
+	if err := c.KeyEvent(rfb.KeyEvent{Down: true, Key: 0xFF0D}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.PointerEvent(rfb.PointerEvent{Buttons: 1, X: 5, Y: 7}); err != nil {
+		t.Fatal(err)
+	}
+	if e := <-keys; !e.Down || e.Key != 0xFF0D {
+		t.Errorf("unexpected key event %+v", e)
+	}
+	if e := <-pointers; e.Buttons != 1 || e.X != 5 || e.Y != 7 {
+		t.Errorf("unexpected pointer event %+v", e)
+	}
+
+	// Read only servers ignore events, the update request shows that the event was processed.
+	s.ReadOnly = true
+	if err := c.PointerEvent(rfb.PointerEvent{X: 1, Y: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.RequestUpdate(false, image.Rect(0, 0, 1, 1)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.ReadUpdate(); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case e := <-pointers:
+		t.Errorf("expected event to be ignored, got %+v", e)
+	default:
+	}
+}
+
+func TestVersion33(t *testing.T) {
+	_, addr := newServer(t, pixel.MonoModel, 32, 16)
+
+	c, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = c.Close() }()
+	_ = c.SetDeadline(time.Now().Add(5 * time.Second))
+
+	r := bufio.NewReader(c)
+	b := make([]byte, 12)
+	if _, err = io.ReadFull(r, b); err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != "RFB 003.008\n" {
+		t.Errorf("unexpected version %q", b)
+	}
+	if _, err = io.WriteString(c, "RFB 003.003\n"); err != nil {
+		t.Fatal(err)
+	}
+	var security uint32
+	if err = binary.Read(r, binary.BigEndian, &security); err != nil || security != 1 {
+		t.Fatalf("expected security type none, got %d (%v)", security, err)
+	}
+	if _, err = c.Write([]byte{1}); err != nil {
+		t.Fatal(err)
+	}
+	var size [2]uint16
+	if err = binary.Read(r, binary.BigEndian, &size); err != nil {
+		t.Fatal(err)
+	}
+	if size != [2]uint16{32, 16} {
+		t.Errorf("expected 32x16 frame buffer, got %v", size)
+	}
+}
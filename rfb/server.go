@@ -0,0 +1,554 @@
+package rfb
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"io"
+	"net"
+	"sync"
+
+	"github.com/BeatGlow/display"
+)
+
+// TileSize is the size of the tiles that are compared to find the changed parts of a frame.
+const TileSize = 16
+
+// Server is a display that serves its content as a VNC framebuffer.
+//
+// The server does not support authentication, it should only be exposed on trusted networks.
+type Server struct {
+	display.Display
+
+	// Name of the desktop, defaults to the display description.
+	Name string
+
+	// ReadOnly ignores all keyboard and pointer events.
+	ReadOnly bool
+
+	// OnKey is called for keyboard events.
+	OnKey func(KeyEvent)
+
+	// OnPointer is called for pointer events.
+	OnPointer func(PointerEvent)
+
+	mu        sync.Mutex
+	frame     *image.RGBA
+	changed   chan struct{} // closed when a new frame is captured
+	done      chan struct{} // closed when the server is closed
+	closed    bool
+	listeners map[net.Listener]struct{}
+	conns     map[net.Conn]struct{}
+}
+
+// New returns a display that serves the content of d as a VNC framebuffer.
+func New(d display.Display) *Server {
+	s := &Server{
+		Display:   d,
+		changed:   make(chan struct{}),
+		done:      make(chan struct{}),
+		listeners: make(map[net.Listener]struct{}),
+		conns:     make(map[net.Conn]struct{}),
+	}
+	s.capture()
+	return s
+}
+
+func (s *Server) String() string {
+	return "rfb " + s.Display.String()
+}
+
+// Refresh redraws the display and captures the frame.
+func (s *Server) Refresh() error {
+	err := s.Display.Refresh()
+	s.capture()
+	return err
+}
+
+// RefreshRect redraws part of the display, if supported by the display, and captures the frame.
+func (s *Server) RefreshRect(r image.Rectangle) error {
+	err := display.RefreshRect(s.Display, r)
+	s.capture()
+	return err
+}
+
+// capture the display content. Captures happen on the goroutine that draws to the display, so the
+// client sessions never access the display.
+func (s *Server) capture() {
+	var (
+		b     = s.Display.Bounds()
+		frame = image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	)
+	draw.Draw(frame, frame.Rect, s.Display, b.Min, draw.Src)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return
+	}
+	s.frame = frame
+	close(s.changed)
+	s.changed = make(chan struct{})
+}
+
+// current returns the last captured frame and a channel that is closed when the next frame is captured.
+func (s *Server) current() (*image.RGBA, <-chan struct{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.frame, s.changed
+}
+
+// ListenAndServe listens on the TCP network address addr and serves VNC clients.
+func (s *Server) ListenAndServe(addr string) error {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	return s.Serve(l)
+}
+
+// Serve VNC clients that connect to l, every client is served in its own goroutine.
+func (s *Server) Serve(l net.Listener) error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return net.ErrClosed
+	}
+	s.listeners[l] = struct{}{}
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		delete(s.listeners, l)
+		s.mu.Unlock()
+		_ = l.Close()
+	}()
+
+	for {
+		c, err := l.Accept()
+		if err != nil {
+			if s.isClosed() {
+				return net.ErrClosed
+			}
+			return err
+		}
+		go func() { _ = s.ServeConn(c) }()
+	}
+}
+
+// ServeConn serves a single VNC client, until the client disconnects or the server is closed.
+func (s *Server) ServeConn(c net.Conn) (err error) {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return c.Close()
+	}
+	s.conns[c] = struct{}{}
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		delete(s.conns, c)
+		s.mu.Unlock()
+		_ = c.Close()
+	}()
+
+	sess := &session{
+		s:     s,
+		c:     c,
+		r:     bufio.NewReader(c),
+		w:     bufio.NewWriter(c),
+		msgs:  make(chan any, 16),
+		done:  make(chan struct{}),
+		wrote: make(chan struct{}),
+	}
+	if err = sess.handshake(); err != nil {
+		return
+	}
+
+	go sess.writeLoop()
+	err = sess.readLoop()
+	close(sess.done)
+	<-sess.wrote
+	if errors.Is(err, io.EOF) || s.isClosed() {
+		err = nil
+	}
+	return
+}
+
+// Close all client connections and the display.
+func (s *Server) Close() error {
+	s.mu.Lock()
+	if !s.closed {
+		s.closed = true
+		close(s.done)
+		for l := range s.listeners {
+			_ = l.Close()
+		}
+		for c := range s.conns {
+			_ = c.Close()
+		}
+	}
+	s.mu.Unlock()
+	return s.Display.Close()
+}
+
+func (s *Server) isClosed() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.closed
+}
+
+// updateRequest is a framebuffer update request.
+type updateRequest struct {
+	incremental bool
+	rect        image.Rectangle
+}
+
+// session is a client connection. Client messages are read by readLoop and passed in order to
+// writeLoop, which sends the framebuffer updates.
+type session struct {
+	s     *Server
+	c     net.Conn
+	r     *bufio.Reader
+	w     *bufio.Writer
+	msgs  chan any
+	done  chan struct{} // closed when the reader stops
+	wrote chan struct{} // closed when the writer stops
+
+	// State owned by writeLoop.
+	format      PixelFormat
+	desktopSize bool
+	shadow      *image.RGBA // frame buffer of the client
+	pending     *updateRequest
+}
+
+func (sess *session) handshake() (err error) {
+	if _, err = io.WriteString(sess.c, versionString(version38)); err != nil {
+		return
+	}
+	b := make([]byte, 12)
+	if _, err = io.ReadFull(sess.r, b); err != nil {
+		return
+	}
+	var version int
+	if version, err = parseVersion(b); err != nil {
+		return
+	}
+
+	if version == version33 {
+		if err = binary.Write(sess.c, binary.BigEndian, uint32(securityNone)); err != nil {
+			return
+		}
+	} else {
+		if _, err = sess.c.Write([]byte{1, securityNone}); err != nil {
+			return
+		}
+		var security byte
+		if security, err = sess.r.ReadByte(); err != nil {
+			return
+		}
+		if security != securityNone {
+			return fmt.Errorf("%w %d", ErrSecurity, security)
+		}
+		if version == version38 {
+			if err = binary.Write(sess.c, binary.BigEndian, uint32(0)); err != nil {
+				return
+			}
+		}
+	}
+
+	// ClientInit, the shared flag is ignored: all clients share the display.
+	if _, err = sess.r.ReadByte(); err != nil {
+		return
+	}
+
+	frame, _ := sess.s.current()
+	sess.format = PixelFormatFor(sess.s.Display.ColorModel())
+	sess.shadow = image.NewRGBA(frame.Rect)
+	name := sess.s.Name
+	if name == "" {
+		name = sess.s.Display.String()
+	}
+
+	init := make([]byte, 0, 24+len(name))
+	init = binary.BigEndian.AppendUint16(init, uint16(frame.Rect.Dx()))
+	init = binary.BigEndian.AppendUint16(init, uint16(frame.Rect.Dy()))
+	init = append(init, sess.format.marshal()...)
+	init = binary.BigEndian.AppendUint32(init, uint32(len(name)))
+	init = append(init, name...)
+	_, err = sess.c.Write(init)
+	return
+}
+
+func (sess *session) readLoop() (err error) {
+	for {
+		var kind byte
+		if kind, err = sess.r.ReadByte(); err != nil {
+			return
+		}
+		var msg any
+		if msg, err = sess.readMessage(kind); err != nil {
+			return
+		}
+		if msg == nil {
+			continue
+		}
+		select {
+		case sess.msgs <- msg:
+		case <-sess.wrote:
+			return io.EOF
+		case <-sess.s.done:
+			return nil
+		}
+	}
+}
+
+// readMessage reads a client message, input events are handled directly.
+func (sess *session) readMessage(kind byte) (msg any, err error) {
+	var b []byte
+	switch kind {
+	case clientSetPixelFormat:
+		if b, err = sess.read(19); err != nil {
+			return
+		}
+		format := unmarshalPixelFormat(b[3:])
+		if !format.valid() {
+			return nil, fmt.Errorf("%w: %+v", ErrPixelFormat, format)
+		}
+		return format, nil
+
+	case clientSetEncodings:
+		if b, err = sess.read(3); err != nil {
+			return
+		}
+		n := int(binary.BigEndian.Uint16(b[1:]))
+		if b, err = sess.read(n * 4); err != nil {
+			return
+		}
+		encodings := make([]int32, n)
+		for i := range encodings {
+			encodings[i] = int32(binary.BigEndian.Uint32(b[i*4:]))
+		}
+		return encodings, nil
+
+	case clientFramebufferUpdateRequest:
+		if b, err = sess.read(9); err != nil {
+			return
+		}
+		return updateRequest{
+			incremental: b[0] != 0,
+			rect: rect{
+				X: binary.BigEndian.Uint16(b[1:]),
+				Y: binary.BigEndian.Uint16(b[3:]),
+				W: binary.BigEndian.Uint16(b[5:]),
+				H: binary.BigEndian.Uint16(b[7:]),
+			}.Rectangle(),
+		}, nil
+
+	case clientKeyEvent:
+		if b, err = sess.read(7); err != nil {
+			return
+		}
+		if handler := sess.s.OnKey; handler != nil && !sess.s.ReadOnly {
+			handler(KeyEvent{Down: b[0] != 0, Key: binary.BigEndian.Uint32(b[3:])})
+		}
+		return nil, nil
+
+	case clientPointerEvent:
+		if b, err = sess.read(5); err != nil {
+			return
+		}
+		if handler := sess.s.OnPointer; handler != nil && !sess.s.ReadOnly {
+			handler(PointerEvent{
+				Buttons: b[0],
+				X:       int(binary.BigEndian.Uint16(b[1:])),
+				Y:       int(binary.BigEndian.Uint16(b[3:])),
+			})
+		}
+		return nil, nil
+
+	case clientCutText:
+		if b, err = sess.read(7); err != nil {
+			return
+		}
+		_, err = sess.r.Discard(int(binary.BigEndian.Uint32(b[3:])))
+		return nil, err
+
+	default:
+		return nil, fmt.Errorf("%w %d", ErrMessage, kind)
+	}
+}
+
+func (sess *session) read(n int) ([]byte, error) {
+	b := make([]byte, n)
+	_, err := io.ReadFull(sess.r, b)
+	return b, err
+}
+
+func (sess *session) writeLoop() {
+	defer close(sess.wrote)
+	for {
+		if sess.pending == nil {
+			select {
+			case msg := <-sess.msgs:
+				sess.handle(msg)
+			case <-sess.done:
+				return
+			case <-sess.s.done:
+				return
+			}
+			continue
+		}
+
+		frame, changed := sess.s.current()
+		if sent, err := sess.update(frame); err != nil {
+			_ = sess.c.Close()
+			return
+		} else if sent {
+			continue
+		}
+
+		// Nothing changed, wait for the next frame.
+		select {
+		case msg := <-sess.msgs:
+			sess.handle(msg)
+		case <-changed:
+		case <-sess.done:
+			return
+		case <-sess.s.done:
+			return
+		}
+	}
+}
+
+func (sess *session) handle(msg any) {
+	switch msg := msg.(type) {
+	case PixelFormat:
+		sess.format = msg
+	case []int32:
+		sess.desktopSize = false
+		for _, encoding := range msg {
+			if encoding == EncodingDesktopSize {
+				sess.desktopSize = true
+			}
+		}
+	case updateRequest:
+		if sess.pending != nil && !sess.pending.incremental {
+			// Keep the pending full update.
+			msg.incremental = false
+			msg.rect = msg.rect.Union(sess.pending.rect)
+		}
+		sess.pending = &msg
+	}
+}
+
+// update sends the pending update if anything changed, or if a full update was requested.
+func (sess *session) update(frame *image.RGBA) (sent bool, err error) {
+	var (
+		req     = *sess.pending
+		resized = sess.desktopSize && frame.Rect != sess.shadow.Rect
+		rects   []image.Rectangle
+	)
+	if resized {
+		sess.shadow = image.NewRGBA(frame.Rect)
+		req = updateRequest{rect: frame.Rect}
+	}
+
+	region := req.rect.Intersect(sess.shadow.Rect)
+	if req.incremental {
+		rects = damage(frame, sess.shadow, region)
+	} else if !region.Empty() {
+		rects = []image.Rectangle{region}
+	}
+	if len(rects) == 0 && !resized && req.incremental {
+		return false, nil
+	}
+
+	n := len(rects)
+	if resized {
+		n++
+	}
+	// Write errors are sticky, they are returned by Flush.
+	_, _ = sess.w.Write([]byte{serverFramebufferUpdate, 0})
+	_ = binary.Write(sess.w, binary.BigEndian, uint16(n))
+	if resized {
+		_ = binary.Write(sess.w, binary.BigEndian, toRect(frame.Rect))
+		_ = binary.Write(sess.w, binary.BigEndian, EncodingDesktopSize)
+	}
+
+	buf := make([]byte, 0, TileSize*TileSize*4)
+	for _, r := range rects {
+		_ = binary.Write(sess.w, binary.BigEndian, toRect(r))
+		_ = binary.Write(sess.w, binary.BigEndian, EncodingRaw)
+		for y := r.Min.Y; y < r.Max.Y; y++ {
+			buf = buf[:0]
+			for x := r.Min.X; x < r.Max.X; x++ {
+				c := pixelAt(frame, x, y)
+				sess.shadow.SetRGBA(x, y, c)
+				buf = sess.format.Encode(buf, c)
+			}
+			if _, err = sess.w.Write(buf); err != nil {
+				return
+			}
+		}
+	}
+	if err = sess.w.Flush(); err != nil {
+		return
+	}
+	sess.pending = nil
+	return true, nil
+}
+
+// damage returns the tiles inside region where the frame differs from the shadow frame buffer. Adjacent
+// tiles in a row are merged.
+func damage(frame, shadow *image.RGBA, region image.Rectangle) (rects []image.Rectangle) {
+	for ty := region.Min.Y - region.Min.Y%TileSize; ty < region.Max.Y; ty += TileSize {
+		var run image.Rectangle
+		for tx := region.Min.X - region.Min.X%TileSize; tx < region.Max.X; tx += TileSize {
+			tile := image.Rect(tx, ty, tx+TileSize, ty+TileSize).Intersect(region)
+			if changed(frame, shadow, tile) {
+				run = run.Union(tile)
+				continue
+			}
+			if !run.Empty() {
+				rects = append(rects, run)
+				run = image.Rectangle{}
+			}
+		}
+		if !run.Empty() {
+			rects = append(rects, run)
+		}
+	}
+	return
+}
+
+// changed returns if any pixel in r differs between the frame and the shadow frame buffer.
+func changed(frame, shadow *image.RGBA, r image.Rectangle) bool {
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			if pixelAt(frame, x, y) != shadow.RGBAAt(x, y) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// pixelAt returns the pixel at (x, y), pixels outside of the frame are black.
+func pixelAt(frame *image.RGBA, x, y int) color.RGBA {
+	if !(image.Point{X: x, Y: y}).In(frame.Rect) {
+		return color.RGBA{A: 0xFF}
+	}
+	return frame.RGBAAt(x, y)
+}
+
+// Interface checks
+var (
+	_ display.Display         = (*Server)(nil)
+	_ display.WindowRefresher = (*Server)(nil)
+)
@@ -2,6 +2,7 @@ package display
 
 import (
 	"fmt"
+	"image"
 
 	"github.com/BeatGlow/display/pixel"
 )
@@ -10,12 +11,26 @@ const (
 	sh1106DefaultWidth  = 128
 	sh1106DefaultHeight = 64
 	sh1106SetPageAddr   = 0xB0
+	sh1106ColumnOffset  = 2 // 128 pixels are centered in the 132 column RAM
 )
 
+func init() {
+	Register(Driver{
+		Name:   "sh1106",
+		Buses:  I2CBus | SPIBus,
+		Width:  sh1106DefaultWidth,
+		Height: sh1106DefaultHeight,
+		New:    SH1106,
+	})
+}
+
 type sh1106 struct {
 	monoDisplay
-	pageSize int
-	width    int
+	pageSize    int
+	width       int
+	multiplex   int // multiplexed rows
+	panelOffset int // display offset of the first panel row
+	offset      int // vertical display offset set with SetOffset
 }
 
 // SH1106 is a driver for the Sino Wealth SH1106 OLED display.
@@ -23,7 +38,8 @@ func SH1106(conn Conn, config *Config) (Display, error) {
 	d := &sh1106{
 		monoDisplay: monoDisplay{
 			baseDisplay: baseDisplay{
-				c: conn,
+				c:         conn,
+				colOffset: sh1106ColumnOffset,
 			},
 		},
 	}
@@ -75,6 +91,7 @@ func (d *sh1106) init(config *Config) (err error) {
 	default:
 		return fmt.Errorf("display: SH1106 unsupported size %dx%d", config.Width, config.Height)
 	}
+	d.multiplex, d.panelOffset = int(multiplexRatio&0x3F)+1, int(displayOffset)
 
 	// init base
 	if err = d.monoDisplay.init(config); err != nil {
@@ -104,6 +121,9 @@ func (d *sh1106) init(config *Config) (err error) {
 	if err = d.SetContrast(0x7F); err != nil {
 		return
 	}
+	if err = d.SetRotation(config.Rotation); err != nil {
+		return
+	}
 	if err = d.Refresh(); err != nil {
 		return
 	}
@@ -114,24 +134,91 @@ func (d *sh1106) init(config *Config) (err error) {
 	return
 }
 
-func (d *sh1106) Refresh() (err error) {
-	//const pageSize = 8
-	pix := d.Image.(*pixel.MonoVerticalLSBImage).Pix
-	for page := 0; page < d.pageSize; page++ {
+// Refresh redraws the whole display.
+func (d *sh1106) Refresh() error {
+	return d.RefreshRect(d.Bounds())
+}
+
+// RefreshRect redraws the pages and columns covering r.
+func (d *sh1106) RefreshRect(r image.Rectangle) (err error) {
+	if r = d.bufferRect(r); r.Empty() {
+		return
+	}
+
+	var (
+		pix    = d.Image.(*pixel.MonoVerticalLSBImage).Pix
+		column = d.colOffset + r.Min.X
+	)
+	for page := r.Min.Y >> 3; page < (r.Max.Y+7)>>3; page++ {
 		if err = d.command(
 			sh1106SetPageAddr|byte(page&0x7),
-			ssd1xxxSetLowColumn|0x2,
-			ssd1xxxSetHighColumn|0x0, //nolint:staticcheck
+			ssd1xxxSetLowColumn|byte(column&0xf),
+			ssd1xxxSetHighColumn|byte(column>>4),
 		); err != nil {
 			return
 		}
-		var (
-			off = page * d.width
-			end = off + d.width
-		)
-		if err := d.data(pix[off:end]...); err != nil {
-			return err
+		off := page * d.width
+		if err = d.data(pix[off+r.Min.X : off+r.Max.X]...); err != nil {
+			return
 		}
 	}
-	return nil
+	return
+}
+
+// SetInvert toggles inverted pixels.
+func (d *sh1106) SetInvert(invert bool) error {
+	if invert {
+		return d.command(ssd1xxxSetInvertDisplay)
+	}
+	return d.command(ssd1xxxSetNormalDisplay)
 }
+
+// SetRotation rotates the display like [monoDisplay.SetRotation].
+//
+// The display offset maps the first multiplexed row to a COM line, so with the COM scan direction reversed
+// panels with fewer rows than the multiplex ratio show the other end of the multiplexed rows; the display
+// offset moves the picture back into view.
+func (d *sh1106) SetRotation(rotation Rotation) error {
+	if err := d.monoDisplay.SetRotation(rotation); err != nil {
+		return err
+	}
+	return d.setDisplayOffset()
+}
+
+// setDisplayOffset sets the display offset for the rotation and the offset set with SetOffset. Raising the
+// display offset moves the picture down the panel, or up when rotated by 180°.
+func (d *sh1106) setDisplayOffset() error {
+	offset := d.panelOffset - d.offset
+	if d.rotation == Rotate180 {
+		offset = d.panelOffset + d.height - d.multiplex + d.offset
+	}
+	return d.command(ssd1xxxSetDisplayOffset, startLine(offset, 64))
+}
+
+// Scroll sets the display start line, the RAM has 64 rows.
+func (d *sh1106) Scroll(line int) error {
+	return d.command(ssd1xxxSetStartLine | startLine(line, 64))
+}
+
+// SetOffset sets the vertical display offset, the RAM has 64 rows. Horizontal offsets are not supported.
+func (d *sh1106) SetOffset(x, y int) error {
+	if x != 0 {
+		return errHorizontalOffset("SH1106")
+	}
+	d.offset = y
+	return d.setDisplayOffset()
+}
+
+// Info describes the SH1106 controller.
+func (d *sh1106) Info() Info {
+	return d.describe("SH1106", d)
+}
+
+// Interface checks
+var (
+	_ WindowRefresher = (*sh1106)(nil)
+	_ Inverter        = (*sh1106)(nil)
+	_ Scroller        = (*sh1106)(nil)
+	_ Describer       = (*sh1106)(nil)
+	_ Offsetter       = (*sh1106)(nil)
+)
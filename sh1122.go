@@ -2,6 +2,7 @@ package display
 
 import (
 	"fmt"
+	"image"
 	"log"
 
 	"github.com/BeatGlow/display/conn"
@@ -18,10 +19,21 @@ const (
 	sh1122SetVSEGMLevel        = 0xDC
 )
 
+func init() {
+	Register(Driver{
+		Name:   "sh1122",
+		Buses:  I2CBus | SPIBus,
+		Width:  sh1122DefaultWidth,
+		Height: sh1122DefaultHeight,
+		New:    SH1122,
+	})
+}
+
 type sh1122 struct {
 	gray4Display
 	pages  int
 	width  int
+	offset byte
 	halted bool
 }
 
@@ -93,6 +105,7 @@ func (d *sh1122) init(config *Config) (err error) {
 	default:
 		return fmt.Errorf("display: SH1122 unsupported size %dx%d", config.Width, config.Height)
 	}
+	d.offset = displayOffset
 
 	// init base
 	log.Printf("init %dx%d", config.Width, config.Height)
@@ -117,7 +130,7 @@ func (d *sh1122) init(config *Config) (err error) {
 		[]byte{sh1122SetDischargeVSLLevel},
 		[]byte{ssd1xxxSetLowColumn},
 		[]byte{ssd1xxxSetHighColumn},
-		[]byte{sh1122SetRowAddress},
+		[]byte{sh1122SetRowAddress, 0x00},
 		[]byte{ssd1xxxSetNormalDisplay},
 		[]byte{ssd1xxxSetDisplayOn},
 	); err != nil {
@@ -127,6 +140,9 @@ func (d *sh1122) init(config *Config) (err error) {
 	if err = d.SetContrast(0x7F); err != nil {
 		return fmt.Errorf("init setting contrast failed: %w", err)
 	}
+	if err = d.SetRotation(config.Rotation); err != nil {
+		return fmt.Errorf("init setting rotation failed: %w", err)
+	}
 	if err = d.Refresh(); err != nil {
 		return fmt.Errorf("init refresh failed: %w", err)
 	}
@@ -149,25 +165,108 @@ func (d *sh1122) SetContrast(level uint8) error {
 	return d.command(ssd1xxxSetContrast, level)
 }
 
+// SetRotation rotates the display. Rotating by 180° is done by the controller, by reversing the segment
+// remap and COM scan direction, other rotations are done in software.
 func (d *sh1122) SetRotation(rotation Rotation) error {
+	rotation &= 3
+	commands := []byte{ssd1322SetRemap, ssd1xxxSetComScanInc}
+	if rotation == Rotate180 {
+		commands = []byte{ssd1xxxSetSegmentRemap, ssd1xxxSetComScanDec}
+		d.setSoftwareRotation(NoRotation)
+	} else {
+		d.setSoftwareRotation(rotation)
+	}
 	d.rotation = rotation
-	return nil
+	return d.commands([]byte{commands[0]}, []byte{commands[1]})
 }
 
-func (d *sh1122) Refresh() (err error) {
-	pix := d.Image.(*pixel.Gray4Image).Pix
-	log.Printf("push %d pixels", len(pix))
-	if err = d.commands(
-		[]byte{ssd1xxxSetLowColumn},
-		[]byte{ssd1xxxSetHighColumn},
-		[]byte{sh1122SetRowAddress},
-	); err != nil {
+// Refresh redraws the whole display.
+func (d *sh1122) Refresh() error {
+	return d.RefreshRect(d.Bounds())
+}
+
+// RefreshRect redraws the rows and columns covering r. Columns are addressed in groups of 2 pixels.
+func (d *sh1122) RefreshRect(r image.Rectangle) (err error) {
+	if r = d.bufferRect(r); r.Empty() {
 		return
 	}
-	for i, l := 0, len(pix); i < l; i += 4096 {
-		if err = d.data(pix[i : i+4096]...); err != nil {
+
+	var (
+		i  = d.Image.(*pixel.Gray4Image)
+		x0 = r.Min.X &^ 1
+		x1 = (r.Max.X + 1) &^ 1
+	)
+	if x0 == 0 && x1 == d.width {
+		// The row address increments automatically, so full rows can be streamed.
+		if err = d.setAddress(0, r.Min.Y); err != nil {
+			return
+		}
+		pix := i.Pix[r.Min.Y*i.Stride : r.Max.Y*i.Stride]
+		for j, l := 0, len(pix); j < l; j += 4096 {
+			k := j + 4096
+			if k > l {
+				k = l
+			}
+			if err = d.data(pix[j:k]...); err != nil {
+				return
+			}
+		}
+		return
+	}
+
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		if err = d.setAddress(x0, y); err != nil {
+			return
+		}
+		off := y * i.Stride
+		if err = d.data(i.Pix[off+x0/2 : off+x1/2]...); err != nil {
 			return
 		}
 	}
 	return
 }
+
+// setAddress sets the RAM address to pixel x (which must be even) and row y.
+func (d *sh1122) setAddress(x, y int) error {
+	column := byte(x / 2)
+	return d.commands(
+		[]byte{ssd1xxxSetLowColumn | column&0xf},
+		[]byte{ssd1xxxSetHighColumn | column>>4},
+		[]byte{sh1122SetRowAddress, byte(y)},
+	)
+}
+
+// SetInvert toggles inverted pixels.
+func (d *sh1122) SetInvert(invert bool) error {
+	if invert {
+		return d.command(ssd1xxxSetInvertDisplay)
+	}
+	return d.command(ssd1xxxSetNormalDisplay)
+}
+
+// Scroll sets the display start line, the RAM has 64 rows.
+func (d *sh1122) Scroll(line int) error {
+	return d.command(sh1122SetDisplayStartLine | startLine(line, 64))
+}
+
+// SetOffset sets the vertical display offset, the RAM has 64 rows. Horizontal offsets are not supported.
+func (d *sh1122) SetOffset(x, y int) error {
+	if x != 0 {
+		return errHorizontalOffset("SH1122")
+	}
+	return d.command(ssd1xxxSetDisplayOffset, startLine(int(d.offset)+y, 64))
+}
+
+// Info describes the SH1122 controller.
+func (d *sh1122) Info() Info {
+	return d.describe("SH1122", d)
+}
+
+// Interface checks
+var (
+	_ WindowRefresher = (*sh1122)(nil)
+	_ Inverter        = (*sh1122)(nil)
+	_ Scroller        = (*sh1122)(nil)
+	_ Describer       = (*sh1122)(nil)
+	_ Offsetter       = (*sh1122)(nil)
+)
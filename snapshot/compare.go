@@ -0,0 +1,178 @@
+package snapshot
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BeatGlow/display"
+	"github.com/BeatGlow/display/pixel"
+)
+
+// UpdateEnv is the environment variable that makes Compare write golden images.
+const UpdateEnv = "SNAPSHOT_UPDATE"
+
+// Image returns the content of a display as an 8-bit gray image for monochrome and grayscale color
+// models, or as an RGBA image for other color models.
+func Image(d display.Display) image.Image {
+	b := d.Bounds()
+	switch d.ColorModel() {
+	case pixel.MonoModel, pixel.Gray2Model, pixel.Gray4Model:
+		i := image.NewGray(b)
+		for y := b.Min.Y; y < b.Max.Y; y++ {
+			for x := b.Min.X; x < b.Max.X; x++ {
+				i.Set(x, y, d.At(x, y))
+			}
+		}
+		return i
+	default:
+		i := image.NewRGBA(b)
+		draw.Draw(i, b, d, b.Min, draw.Src)
+		return i
+	}
+}
+
+// Diff compares two images by their 8-bit RGB values and returns the number of differing pixels.
+//
+// If the images differ, an image is returned with the expected image, the actual image and their
+// differences side by side. Differing pixels are red, other pixels are shown dimmed.
+func Diff(want, got image.Image) (n int, diff image.Image) {
+	var (
+		wb, gb = want.Bounds(), got.Bounds()
+		w, h   = max(wb.Dx(), gb.Dx()), max(wb.Dy(), gb.Dy())
+		out    = image.NewRGBA(image.Rect(0, 0, w*3+2, h))
+		gap    = color.RGBA{R: 0x40, G: 0x40, B: 0xFF, A: 0xFF}
+	)
+	draw.Draw(out, out.Bounds(), image.NewUniform(gap), image.Point{}, draw.Src)
+	draw.Draw(out, image.Rect(0, 0, wb.Dx(), wb.Dy()), want, wb.Min, draw.Src)
+	draw.Draw(out, image.Rect(w+1, 0, w+1+gb.Dx(), gb.Dy()), got, gb.Min, draw.Src)
+
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			var (
+				p         = image.Pt(x, y)
+				inW, inG  = p.Add(wb.Min).In(wb), p.Add(gb.Min).In(gb)
+				wantRGB   uint32
+				gotRGB    uint32
+				diffColor color.RGBA
+			)
+			if inW {
+				wantRGB = rgb(want.At(wb.Min.X+x, wb.Min.Y+y))
+			}
+			if inG {
+				gotRGB = rgb(got.At(gb.Min.X+x, gb.Min.Y+y))
+			}
+			if inW != inG || wantRGB != gotRGB {
+				n++
+				diffColor = color.RGBA{R: 0xFF, A: 0xFF}
+			} else {
+				diffColor = color.RGBA{
+					R: uint8(wantRGB>>16) / 4,
+					G: uint8(wantRGB>>8) / 4,
+					B: uint8(wantRGB) / 4,
+					A: 0xFF,
+				}
+			}
+			out.SetRGBA(w*2+2+x, y, diffColor)
+		}
+	}
+	if n == 0 {
+		return 0, nil
+	}
+	return n, out
+}
+
+func rgb(c color.Color) uint32 {
+	r, g, b, _ := c.RGBA()
+	return (r>>8)<<16 | (g>>8)<<8 | b>>8
+}
+
+// TB is the part of [testing.TB] used by Compare, so the package doesn't depend on the testing package.
+type TB interface {
+	Helper()
+	Errorf(format string, args ...any)
+}
+
+// Compare the content of a display against the golden PNG image. The golden image is written instead, if
+// the SNAPSHOT_UPDATE environment variable is set.
+//
+// On a mismatch the test fails and a diff image is written next to the golden image, with a ".diff.png"
+// suffix. Stale diff images are removed when the images match.
+func Compare(t TB, d display.Display, golden string) {
+	t.Helper()
+
+	got := Image(d)
+	if os.Getenv(UpdateEnv) != "" {
+		if err := WritePNG(golden, got); err != nil {
+			t.Errorf("snapshot: %v", err)
+			return
+		}
+		if l, ok := t.(interface{ Logf(string, ...any) }); ok {
+			l.Logf("snapshot: updated %s", golden)
+		}
+		return
+	}
+
+	want, err := ReadPNG(golden)
+	if errors.Is(err, os.ErrNotExist) {
+		t.Errorf("snapshot: golden image %s does not exist, run with %s=1 to create it", golden, UpdateEnv)
+		return
+	} else if err != nil {
+		t.Errorf("snapshot: %v", err)
+		return
+	}
+
+	diffName := strings.TrimSuffix(golden, filepath.Ext(golden)) + ".diff.png"
+	n, diff := Diff(want, got)
+	if n == 0 {
+		_ = os.Remove(diffName)
+		return
+	}
+
+	var sizeInfo string
+	if ws, gs := want.Bounds().Size(), got.Bounds().Size(); ws != gs {
+		sizeInfo = fmt.Sprintf(" (expected %dx%d, got %dx%d)", ws.X, ws.Y, gs.X, gs.Y)
+	}
+	if err = WritePNG(diffName, diff); err != nil {
+		t.Errorf("snapshot %s: %d pixels differ%s, writing diff failed: %v", golden, n, sizeInfo, err)
+		return
+	}
+	t.Errorf("snapshot %s: %d pixels differ%s, see %s", golden, n, sizeInfo, diffName)
+}
+
+// ReadPNG reads a PNG image.
+func ReadPNG(name string) (image.Image, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	i, err := png.Decode(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return i, nil
+}
+
+// WritePNG writes a PNG image, creating the parent directories if needed.
+func WritePNG(name string, i image.Image) (err error) {
+	if err = os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+		return
+	}
+	var f *os.File
+	if f, err = os.Create(name); err != nil {
+		return
+	}
+	if err = png.Encode(f, i); err != nil {
+		_ = f.Close()
+		return
+	}
+	return f.Close()
+}
@@ -0,0 +1,15 @@
+// Package snapshot renders displays to PNG files and compares them against golden images in tests.
+//
+// A [File] is a display that writes a PNG image on every refresh, either overwriting a single file or as
+// a numbered sequence. [Compare] checks the current content of any display against a golden PNG image:
+//
+//	func TestMenu(t *testing.T) {
+//		d, _ := snapshot.NewFile("", &snapshot.Config{Model: pixel.Gray4Model})
+//		drawMenu(d)
+//		snapshot.Compare(t, d, "testdata/menu.png")
+//	}
+//
+// Golden images are (re)written instead of compared when the SNAPSHOT_UPDATE environment variable is set
+// to a non-empty value. On a mismatch a diff image is written next to the golden image, it shows the
+// expected image, the actual image and the differing pixels (in red) side by side.
+package snapshot
@@ -0,0 +1,121 @@
+package snapshot
+
+import (
+	"fmt"
+	"image/color"
+	"strings"
+
+	"github.com/BeatGlow/display"
+	"github.com/BeatGlow/display/internal/virtual"
+)
+
+// Config is the file display configuration.
+type Config struct {
+	display.Config
+
+	// Model is the color model of the emulated display, one of [pixel.MonoModel] (default),
+	// [pixel.Gray2Model], [pixel.Gray4Model] or [pixel.CRGB16Model].
+	Model color.Model
+}
+
+// File is a display that writes a PNG image on every refresh.
+//
+// The width, height and rotation from the configuration are honoured like drivers that rotate in
+// software. The images show what a viewer of the mounted panel would see, so they are always upright.
+//
+// The contrast level is recorded but not applied to the images, a display that is switched off is written
+// as a black image.
+type File struct {
+	*virtual.Frame
+	pattern  string
+	frame    int
+	contrast uint8
+	on       bool
+}
+
+// NewFile returns a display writing PNG images to pattern on refresh. If pattern contains a formatting
+// verb, such as "frame-%04d.png", it is formatted with the frame number, starting at 0. Otherwise every
+// refresh overwrites the same file. Refresh doesn't write a file if the pattern is empty.
+func NewFile(pattern string, config *Config) (*File, error) {
+	if config == nil {
+		config = new(Config)
+	}
+	frame, err := virtual.NewFrame(config.Model, &config.Config)
+	if err != nil {
+		return nil, err
+	}
+	return &File{
+		Frame:    frame,
+		pattern:  pattern,
+		contrast: 0xFF,
+		on:       true,
+	}, nil
+}
+
+func (d *File) String() string {
+	return "file " + d.Frame.String()
+}
+
+// Name returns the file name for the next refresh.
+func (d *File) Name() string {
+	if strings.Contains(d.pattern, "%") {
+		return fmt.Sprintf(d.pattern, d.frame)
+	}
+	return d.pattern
+}
+
+// Frames returns the number of refreshes.
+func (d *File) Frames() int {
+	return d.frame
+}
+
+// Close the display.
+func (d *File) Close() error {
+	return nil
+}
+
+// Show toggles the display on or off, a display that is off is written as a black image.
+func (d *File) Show(show bool) error {
+	d.on = show
+	return nil
+}
+
+// Contrast returns the contrast level.
+func (d *File) Contrast() uint8 {
+	return d.contrast
+}
+
+// SetContrast records the contrast level.
+func (d *File) SetContrast(level uint8) error {
+	d.contrast = level
+	return nil
+}
+
+// Refresh writes the display contents to a PNG file.
+func (d *File) Refresh() error {
+	name := d.Name()
+	d.frame++
+	if name == "" {
+		return nil
+	}
+
+	if !d.on {
+		return WritePNG(name, Image(blank{d}))
+	}
+	return WritePNG(name, Image(d))
+}
+
+// blank is a display that is switched off.
+type blank struct {
+	display.Display
+}
+
+func (blank) At(int, int) color.Color {
+	return color.Black
+}
+
+// Interface checks
+var (
+	_ display.Display  = (*File)(nil)
+	_ display.Buffered = (*File)(nil)
+)
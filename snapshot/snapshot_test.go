@@ -0,0 +1,166 @@
+package snapshot_test
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/BeatGlow/display"
+	"github.com/BeatGlow/display/draw"
+	"github.com/BeatGlow/display/pixel"
+	"github.com/BeatGlow/display/snapshot"
+)
+
+func TestFile(t *testing.T) {
+	var (
+		dir     = t.TempDir()
+		pattern = filepath.Join(dir, "frame-%02d.png")
+	)
+	d, err := snapshot.NewFile(pattern, &snapshot.Config{
+		Config: display.Config{Width: 8, Height: 4, Rotation: display.Rotate90},
+		Model:  pixel.Gray4Model,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b := d.Bounds(); b.Dx() != 4 || b.Dy() != 8 {
+		t.Fatalf("expected 4x8 bounds, got %s", b)
+	}
+	d.Set(1, 2, pixel.Gray4{Y: 0xF})
+	if err = d.Refresh(); err != nil {
+		t.Fatal(err)
+	}
+	if err = d.Show(false); err != nil {
+		t.Fatal(err)
+	}
+	if err = d.Refresh(); err != nil {
+		t.Fatal(err)
+	}
+	if d.Frames() != 2 {
+		t.Errorf("expected 2 frames, got %d", d.Frames())
+	}
+
+	for n, want := range []uint8{0xFF, 0x00} {
+		i, err := snapshot.ReadPNG(fmt.Sprintf(pattern, n))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if b := i.Bounds(); b.Dx() != 4 || b.Dy() != 8 {
+			t.Errorf("frame %d: expected 4x8 image, got %s", n, b)
+		}
+		if v := color.GrayModel.Convert(i.At(1, 2)).(color.Gray).Y; v != want {
+			t.Errorf("frame %d: expected pixel %#02x, got %#02x", n, want, v)
+		}
+	}
+}
+
+func TestDiff(t *testing.T) {
+	var (
+		want = image.NewGray(image.Rect(0, 0, 4, 2))
+		got  = image.NewGray(image.Rect(0, 0, 4, 2))
+	)
+	if n, diff := snapshot.Diff(want, got); n != 0 || diff != nil {
+		t.Errorf("expected no differences, got %d", n)
+	}
+
+	got.SetGray(3, 1, color.Gray{Y: 0xFF})
+	n, diff := snapshot.Diff(want, got)
+	if n != 1 {
+		t.Fatalf("expected 1 difference, got %d", n)
+	}
+	if b := diff.Bounds(); b.Dx() != 14 || b.Dy() != 2 {
+		t.Errorf("expected 14x2 diff image, got %s", b)
+	}
+	if r, _, _, _ := diff.At(8+2+3, 1).RGBA(); r != 0xFFFF {
+		t.Error("expected differing pixel to be red")
+	}
+
+	if n, _ = snapshot.Diff(want, image.NewGray(image.Rect(0, 0, 4, 3))); n != 4 {
+		t.Errorf("expected 4 differences for a size mismatch, got %d", n)
+	}
+}
+
+// screen draws a test screen.
+func screen(t *testing.T, model color.Model) display.Display {
+	d, err := snapshot.NewFile("", &snapshot.Config{
+		Config: display.Config{Width: 32, Height: 16},
+		Model:  model,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	draw.RoundedBox(d, image.Rect(1, 1, 31, 15), 4, color.White)
+	for x := 4; x < 28; x++ {
+		d.Set(x, 8, color.RGBA{R: uint8(x * 8), G: 0x80, B: 0xFF - uint8(x*8), A: 0xFF})
+	}
+	return d
+}
+
+func TestCompare(t *testing.T) {
+	for _, test := range []struct {
+		Name  string
+		Model color.Model
+	}{
+		{"mono", pixel.MonoModel},
+		{"gray4", pixel.Gray4Model},
+		{"rgb", pixel.CRGB16Model},
+	} {
+		t.Run(test.Name, func(it *testing.T) {
+			snapshot.Compare(it, screen(it, test.Model), filepath.Join("testdata", test.Name+".png"))
+		})
+	}
+}
+
+type recorder struct {
+	errors []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestCompareMismatch(t *testing.T) {
+	var (
+		dir    = t.TempDir()
+		golden = filepath.Join(dir, "screen.png")
+		diff   = filepath.Join(dir, "screen.diff.png")
+		d      = screen(t, pixel.MonoModel)
+		r      = new(recorder)
+	)
+	t.Setenv(snapshot.UpdateEnv, "")
+	if snapshot.Compare(r, d, golden); len(r.errors) != 1 || !strings.Contains(r.errors[0], "does not exist") {
+		t.Fatalf("expected missing golden image error, got %q", r.errors)
+	}
+
+	t.Setenv(snapshot.UpdateEnv, "1")
+	r.errors = nil
+	if snapshot.Compare(r, d, golden); len(r.errors) != 0 {
+		t.Fatalf("expected golden image to be written, got %q", r.errors)
+	}
+	t.Setenv(snapshot.UpdateEnv, "")
+
+	d.Set(0, 0, pixel.On)
+	d.Set(31, 15, pixel.On)
+	if snapshot.Compare(r, d, golden); len(r.errors) != 1 || !strings.Contains(r.errors[0], "2 pixels differ") {
+		t.Fatalf("expected 2 pixels to differ, got %q", r.errors)
+	}
+	if _, err := os.Stat(diff); err != nil {
+		t.Errorf("expected diff image: %v", err)
+	}
+
+	r.errors = nil
+	d.Set(0, 0, pixel.Off)
+	d.Set(31, 15, pixel.Off)
+	if snapshot.Compare(r, d, golden); len(r.errors) != 0 {
+		t.Fatalf("expected images to match, got %q", r.errors)
+	}
+	if _, err := os.Stat(diff); !os.IsNotExist(err) {
+		t.Errorf("expected stale diff image to be removed, got %v", err)
+	}
+}
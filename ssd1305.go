@@ -2,6 +2,7 @@ package display
 
 import (
 	"fmt"
+	"image"
 
 	"github.com/BeatGlow/display/pixel"
 )
@@ -9,16 +10,29 @@ import (
 const (
 	ssd1305DefaultWidth    = 128
 	ssd1305DefaultHeight   = 32
-	ssd1305SetPageAddr     = 0x22
+	ssd1305SetPageAddr     = 0xB0
 	ssd1305SetLUT          = 0x91
 	ssd1305SetMasterConfig = 0xAD
 	ssd1305setAreaColor    = 0xD8
+	ssd1305ColumnOffset    = 2  // 128 pixels are centered in the 132 column RAM
+	ssd1305Rows            = 64 // RAM rows, all driven by the multiplex ratio
 )
 
+func init() {
+	Register(Driver{
+		Name:   "ssd1305",
+		Buses:  I2CBus | SPIBus,
+		Width:  ssd1305DefaultWidth,
+		Height: ssd1305DefaultHeight,
+		New:    SSD1305,
+	})
+}
+
 type ssd1305 struct {
 	monoDisplay
 	pageSize   int
 	pageOffset int
+	offset     int // vertical display offset set with SetOffset
 }
 
 // SSD1305 is a driver for the Sino Wealth SSD1305 OLED display.
@@ -26,7 +40,8 @@ func SSD1305(conn Conn, config *Config) (Display, error) {
 	d := &ssd1305{
 		monoDisplay: monoDisplay{
 			baseDisplay: baseDisplay{
-				c: conn,
+				c:         conn,
+				colOffset: ssd1305ColumnOffset,
 			},
 		},
 	}
@@ -106,6 +121,9 @@ func (d *ssd1305) init(config *Config) (err error) {
 	if err = d.SetContrast(0x7F); err != nil {
 		return
 	}
+	if err = d.SetRotation(config.Rotation); err != nil {
+		return
+	}
 	if err = d.Refresh(); err != nil {
 		return
 	}
@@ -116,24 +134,89 @@ func (d *ssd1305) init(config *Config) (err error) {
 	return
 }
 
-func (d *ssd1305) Refresh() (err error) {
-	//const pageSize = 8
-	pix := d.Image.(*pixel.MonoVerticalLSBImage).Pix
-	for page := 0; page < d.pageSize; page++ {
+// Refresh redraws the whole display.
+func (d *ssd1305) Refresh() error {
+	return d.RefreshRect(d.Bounds())
+}
+
+// RefreshRect redraws the pages and columns covering r.
+func (d *ssd1305) RefreshRect(r image.Rectangle) (err error) {
+	if r = d.bufferRect(r); r.Empty() {
+		return
+	}
+
+	var (
+		pix    = d.Image.(*pixel.MonoVerticalLSBImage).Pix
+		column = d.colOffset + r.Min.X
+	)
+	for page := r.Min.Y >> 3; page < (r.Max.Y+7)>>3; page++ {
 		if err = d.command(
 			ssd1305SetPageAddr|byte(page&0x7),
-			ssd1xxxSetLowColumn|0x2,
-			ssd1xxxSetHighColumn|0x0, //nolint:staticcheck
+			ssd1xxxSetLowColumn|byte(column&0xf),
+			ssd1xxxSetHighColumn|byte(column>>4),
 		); err != nil {
 			return
 		}
-		var (
-			off = page * d.width
-			end = off + d.width
-		)
-		if err := d.data(pix[off:end]...); err != nil {
-			return err
+		off := page * d.width
+		if err = d.data(pix[off+r.Min.X : off+r.Max.X]...); err != nil {
+			return
 		}
 	}
-	return nil
+	return
+}
+
+// SetInvert toggles inverted pixels.
+func (d *ssd1305) SetInvert(invert bool) error {
+	if invert {
+		return d.command(ssd1xxxSetInvertDisplay)
+	}
+	return d.command(ssd1xxxSetNormalDisplay)
 }
+
+// SetRotation rotates the display like [monoDisplay.SetRotation].
+//
+// The multiplex ratio always covers the 64 rows of the RAM, so with the COM scan direction reversed panels
+// with fewer rows show the bottom of the RAM; the display offset moves the picture back into view.
+func (d *ssd1305) SetRotation(rotation Rotation) error {
+	if err := d.monoDisplay.SetRotation(rotation); err != nil {
+		return err
+	}
+	return d.setDisplayOffset()
+}
+
+// setDisplayOffset sets the display offset for the rotation and the offset set with SetOffset.
+func (d *ssd1305) setDisplayOffset() error {
+	offset := d.offset
+	if d.rotation == Rotate180 {
+		offset += ssd1305Rows - d.height
+	}
+	return d.command(ssd1xxxSetDisplayOffset, startLine(offset, ssd1305Rows))
+}
+
+// Scroll sets the display start line, the RAM has 64 rows.
+func (d *ssd1305) Scroll(line int) error {
+	return d.command(ssd1xxxSetStartLine | startLine(line, ssd1305Rows))
+}
+
+// SetOffset sets the vertical display offset, the RAM has 64 rows. Horizontal offsets are not supported.
+func (d *ssd1305) SetOffset(x, y int) error {
+	if x != 0 {
+		return errHorizontalOffset("SSD1305")
+	}
+	d.offset = y
+	return d.setDisplayOffset()
+}
+
+// Info describes the SSD1305 controller.
+func (d *ssd1305) Info() Info {
+	return d.describe("SSD1305", d)
+}
+
+// Interface checks
+var (
+	_ WindowRefresher = (*ssd1305)(nil)
+	_ Inverter        = (*ssd1305)(nil)
+	_ Scroller        = (*ssd1305)(nil)
+	_ Describer       = (*ssd1305)(nil)
+	_ Offsetter       = (*ssd1305)(nil)
+)
@@ -2,6 +2,7 @@ package display
 
 import (
 	"fmt"
+	"image"
 
 	"github.com/BeatGlow/display/pixel"
 )
@@ -10,14 +11,31 @@ const (
 	ssd1306DefaultWidth  = 128
 	ssd1306DefaultHeight = 64
 	ssd1306SetPageAddr   = 0xB0
+	ssd1306SetFadeBlink  = 0x23
+	ssd1306SetZoomIn     = 0xD6
 )
 
+// ssd1306ScrollIntervals are the supported scroll step intervals in frames, by their 3-bit setting.
+var ssd1306ScrollIntervals = [8]int{5, 64, 128, 256, 3, 4, 25, 2}
+
+func init() {
+	Register(Driver{
+		Name:   "ssd1306",
+		Buses:  I2CBus | SPIBus,
+		Width:  ssd1306DefaultWidth,
+		Height: ssd1306DefaultHeight,
+		New:    SSD1306,
+	})
+}
+
 type ssd1306 struct {
 	monoDisplay
-	pageSize int
-	width    int
-	colStart byte
-	colEnd   byte
+	pageSize  int
+	width     int
+	colStart  byte
+	colEnd    byte
+	comPins   byte
+	scrolling bool
 }
 
 func SSD1306(conn Conn, config *Config) (Display, error) {
@@ -61,7 +79,7 @@ func (d *ssd1306) String() string {
 
 func (d *ssd1306) init(config *Config) (err error) {
 	var (
-		multiplexRatio  byte = byte(config.Width - 1)
+		multiplexRatio  byte = byte(config.Height - 1)
 		displayClockDiv byte
 		comPins         byte
 		colStart        byte
@@ -86,6 +104,7 @@ func (d *ssd1306) init(config *Config) (err error) {
 	d.width = config.Width
 	d.colStart = colStart
 	d.colEnd = colStart + byte(config.Width)
+	d.comPins = comPins
 
 	// init base
 	if err = d.monoDisplay.init(config); err != nil {
@@ -115,6 +134,9 @@ func (d *ssd1306) init(config *Config) (err error) {
 	if err = d.SetContrast(0xCF); err != nil {
 		return
 	}
+	if err = d.SetRotation(config.Rotation); err != nil {
+		return
+	}
 	if err = d.Refresh(); err != nil {
 		return
 	}
@@ -125,24 +147,187 @@ func (d *ssd1306) init(config *Config) (err error) {
 	return
 }
 
-func (d *ssd1306) Refresh() (err error) {
-	//const pageSize = 8
-	pix := d.Image.(*pixel.MonoVerticalLSBImage).Pix
-	for page := 0; page < d.pageSize; page++ {
-		if err = d.command(
-			ssd1xxxSetColumnAddr, d.colStart, d.colEnd-1,
-			ssd1xxxSetStartLine|0x0, //nolint:staticcheck
-			ssd1xxxSetPageAddr, 0x00, byte(page),
-		); err != nil {
+// Refresh redraws the whole display.
+func (d *ssd1306) Refresh() error {
+	return d.RefreshRect(d.Bounds())
+}
+
+// RefreshRect redraws the pages and columns covering r. Refreshing stops hardware scrolling, after which
+// the whole display is redrawn.
+func (d *ssd1306) RefreshRect(r image.Rectangle) (err error) {
+	if d.scrolling {
+		if err = d.command(ssd1xxxDeactivateScroll); err != nil {
 			return
 		}
-		var (
-			off = page * d.width
-			end = off + d.width
-		)
-		if err := d.data(pix[off:end]...); err != nil {
-			return err
+		d.scrolling = false
+		r = d.Bounds()
+	}
+	if r = d.bufferRect(r); r.Empty() {
+		return
+	}
+
+	var (
+		pix       = d.Image.(*pixel.MonoVerticalLSBImage).Pix
+		pageStart = r.Min.Y >> 3
+		pageEnd   = (r.Max.Y + 7) >> 3
+	)
+	if err = d.command(
+		ssd1xxxSetColumnAddr, d.colStart+byte(r.Min.X), d.colStart+byte(r.Max.X-1),
+		ssd1xxxSetPageAddr, byte(pageStart), byte(pageEnd-1),
+	); err != nil {
+		return
+	}
+	for page := pageStart; page < pageEnd; page++ {
+		off := page * d.width
+		if err = d.data(pix[off+r.Min.X : off+r.Max.X]...); err != nil {
+			return
+		}
+	}
+	return
+}
+
+// SetInvert toggles inverted pixels.
+func (d *ssd1306) SetInvert(invert bool) error {
+	if invert {
+		return d.command(ssd1xxxSetInvertDisplay)
+	}
+	return d.command(ssd1xxxSetNormalDisplay)
+}
+
+// Scroll sets the display start line, the RAM has 64 rows.
+func (d *ssd1306) Scroll(line int) error {
+	return d.command(ssd1xxxSetStartLine | startLine(line, 64))
+}
+
+// SetOffset sets the vertical display offset, the RAM has 64 rows. Horizontal offsets are not supported.
+func (d *ssd1306) SetOffset(x, y int) error {
+	if x != 0 {
+		return errHorizontalOffset("SSD1306")
+	}
+	return d.command(ssd1xxxSetDisplayOffset, startLine(y, 64))
+}
+
+// StartScroll starts continuous horizontal or diagonal scrolling of the pages between StartPage and
+// EndPage. The scroll interval is rounded to the nearest of 2, 3, 4, 5, 25, 64, 128 or 256 frames.
+func (d *ssd1306) StartScroll(scroll HardwareScroll) (err error) {
+	if scroll.StartPage < 0 || scroll.EndPage >= d.pageSize || scroll.StartPage > scroll.EndPage {
+		return fmt.Errorf("%w: SSD1306 scroll pages %d-%d", ErrBounds, scroll.StartPage, scroll.EndPage)
+	}
+
+	var (
+		interval = ssd1306ScrollInterval(scroll.Interval)
+		start    = byte(scroll.StartPage)
+		end      = byte(scroll.EndPage)
+		scrolls  [][]byte
+	)
+	switch scroll.Direction {
+	case ScrollRight, ScrollLeft:
+		command := byte(ssd1xxxRightHorizontalScroll)
+		if scroll.Direction == ScrollLeft {
+			command = ssd1xxxLeftHorizontalScroll
+		}
+		scrolls = [][]byte{{command, 0x00, start, interval, end, 0x00, 0xFF}}
+	case ScrollDiagonalRight, ScrollDiagonalLeft:
+		rows := scroll.Rows
+		if rows == 0 {
+			rows = d.height - scroll.Top
+		}
+		if scroll.Top < 0 || rows < 0 || scroll.Top+rows > d.height {
+			return fmt.Errorf("%w: SSD1306 vertical scroll area %d+%d", ErrBounds, scroll.Top, rows)
+		}
+		if scroll.VerticalOffset < 0 || scroll.VerticalOffset >= d.height {
+			return fmt.Errorf("%w: SSD1306 vertical scroll offset %d", ErrBounds, scroll.VerticalOffset)
+		}
+		command := byte(ssd1xxxVerticalRightHorizontalScroll)
+		if scroll.Direction == ScrollDiagonalLeft {
+			command = ssd1xxxVerticalLeftHorizontalScroll
 		}
+		scrolls = [][]byte{
+			{ssd1xxxSetVerticalScrollArea, byte(scroll.Top), byte(rows)},
+			{command, 0x00, start, interval, end, byte(scroll.VerticalOffset)},
+		}
+	default:
+		return fmt.Errorf("display: SSD1306 unsupported scroll direction %s", scroll.Direction)
+	}
+
+	// Scroll parameters may only be changed while scrolling is deactivated.
+	if err = d.command(ssd1xxxDeactivateScroll); err != nil {
+		return
+	}
+	d.scrolling = false
+	if err = d.commands(scrolls...); err != nil {
+		return
+	}
+	if err = d.command(ssd1xxxActivateScroll); err != nil {
+		return
 	}
-	return nil
+	d.scrolling = true
+	return
 }
+
+// StopScroll stops scrolling and redraws the display.
+func (d *ssd1306) StopScroll() error {
+	if err := d.command(ssd1xxxDeactivateScroll); err != nil {
+		return err
+	}
+	d.scrolling = false
+	return d.Refresh()
+}
+
+// ssd1306ScrollInterval returns the setting for the supported interval nearest to frames.
+func ssd1306ScrollInterval(frames int) byte {
+	var best byte
+	for i, v := range ssd1306ScrollIntervals {
+		if abs(v-frames) < abs(ssd1306ScrollIntervals[best]-frames) {
+			best = byte(i)
+		}
+	}
+	return best
+}
+
+// SetFade fades out or blinks the display, by stepping the contrast every 8 to 128 frames, in multiples of
+// 8 frames.
+func (d *ssd1306) SetFade(mode FadeMode, interval int) error {
+	var setting byte
+	switch mode {
+	case FadeOff:
+	case FadeOut:
+		setting = 0x20
+	case Blink:
+		setting = 0x30
+	default:
+		return fmt.Errorf("display: SSD1306 unsupported fade mode %s", mode)
+	}
+	step := min(max((interval+4)/8, 1), 16)
+	return d.command(ssd1306SetFadeBlink, setting|byte(step-1))
+}
+
+// SetZoom toggles the zoom in mode, which requires the alternative COM pin configuration used by panels
+// with 48 or 64 rows.
+func (d *ssd1306) SetZoom(zoom bool) error {
+	if d.comPins&0x10 == 0 {
+		return fmt.Errorf("display: SSD1306 zoom is not supported at %dx%d", d.width, d.height)
+	}
+	var setting byte
+	if zoom {
+		setting = 0x01
+	}
+	return d.command(ssd1306SetZoomIn, setting)
+}
+
+// Info describes the SSD1306 controller.
+func (d *ssd1306) Info() Info {
+	return d.describe("SSD1306", d)
+}
+
+// Interface checks
+var (
+	_ WindowRefresher  = (*ssd1306)(nil)
+	_ Inverter         = (*ssd1306)(nil)
+	_ Scroller         = (*ssd1306)(nil)
+	_ Describer        = (*ssd1306)(nil)
+	_ HardwareScroller = (*ssd1306)(nil)
+	_ Fader            = (*ssd1306)(nil)
+	_ Zoomer           = (*ssd1306)(nil)
+	_ Offsetter        = (*ssd1306)(nil)
+)
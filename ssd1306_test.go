@@ -0,0 +1,150 @@
+package display
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/BeatGlow/display/conntest"
+)
+
+func TestSSD1306StartScroll(t *testing.T) {
+	tests := []struct {
+		Name   string
+		Scroll HardwareScroll
+		Want   [][]byte
+	}{
+		{
+			"right",
+			HardwareScroll{Direction: ScrollRight, StartPage: 2, EndPage: 5, Interval: 5},
+			[][]byte{{0x2E}, {0x26, 0x00, 0x02, 0x00, 0x05, 0x00, 0xFF}, {0x2F}},
+		},
+		{
+			"left rounded to 2 frames",
+			HardwareScroll{Direction: ScrollLeft, EndPage: 7},
+			[][]byte{{0x2E}, {0x27, 0x00, 0x00, 0x07, 0x07, 0x00, 0xFF}, {0x2F}},
+		},
+		{
+			"diagonal right",
+			HardwareScroll{Direction: ScrollDiagonalRight, EndPage: 7, Interval: 30, VerticalOffset: 1, Top: 16},
+			[][]byte{{0x2E}, {0xA3, 16, 48}, {0x29, 0x00, 0x00, 0x06, 0x07, 0x01}, {0x2F}},
+		},
+		{
+			"diagonal left",
+			HardwareScroll{Direction: ScrollDiagonalLeft, StartPage: 1, EndPage: 1, Interval: 1000, Rows: 8},
+			[][]byte{{0x2E}, {0xA3, 0, 8}, {0x2A, 0x00, 0x01, 0x03, 0x01, 0x00}, {0x2F}},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(it *testing.T) {
+			c := conntest.NewRecorder()
+			d, err := Open("ssd1306", c, &Config{})
+			if err != nil {
+				it.Fatal(err)
+			}
+			c.Clear()
+			if err = d.(HardwareScroller).StartScroll(test.Scroll); err != nil {
+				it.Fatal(err)
+			}
+			var got [][]byte
+			for _, call := range c.Filter(conntest.OpCommand) {
+				got = append(got, append([]byte{call.Command}, call.Data...))
+			}
+			if !reflect.DeepEqual(got, test.Want) {
+				it.Errorf("expected commands % x, got % x", test.Want, got)
+			}
+		})
+	}
+}
+
+func TestSSD1306ScrollErrors(t *testing.T) {
+	d, err := Open("ssd1306", conntest.NewRecorder(), &Config{Width: 128, Height: 32})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := d.(HardwareScroller)
+	for _, scroll := range []HardwareScroll{
+		{StartPage: 0, EndPage: 4},
+		{StartPage: 3, EndPage: 2},
+		{StartPage: -1, EndPage: 2},
+		{Direction: ScrollDiagonalRight, EndPage: 3, Top: 16, Rows: 17},
+		{Direction: ScrollDiagonalLeft, EndPage: 3, VerticalOffset: 32},
+	} {
+		if err = s.StartScroll(scroll); !errors.Is(err, ErrBounds) {
+			t.Errorf("%+v: expected %v, got %v", scroll, ErrBounds, err)
+		}
+	}
+	if err = s.StartScroll(HardwareScroll{Direction: 42}); err == nil {
+		t.Error("expected an error for an unsupported direction")
+	}
+}
+
+func TestSSD1306RefreshWhileScrolling(t *testing.T) {
+	c := conntest.NewRecorder()
+	d, err := Open("ssd1306", c, &Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = d.(HardwareScroller).StartScroll(HardwareScroll{EndPage: 1}); err != nil {
+		t.Fatal(err)
+	}
+
+	c.Clear()
+	if err = RefreshRect(d, d.Bounds().Inset(16)); err != nil {
+		t.Fatal(err)
+	}
+	if commands := c.Commands(); len(commands) == 0 || commands[0] != ssd1xxxDeactivateScroll {
+		t.Errorf("expected refresh to deactivate scrolling, got commands % x", commands)
+	}
+	if n := c.DataLen(); n != 128*64/8 {
+		t.Errorf("expected refresh to redraw %d bytes, got %d", 128*64/8, n)
+	}
+
+	c.Clear()
+	if err = RefreshRect(d, d.Bounds().Inset(16)); err != nil {
+		t.Fatal(err)
+	}
+	if n := c.DataLen(); n != 96*32/8 {
+		t.Errorf("expected partial refresh of %d bytes, got %d", 96*32/8, n)
+	}
+}
+
+func TestSSD1306FadeZoom(t *testing.T) {
+	c := conntest.NewRecorder()
+	d, err := Open("ssd1306", c, &Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range []struct {
+		Mode     FadeMode
+		Interval int
+		Want     byte
+	}{
+		{FadeOff, 0, 0x00},
+		{FadeOut, 8, 0x20},
+		{FadeOut, 30, 0x23},
+		{Blink, 128, 0x3F},
+		{Blink, 1000, 0x3F},
+	} {
+		c.Clear()
+		if err = d.(Fader).SetFade(test.Mode, test.Interval); err != nil {
+			t.Fatal(err)
+		}
+		if calls := c.Filter(conntest.OpCommand); len(calls) != 1 || calls[0].Command != ssd1306SetFadeBlink || calls[0].Data[0] != test.Want {
+			t.Errorf("%s every %d frames: expected setting %#02x, got %v", test.Mode, test.Interval, test.Want, calls)
+		}
+	}
+	if err = d.(Fader).SetFade(42, 8); err == nil {
+		t.Error("expected an error for an unsupported fade mode")
+	}
+
+	if err = d.(Zoomer).SetZoom(true); err != nil {
+		t.Fatal(err)
+	}
+	if d, err = Open("ssd1306", c, &Config{Width: 128, Height: 32}); err != nil {
+		t.Fatal(err)
+	}
+	if err = d.(Zoomer).SetZoom(true); err == nil {
+		t.Error("expected zoom to be unsupported at 128x32")
+	}
+}
@@ -62,6 +62,16 @@ var (
 	}
 )
 
+func init() {
+	Register(Driver{
+		Name:   "ssd1322",
+		Buses:  SPIBus | ParallelBus,
+		Width:  ssd1322DefaultWidth,
+		Height: ssd1322DefaultHeight,
+		New:    SSD1322,
+	})
+}
+
 type ssd1322 struct {
 	ssd1xxxDisplay
 }
@@ -178,6 +188,9 @@ func (d *ssd1322) init(config *Config) (err error) {
 	if err = d.SetContrast(0xFF); err != nil {
 		return
 	}
+	if err = d.SetRotation(config.Rotation); err != nil {
+		return
+	}
 	if err = d.Refresh(); err != nil {
 		return
 	}
@@ -210,6 +223,21 @@ func (d *ssd1322) SetContrast(level uint8) error {
 	return d.command(ssd1322SetContrast, level)
 }
 
+// SetRotation rotates the display. Rotating by 180° is done by the controller, by enabling the column
+// address remap and reversing the COM scan direction, other rotations are done in software.
+func (d *ssd1322) SetRotation(rotation Rotation) error {
+	rotation &= 3
+	remap := byte(0x14) // Enable Nibble Re-map, Scan from COM[N-1] to COM0
+	if rotation == Rotate180 {
+		remap = 0x06 // Enable Column Address Re-map, Enable Nibble Re-map, Scan from COM0 to COM[N-1]
+		d.setSoftwareRotation(NoRotation)
+	} else {
+		d.setSoftwareRotation(rotation)
+	}
+	d.rotation = rotation
+	return d.command(ssd1322SetRemap, remap, 0x11)
+}
+
 func (d *ssd1322) setWindow(x, y, width, height int) error {
 	var (
 		x0 = byte((480-d.width)/8) + byte(x/4)
@@ -225,6 +253,10 @@ func (d *ssd1322) setWindow(x, y, width, height int) error {
 
 // Refresh needs to be duplicated here, otherwise we can't access the gray buf.
 func (d *ssd1322) Refresh() error {
+	if _, ok := d.Image.(*pixel.Gray4Image); ok {
+		return d.RefreshRect(d.Bounds())
+	}
+
 	if err := d.setWindow(0, 0, d.width, d.height); err != nil {
 		return err
 	}
@@ -237,13 +269,96 @@ func (d *ssd1322) Refresh() error {
 		return d.data(i.Pix...) // 2048 bytes @ 256x64x1
 	case *pixel.Gray2Image:
 		return d.data(i.Pix...) // 4096 bytes @ 256x64x2
-	case *pixel.Gray4Image:
-		return d.data(i.Pix...) // 8192 bytes @ 256x64x4
 	}
 	return nil
 }
 
+// RefreshRect redraws the columns and rows covering r. Columns are addressed in groups of 4 pixels.
+func (d *ssd1322) RefreshRect(r image.Rectangle) error {
+	i, ok := d.Image.(*pixel.Gray4Image)
+	if !ok {
+		return d.Refresh()
+	}
+	if r = d.bufferRect(r); r.Empty() {
+		return nil
+	}
+
+	var (
+		x0 = r.Min.X &^ 3
+		x1 = (r.Max.X + 3) &^ 3
+	)
+	if err := d.setWindow(x0, r.Min.Y, x1-x0, r.Dy()); err != nil {
+		return err
+	}
+	if err := d.command(ssd1322WriteRAM); err != nil {
+		return err
+	}
+
+	if x0 == 0 && x1 == d.width {
+		return d.data(i.Pix[r.Min.Y*i.Stride : r.Max.Y*i.Stride]...) // 8192 bytes @ 256x64x4
+	}
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		off := y * i.Stride
+		if err := d.data(i.Pix[off+x0/2 : off+x1/2]...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SetInvert toggles inverted pixels.
+func (d *ssd1322) SetInvert(invert bool) error {
+	if invert {
+		return d.command(ssd1322SetInverseDIsplay)
+	}
+	return d.command(ssd1322SetNormalDisplay)
+}
+
+// Scroll sets the display start line, the RAM has 128 rows.
+func (d *ssd1322) Scroll(line int) error {
+	return d.command(ssd1322SetDisplayStartLine, startLine(line, 128))
+}
+
+// SetOffset sets the vertical display offset, the RAM has 128 rows. Horizontal offsets are not supported.
+func (d *ssd1322) SetOffset(x, y int) error {
+	if x != 0 {
+		return errHorizontalOffset("SSD1322")
+	}
+	return d.command(ssd1322SetDisplayOffset, startLine(y, 128))
+}
+
+// SetGrayscaleTable sets the pulse widths for gray levels 1 to 15, in DCLKs. The table has 15 entries in
+// the range [0..180], that should be in ascending order.
+func (d *ssd1322) SetGrayscaleTable(table []uint8) error {
+	if table == nil {
+		return d.command(ssd1322SetDefaultGrayscale)
+	}
+	if len(table) != 15 {
+		return fmt.Errorf("%w: expected 15 levels, got %d", ErrGrayscaleTable, len(table))
+	}
+	for i, v := range table {
+		if v > 180 {
+			return fmt.Errorf("%w: level %d pulse width %d exceeds 180", ErrGrayscaleTable, i+1, v)
+		}
+	}
+	if err := d.command(ssd1322SetGrayScaleTable, table...); err != nil {
+		return err
+	}
+	return d.command(ssd1322EnableGrayScaleTable)
+}
+
+// Info describes the SSD1322 controller.
+func (d *ssd1322) Info() Info {
+	return d.describe("SSD1322", d)
+}
+
 // Interface checks
 var (
-	_ Display = (*ssd1322)(nil)
+	_ Display         = (*ssd1322)(nil)
+	_ WindowRefresher = (*ssd1322)(nil)
+	_ Inverter        = (*ssd1322)(nil)
+	_ Scroller        = (*ssd1322)(nil)
+	_ GrayscaleTabler = (*ssd1322)(nil)
+	_ Describer       = (*ssd1322)(nil)
+	_ Offsetter       = (*ssd1322)(nil)
 )
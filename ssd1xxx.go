@@ -5,35 +5,50 @@ import (
 )
 
 const (
-	ssd1xxxSetLowColumn          = 0x00
-	ssd1xxxSetHighColumn         = 0x10
-	ssd1xxxSetMemoryMode         = 0x20
-	ssd1xxxSetColumnAddr         = 0x21
-	ssd1xxxSetPageAddr           = 0x22
-	ssd1xxxSetStartLine          = 0x40
-	ssd1xxxSetContrast           = 0x81
-	ssd1xxxSetChargePump         = 0x8D
-	ssd1xxxSetRemap              = 0xA0
-	ssd1xxxSetSegmentRemap       = 0xA1
-	ssd1xxxSetDisplayAllOnResume = 0xA4
-	ssd1xxxSetDisplayAllOn       = 0xA5
-	ssd1xxxSetNormalDisplay      = 0xA6
-	ssd1xxxSetInvertDisplay      = 0xA7
-	ssd1xxxSetMultiplexRatio     = 0xA8
-	ssd1xxxSetDisplayOff         = 0xAE
-	ssd1xxxSetDisplayOn          = 0xAF
-	ssd1xxxSetComScanInc         = 0xC0
-	ssd1xxxSetComScanDec         = 0xC8
-	ssd1xxxSetDisplayOffset      = 0xD3
-	ssd1xxxSetDisplayClockDiv    = 0xD5
-	ssd1xxxSetPrecharge          = 0xD9
-	ssd1xxxSetComPins            = 0xDA
-	ssd1xxxSetVCOMDeselect       = 0xDB
-	ssd1xxxSetCommandLock        = 0xFD
-	externalVCC                  = 0x1
-	switchCapVCC                 = 0x2
+	ssd1xxxSetLowColumn                  = 0x00
+	ssd1xxxSetHighColumn                 = 0x10
+	ssd1xxxSetMemoryMode                 = 0x20
+	ssd1xxxSetColumnAddr                 = 0x21
+	ssd1xxxSetPageAddr                   = 0x22
+	ssd1xxxRightHorizontalScroll         = 0x26
+	ssd1xxxLeftHorizontalScroll          = 0x27
+	ssd1xxxVerticalRightHorizontalScroll = 0x29
+	ssd1xxxVerticalLeftHorizontalScroll  = 0x2A
+	ssd1xxxDeactivateScroll              = 0x2E
+	ssd1xxxActivateScroll                = 0x2F
+	ssd1xxxSetStartLine                  = 0x40
+	ssd1xxxSetContrast                   = 0x81
+	ssd1xxxSetChargePump                 = 0x8D
+	ssd1xxxSetRemap                      = 0xA0
+	ssd1xxxSetSegmentRemap               = 0xA1
+	ssd1xxxSetVerticalScrollArea         = 0xA3
+	ssd1xxxSetDisplayAllOnResume         = 0xA4
+	ssd1xxxSetDisplayAllOn               = 0xA5
+	ssd1xxxSetNormalDisplay              = 0xA6
+	ssd1xxxSetInvertDisplay              = 0xA7
+	ssd1xxxSetMultiplexRatio             = 0xA8
+	ssd1xxxSetDisplayOff                 = 0xAE
+	ssd1xxxSetDisplayOn                  = 0xAF
+	ssd1xxxSetComScanInc                 = 0xC0
+	ssd1xxxSetComScanDec                 = 0xC8
+	ssd1xxxSetDisplayOffset              = 0xD3
+	ssd1xxxSetDisplayClockDiv            = 0xD5
+	ssd1xxxSetPrecharge                  = 0xD9
+	ssd1xxxSetComPins                    = 0xDA
+	ssd1xxxSetVCOMDeselect               = 0xDB
+	ssd1xxxSetCommandLock                = 0xFD
+	externalVCC                          = 0x1
+	switchCapVCC                         = 0x2
 )
 
+// ssd1xxxFlip returns the segment remap and COM scan direction commands, flipped for 180° rotation.
+func ssd1xxxFlip(flip bool) (remap, comScan byte) {
+	if flip {
+		return ssd1xxxSetRemap, ssd1xxxSetComScanInc
+	}
+	return ssd1xxxSetSegmentRemap, ssd1xxxSetComScanDec
+}
+
 type ssd1xxxDisplay struct {
 	monoDisplay
 	useMono bool
@@ -72,8 +87,9 @@ func (d *ssd1xxxDisplay) SetContrast(level uint8) error {
 	return d.command(ssd1xxxSetContrast, level)
 }
 
+// SetRotation rotates the display in software.
 func (d *ssd1xxxDisplay) SetRotation(rotation Rotation) error {
-	d.rotation = rotation
+	d.setSoftwareRotation(rotation)
 	return nil
 }
 
@@ -3,10 +3,10 @@ package display
 import (
 	"encoding/binary"
 	"fmt"
+	"image"
 	"time"
 
 	"periph.io/x/conn/v3/gpio"
-	"periph.io/x/conn/v3/physic"
 
 	"github.com/BeatGlow/display/conn"
 	"github.com/BeatGlow/display/pixel"
@@ -79,9 +79,20 @@ const (
 	st7735PageAddressOrder                       // D7: MY
 )
 
+func init() {
+	Register(Driver{
+		Name:    "st7735",
+		Aliases: []string{"st7735r", "st7735s"},
+		Buses:   SPIBus,
+		Width:   st7735DefaultWidth,
+		Height:  st7735DefaultHeight,
+		New:     ST7735,
+	})
+}
+
 type st7735 struct {
 	crgb16Display
-	backlight gpio.PinOut
+	backlight *Backlight
 }
 
 func ST7735(c Conn, config *Config) (Display, error) {
@@ -100,7 +111,6 @@ func ST7735(c Conn, config *Config) (Display, error) {
 		crgb16Display: crgb16Display{
 			baseDisplay: baseDisplay{c: c},
 		},
-		backlight: config.Backlight,
 	}
 
 	// Common initialization
@@ -118,11 +128,11 @@ func (d *st7735) String() string {
 
 // command shadows display.command
 func (d *st7735) command(cmnd byte, data ...byte) (err error) {
-	if err = d.command(cmnd); err != nil {
+	if err = d.c.Command(cmnd); err != nil {
 		return
 	}
 	for _, data := range data {
-		if err = d.data(data); err != nil {
+		if err = d.c.Data(data); err != nil {
 			return
 		}
 	}
@@ -156,7 +166,7 @@ func (d *st7735) init(config *Config) (err error) {
 
 	if config.Height == 0 {
 		if config.Rotation == Rotate90 || config.Rotation == Rotate270 {
-			config.Width = st7735DefaultWidth
+			config.Height = st7735DefaultWidth
 		} else {
 			config.Height = st7735DefaultHeight
 		}
@@ -175,10 +185,7 @@ func (d *st7735) init(config *Config) (err error) {
 	}
 
 	if config.Backlight != nil {
-		d.backlight = config.Backlight
-		if err = d.backlight.PWM(gpio.DutyMax, 2000*physic.Hertz); err != nil {
-			return
-		}
+		d.backlight = NewBacklight(config.Backlight)
 	}
 
 	// reset the device.
@@ -239,6 +246,9 @@ func (d *st7735) init(config *Config) (err error) {
 }
 
 func (d *st7735) Close() error {
+	if d.backlight != nil {
+		_ = d.backlight.Close()
+	}
 	if err := d.Show(false); err != nil {
 		_ = d.c.Close()
 		return err
@@ -254,18 +264,12 @@ func (d *st7735) Show(show bool) error {
 	return d.command(command)
 }
 
+// SetContrast sets the backlight brightness, if the display has a backlight pin.
 func (d *st7735) SetContrast(level uint8) error {
 	if d.backlight == nil {
 		return nil
 	}
-	//if level == 0xFF {
-	//	return d.backlight.Out(gpio.High)
-	//}
-	const (
-		step = gpio.DutyMax / 0xFF
-		rate = 2 * physic.KiloHertz
-	)
-	return d.backlight.PWM(step*gpio.Duty(level), rate)
+	return d.backlight.SetBrightness(level)
 }
 
 func (d *st7735) SetRotation(rotation Rotation) error {
@@ -294,6 +298,11 @@ func (d *st7735) SetWindow(x0, y0, x1, y1 int) error {
 	if y1 == 0 {
 		y1 = d.height - 1
 	}
+	return d.setWindow(x0, y0, x1, y1)
+}
+
+// setWindow sets the (inclusive) RAM window and starts a memory write.
+func (d *st7735) setWindow(x0, y0, x1, y1 int) error {
 	if d.rotation == Rotate90 || d.rotation == Rotate270 {
 		x0 += d.rowOffset
 		y0 += d.colOffset
@@ -317,20 +326,71 @@ func (d *st7735) SetWindow(x0, y0, x1, y1 int) error {
 
 // Refresh sets the window to full screen and redraws using the internal frame buffer.
 func (d *st7735) Refresh() error {
-	if err := d.SetWindow(0, 0, 0, 0); err != nil {
+	return d.RefreshRect(d.Bounds())
+}
+
+// RefreshRect sets the window to r and redraws it using the internal frame buffer.
+func (d *st7735) RefreshRect(r image.Rectangle) error {
+	if r = d.bufferRect(r); r.Empty() {
+		return nil
+	}
+	if err := d.setWindow(r.Min.X, r.Min.Y, r.Max.X-1, r.Max.Y-1); err != nil {
 		return err
 	}
 	const batchSize = 4096
 
-	pix := d.Image.(*pixel.CRGB16Image).Pix
-	for i, l := 0, len(pix); i < l; i += batchSize {
-		j := i + batchSize
-		if j > l {
-			j = l
+	img := d.Image.(*pixel.CRGB16Image)
+	if r.Dx() == d.width {
+		pix := img.Pix[r.Min.Y*img.Stride : r.Max.Y*img.Stride]
+		for i, l := 0, len(pix); i < l; i += batchSize {
+			j := i + batchSize
+			if j > l {
+				j = l
+			}
+			if err := d.data(pix[i:j]...); err != nil {
+				return err
+			}
 		}
-		if err := d.data(pix[i:j]...); err != nil {
+		return nil
+	}
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		off := y*img.Stride + r.Min.X*2
+		if err := d.data(img.Pix[off : off+r.Dx()*2]...); err != nil {
 			return err
 		}
 	}
 	return nil
 }
+
+// SetInvert toggles inverted pixels.
+func (d *st7735) SetInvert(invert bool) error {
+	if invert {
+		return d.command(st7735INVON)
+	}
+	return d.command(st7735INVOFF)
+}
+
+// SetSleep toggles the sleep mode. The controller needs 120ms to wake up, before it accepts a new
+// sleep command.
+func (d *st7735) SetSleep(sleep bool) (err error) {
+	if sleep {
+		err = d.command(st7735SLPIN)
+	} else {
+		err = d.command(st7735SLPOUT)
+	}
+	time.Sleep(120 * time.Millisecond)
+	return
+}
+
+// Info describes the ST7735 controller.
+func (d *st7735) Info() Info {
+	return d.describe("ST7735", d)
+}
+
+// Interface checks
+var (
+	_ WindowRefresher = (*st7735)(nil)
+	_ Inverter        = (*st7735)(nil)
+	_ Sleeper         = (*st7735)(nil)
+	_ Describer       = (*st7735)(nil)
+)
@@ -3,6 +3,7 @@ package display
 import (
 	"encoding/binary"
 	"fmt"
+	"image"
 	"time"
 
 	"periph.io/x/conn/v3/gpio"
@@ -14,6 +15,7 @@ import (
 const (
 	st7789DefaultWidth  = 240
 	st7789DefaultHeight = 240
+	st7789RAMHeight     = 320
 )
 
 // Registers (from st7789.pdf).
@@ -112,8 +114,21 @@ const (
 	st7789PageAddressOrder                       // D7: MY
 )
 
+func init() {
+	Register(Driver{
+		Name:    "st7789",
+		Aliases: []string{"st7789v"},
+		Buses:   SPIBus,
+		Width:   st7789DefaultWidth,
+		Height:  st7789DefaultHeight,
+		New:     ST7789,
+	})
+}
+
 type st7789 struct {
 	crgb16Display
+	ramOffset int // unused RAM rows below the panel
+	backlight *Backlight
 }
 
 func ST7789(c Conn, config *Config) (Display, error) {
@@ -133,6 +148,9 @@ func ST7789(c Conn, config *Config) (Display, error) {
 			baseDisplay: baseDisplay{c: c},
 		},
 	}
+	if config.Backlight != nil {
+		d.backlight = NewBacklight(config.Backlight)
+	}
 
 	// Common initialization
 	if err := d.init(config); err != nil {
@@ -143,6 +161,9 @@ func ST7789(c Conn, config *Config) (Display, error) {
 }
 
 func (d *st7789) Close() error {
+	if d.backlight != nil {
+		_ = d.backlight.Close()
+	}
 	if err := d.Show(false); err != nil {
 		_ = d.c.Close()
 		return err
@@ -195,6 +216,14 @@ func (d *st7789) init(config *Config) (err error) {
 		return fmt.Errorf("st7789: invalid size %dx%d, maximum size is 320x240 at %s rotation", config.Width, config.Height, config.Rotation)
 	}
 
+	// Panels with less than 320 rows are connected to the first RAM rows, so mirroring the row address
+	// order requires an offset.
+	if config.Rotation == Rotate90 || config.Rotation == Rotate270 {
+		d.ramOffset = st7789RAMHeight - config.Width
+	} else {
+		d.ramOffset = st7789RAMHeight - config.Height
+	}
+
 	// init base
 	if err = d.crgb16Display.init(config, binary.BigEndian); err != nil {
 		return
@@ -234,6 +263,7 @@ func (d *st7789) init(config *Config) (err error) {
 		{st7789FRCTR2, 0x0F},        // Frame Rate Control in Normal Mode: 60Hz (default)
 		{st7789PWCTRL1, 0xA4, 0xA1}, // Power Control 1: default
 		{st7789INVON},               // Partial Display Mode On
+		{st7789WRCTRLD, 0x2C},       // Write CTRL Display: brightness control, display dimming and backlight on
 		{st7789PVGAMCTRL, 0x00, 0x19, 0x1E, 0x0A, 0x09, 0x15, 0x3D, 0x44, 0x51, 0x12, 0x03, 0x00, 0x3F, 0x3F}, // Positive Voltage Gamma Control: default
 		{st7789NVGAMCTRL, 0x00, 0x18, 0x1E, 0x0A, 0x09, 0x25, 0x3F, 0x43, 0x52, 0x33, 0x03, 0x00, 0x3F, 0x3F}, // Negative Voltage Gamma Control: default
 		{st7789DISPON}, // Display On
@@ -242,7 +272,10 @@ func (d *st7789) init(config *Config) (err error) {
 	}
 	time.Sleep(100 * time.Millisecond)
 
-	return d.SetRotation(config.Rotation)
+	if err = d.SetRotation(config.Rotation); err != nil {
+		return
+	}
+	return d.SetContrast(0xFF)
 }
 
 func (d *st7789) Show(show bool) error {
@@ -253,15 +286,23 @@ func (d *st7789) Show(show bool) error {
 	return d.command(command)
 }
 
+// SetContrast sets the display brightness. The brightness is set in the controller, for panels with the
+// backlight driven by the LEDPWM output, and on the backlight pin, if the display has one.
 func (d *st7789) SetContrast(level uint8) error {
-	// TODO(maze): PWM the backlight
-	return nil
+	if err := d.command(st7789WRDISBV, level); err != nil {
+		return err
+	}
+	if d.backlight == nil {
+		return nil
+	}
+	return d.backlight.SetBrightness(level)
 }
 
 func (d *st7789) SetRotation(rotation Rotation) error {
 	rotation &= 3
 
 	var madctl byte
+	d.rowOffset = 0
 	switch rotation {
 	case NoRotation:
 		madctl = 0
@@ -269,8 +310,10 @@ func (d *st7789) SetRotation(rotation Rotation) error {
 		madctl = st7789ColumnAddressOrder | st7789PageColumnOrder
 	case Rotate180:
 		madctl = st7789ColumnAddressOrder | st7789PageAddressOrder
+		d.rowOffset = d.ramOffset
 	case Rotate270:
 		madctl = st7789PageAddressOrder | st7789PageColumnOrder
+		d.rowOffset = d.ramOffset
 	}
 
 	d.rotation = rotation
@@ -284,6 +327,11 @@ func (d *st7789) SetWindow(x0, y0, x1, y1 int) error {
 	if y1 == 0 {
 		y1 = d.height - 1
 	}
+	return d.setWindow(x0, y0, x1, y1)
+}
+
+// setWindow sets the (inclusive) RAM window and starts a memory write.
+func (d *st7789) setWindow(x0, y0, x1, y1 int) error {
 	if d.rotation == Rotate90 || d.rotation == Rotate270 {
 		x0 += d.rowOffset
 		y0 += d.colOffset
@@ -307,20 +355,72 @@ func (d *st7789) SetWindow(x0, y0, x1, y1 int) error {
 
 // Refresh sets the window to full screen and redraws using the internal frame buffer.
 func (d *st7789) Refresh() error {
-	if err := d.SetWindow(0, 0, 0, 0); err != nil {
+	return d.RefreshRect(d.Bounds())
+}
+
+// RefreshRect sets the window to r and redraws it using the internal frame buffer.
+func (d *st7789) RefreshRect(r image.Rectangle) error {
+	if r = d.bufferRect(r); r.Empty() {
+		return nil
+	}
+	if err := d.setWindow(r.Min.X, r.Min.Y, r.Max.X-1, r.Max.Y-1); err != nil {
 		return err
 	}
 	const batchSize = 4096
 
-	pix := d.Image.(*pixel.CRGB16Image).Pix
-	for i, l := 0, len(pix); i < l; i += batchSize {
-		j := i + batchSize
-		if j > l {
-			j = l
+	img := d.Image.(*pixel.CRGB16Image)
+	if r.Dx() == d.width {
+		pix := img.Pix[r.Min.Y*img.Stride : r.Max.Y*img.Stride]
+		for i, l := 0, len(pix); i < l; i += batchSize {
+			j := i + batchSize
+			if j > l {
+				j = l
+			}
+			if err := d.data(pix[i:j]...); err != nil {
+				return err
+			}
 		}
-		if err := d.data(pix[i:j]...); err != nil {
+		return nil
+	}
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		off := y*img.Stride + r.Min.X*2
+		if err := d.data(img.Pix[off : off+r.Dx()*2]...); err != nil {
 			return err
 		}
 	}
 	return nil
 }
+
+// SetInvert toggles inverted pixels. IPS panels need display inversion to show the correct colors, so
+// inverting the pixels disables the controller's display inversion.
+func (d *st7789) SetInvert(invert bool) error {
+	if invert {
+		return d.command(st7789INVOFF)
+	}
+	return d.command(st7789INVON)
+}
+
+// SetSleep toggles the sleep mode. The controller needs 120ms to wake up, before it accepts a new
+// sleep command.
+func (d *st7789) SetSleep(sleep bool) (err error) {
+	if sleep {
+		err = d.command(st7789SLPIN)
+	} else {
+		err = d.command(st7789SLPOUT)
+	}
+	time.Sleep(120 * time.Millisecond)
+	return
+}
+
+// Info describes the ST7789 controller.
+func (d *st7789) Info() Info {
+	return d.describe("ST7789", d)
+}
+
+// Interface checks
+var (
+	_ WindowRefresher = (*st7789)(nil)
+	_ Inverter        = (*st7789)(nil)
+	_ Sleeper         = (*st7789)(nil)
+	_ Describer       = (*st7789)(nil)
+)
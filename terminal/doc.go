@@ -0,0 +1,6 @@
+// Package terminal implements a display that renders to a terminal, for developing without hardware.
+//
+// Monochrome displays are rendered with Unicode half blocks (1×2 pixels per character) or braille patterns
+// (2×4 pixels per character), grayscale displays with the gray ramp of the 256 color palette and color
+// displays with 24-bit ANSI colors, using half blocks.
+package terminal
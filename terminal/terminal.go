@@ -0,0 +1,252 @@
+package terminal
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+	"strconv"
+
+	"github.com/BeatGlow/display"
+	"github.com/BeatGlow/display/internal/virtual"
+	"github.com/BeatGlow/display/pixel"
+)
+
+// Defaults.
+const (
+	DefaultWidth  = virtual.DefaultWidth
+	DefaultHeight = virtual.DefaultHeight
+)
+
+// ErrModel is returned for color models that can't be rendered.
+var ErrModel = virtual.ErrModel
+
+// Config is the terminal display configuration.
+type Config struct {
+	display.Config
+
+	// Model is the color model of the emulated display, one of [pixel.MonoModel] (default),
+	// [pixel.Gray2Model], [pixel.Gray4Model] or [pixel.CRGB16Model].
+	Model color.Model
+
+	// Braille renders monochrome displays with braille patterns instead of half blocks.
+	Braille bool
+}
+
+// Display renders to a terminal.
+//
+// The width, height and rotation from the configuration are honoured like drivers that rotate in
+// software: the display bounds are swapped when rotated by 90° or 270°. The terminal shows what a viewer
+// of the mounted panel would see, so the image is always rendered upright.
+//
+// Monochrome displays are rendered in the terminal's default colors, the contrast level only applies to
+// grayscale and color displays.
+type Display struct {
+	*virtual.Frame
+	w        io.Writer
+	braille  bool
+	contrast uint8
+	on       bool
+	started  bool
+	buf      bytes.Buffer
+}
+
+// New terminal display writing to w.
+func New(w io.Writer, config *Config) (*Display, error) {
+	if config == nil {
+		config = new(Config)
+	}
+	frame, err := virtual.NewFrame(config.Model, &config.Config)
+	if err != nil {
+		return nil, err
+	}
+	return &Display{
+		Frame:    frame,
+		w:        w,
+		braille:  config.Braille,
+		contrast: 0xFF,
+		on:       true,
+	}, nil
+}
+
+func (d *Display) String() string {
+	return "terminal " + d.Frame.String()
+}
+
+// Close resets the terminal colors.
+func (d *Display) Close() error {
+	if !d.started {
+		return nil
+	}
+	_, err := io.WriteString(d.w, "\x1b[0m")
+	return err
+}
+
+// Show toggles the display on or off, the terminal is redrawn immediately.
+func (d *Display) Show(show bool) error {
+	d.on = show
+	return d.Refresh()
+}
+
+// SetContrast adjusts the brightness of grayscale and color displays, the terminal is redrawn immediately.
+func (d *Display) SetContrast(level uint8) error {
+	d.contrast = level
+	return d.Refresh()
+}
+
+// Refresh redraws the terminal.
+func (d *Display) Refresh() error {
+	d.buf.Reset()
+	if !d.started {
+		// Clear the screen once, subsequent frames overwrite the previous frame.
+		d.buf.WriteString("\x1b[2J")
+		d.started = true
+	}
+	d.buf.WriteString("\x1b[H")
+
+	switch {
+	case d.ColorModel() == pixel.MonoModel && d.braille:
+		d.renderBraille()
+	case d.ColorModel() == pixel.MonoModel:
+		d.renderHalfBlocks()
+	default:
+		d.renderColor()
+	}
+
+	_, err := d.w.Write(d.buf.Bytes())
+	return err
+}
+
+// lit returns if the pixel at (x, y) is lit, pixels outside of the display are off.
+func (d *Display) lit(x, y int) bool {
+	if !d.on || !(image.Point{X: x, Y: y}).In(d.Bounds()) {
+		return false
+	}
+	return pixel.MonoModel.Convert(d.At(x, y)).(pixel.Mono).On
+}
+
+var halfBlocks = [4]string{" ", "▀", "▄", "█"}
+
+func (d *Display) renderHalfBlocks() {
+	b := d.Bounds()
+	for y := 0; y < b.Dy(); y += 2 {
+		for x := 0; x < b.Dx(); x++ {
+			var i int
+			if d.lit(x, y) {
+				i |= 1
+			}
+			if d.lit(x, y+1) {
+				i |= 2
+			}
+			d.buf.WriteString(halfBlocks[i])
+		}
+		d.buf.WriteByte('\n')
+	}
+}
+
+// brailleDots are the dot bits of a braille pattern, indexed by row and column.
+var brailleDots = [4][2]rune{
+	{0x01, 0x08},
+	{0x02, 0x10},
+	{0x04, 0x20},
+	{0x40, 0x80},
+}
+
+func (d *Display) renderBraille() {
+	b := d.Bounds()
+	for y := 0; y < b.Dy(); y += 4 {
+		for x := 0; x < b.Dx(); x += 2 {
+			r := rune(0x2800)
+			for row := 0; row < 4; row++ {
+				for col := 0; col < 2; col++ {
+					if d.lit(x+col, y+row) {
+						r |= brailleDots[row][col]
+					}
+				}
+			}
+			d.buf.WriteRune(r)
+		}
+		d.buf.WriteByte('\n')
+	}
+}
+
+// rgb returns the color of the pixel at (x, y), scaled by the contrast level.
+func (d *Display) rgb(x, y int) (c [3]uint8) {
+	if !d.on || !(image.Point{X: x, Y: y}).In(d.Bounds()) {
+		return
+	}
+	switch v := d.At(x, y).(type) {
+	case pixel.Gray2:
+		c[0] = (v.Y & 3) * 0x55
+		c[1], c[2] = c[0], c[0]
+	case pixel.Gray4:
+		c[0] = (v.Y & 0xF) * 0x11
+		c[1], c[2] = c[0], c[0]
+	default:
+		r, g, b, _ := v.RGBA()
+		c = [3]uint8{uint8(r >> 8), uint8(g >> 8), uint8(b >> 8)}
+	}
+	for i := range c {
+		c[i] = uint8(uint16(c[i]) * uint16(d.contrast) / 0xFF)
+	}
+	return
+}
+
+// renderColor renders two pixels per character, the foreground is the upper pixel and the background
+// the lower pixel. Escape sequences are only emitted if the colors change.
+func (d *Display) renderColor() {
+	var (
+		b      = d.Bounds()
+		gray   = d.ColorModel() != pixel.CRGB16Model
+		fg, bg [3]uint8
+	)
+	for y := 0; y < b.Dy(); y += 2 {
+		for x := 0; x < b.Dx(); x++ {
+			top, bottom := d.rgb(x, y), d.rgb(x, y+1)
+			if x == 0 || top != fg {
+				d.writeColor(38, top, gray)
+			}
+			if x == 0 || bottom != bg {
+				d.writeColor(48, bottom, gray)
+			}
+			fg, bg = top, bottom
+			d.buf.WriteString("▀")
+		}
+		d.buf.WriteString("\x1b[0m\n")
+	}
+}
+
+// writeColor writes a foreground (38) or background (48) color escape sequence.
+func (d *Display) writeColor(layer int, c [3]uint8, gray bool) {
+	d.buf.WriteString("\x1b[")
+	d.buf.WriteString(strconv.Itoa(layer))
+	if gray {
+		d.buf.WriteString(";5;")
+		d.buf.WriteString(strconv.Itoa(grayIndex(c[0])))
+	} else {
+		fmt.Fprintf(&d.buf, ";2;%d;%d;%d", c[0], c[1], c[2])
+	}
+	d.buf.WriteByte('m')
+}
+
+// grayIndex returns the closest color in the 256 color palette for gray level v. The gray ramp (232-255)
+// holds 24 levels from 8 to 238, black (16) and white (231) are taken from the color cube.
+func grayIndex(v uint8) int {
+	switch {
+	case v < 4:
+		return 16
+	case v > 246:
+		return 231
+	case v < 8:
+		return 232
+	default:
+		return 232 + min((int(v)-8+5)/10, 23)
+	}
+}
+
+// Interface checks
+var (
+	_ display.Display  = (*Display)(nil)
+	_ display.Buffered = (*Display)(nil)
+)
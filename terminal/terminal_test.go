@@ -0,0 +1,143 @@
+package terminal_test
+
+import (
+	"bytes"
+	"errors"
+	"image/color"
+	"strings"
+	"testing"
+
+	"github.com/BeatGlow/display"
+	"github.com/BeatGlow/display/pixel"
+	"github.com/BeatGlow/display/terminal"
+)
+
+// frame returns the last frame written, without the leading escape sequences.
+func frame(out *bytes.Buffer) string {
+	s := out.String()
+	if i := strings.LastIndex(s, "\x1b[H"); i >= 0 {
+		s = s[i+3:]
+	}
+	out.Reset()
+	return s
+}
+
+func TestHalfBlocks(t *testing.T) {
+	out := new(bytes.Buffer)
+	d, err := terminal.New(out, &terminal.Config{Config: display.Config{Width: 4, Height: 3}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.Set(0, 0, pixel.On)
+	d.Set(1, 1, pixel.On)
+	d.Set(2, 0, pixel.On)
+	d.Set(2, 1, pixel.On)
+	d.Set(3, 2, color.White)
+	if err = d.Refresh(); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(out.String(), "\x1b[2J\x1b[H") {
+		t.Errorf("expected screen to be cleared, got %q", out.String())
+	}
+	if v, want := frame(out), "▀▄█ \n   ▀\n"; v != want {
+		t.Errorf("expected %q, got %q", want, v)
+	}
+
+	if err = d.Show(false); err != nil {
+		t.Fatal(err)
+	}
+	if v, want := frame(out), "    \n    \n"; v != want {
+		t.Errorf("expected blank display, got %q", v)
+	}
+}
+
+func TestBraille(t *testing.T) {
+	out := new(bytes.Buffer)
+	d, err := terminal.New(out, &terminal.Config{Config: display.Config{Width: 4, Height: 4}, Braille: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.Set(0, 0, pixel.On)
+	d.Set(1, 3, pixel.On)
+	d.Set(3, 1, pixel.On)
+	if err = d.Refresh(); err != nil {
+		t.Fatal(err)
+	}
+	if v, want := frame(out), "⢁⠐\n"; v != want {
+		t.Errorf("expected %q, got %q", want, v)
+	}
+}
+
+func TestRotation(t *testing.T) {
+	out := new(bytes.Buffer)
+	d, err := terminal.New(out, &terminal.Config{Config: display.Config{Width: 4, Height: 2, Rotation: display.Rotate90}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b := d.Bounds(); b.Dx() != 2 || b.Dy() != 4 {
+		t.Fatalf("expected 2x4 bounds, got %s", b)
+	}
+	d.Set(0, 0, pixel.On)
+	if !d.FrameBuffer().At(3, 0).(pixel.Mono).On {
+		t.Error("expected frame buffer pixel 3,0 to be set")
+	}
+	if err = d.Refresh(); err != nil {
+		t.Fatal(err)
+	}
+	if v, want := frame(out), "▀ \n  \n"; v != want {
+		t.Errorf("expected %q, got %q", want, v)
+	}
+}
+
+func TestGray(t *testing.T) {
+	out := new(bytes.Buffer)
+	d, err := terminal.New(out, &terminal.Config{Config: display.Config{Width: 2, Height: 2}, Model: pixel.Gray4Model})
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.Set(0, 0, pixel.Gray4{Y: 0xF})
+	d.Set(1, 0, pixel.Gray4{Y: 0xF})
+	d.Set(1, 1, pixel.Gray4{Y: 0x8})
+	if err = d.Refresh(); err != nil {
+		t.Fatal(err)
+	}
+	if v, want := frame(out), "\x1b[38;5;231m\x1b[48;5;16m▀\x1b[48;5;245m▀\x1b[0m\n"; v != want {
+		t.Errorf("expected %q, got %q", want, v)
+	}
+}
+
+func TestColor(t *testing.T) {
+	out := new(bytes.Buffer)
+	d, err := terminal.New(out, &terminal.Config{Config: display.Config{Width: 1, Height: 2}, Model: pixel.CRGB16Model})
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.Set(0, 0, color.RGBA{R: 0xFF, A: 0xFF})
+	d.Set(0, 1, color.RGBA{B: 0xFF, A: 0xFF})
+	if err = d.Refresh(); err != nil {
+		t.Fatal(err)
+	}
+	if v, want := frame(out), "\x1b[38;2;255;0;0m\x1b[48;2;0;0;255m▀\x1b[0m\n"; v != want {
+		t.Errorf("expected %q, got %q", want, v)
+	}
+
+	if err = d.SetContrast(0x80); err != nil {
+		t.Fatal(err)
+	}
+	if v, want := frame(out), "\x1b[38;2;128;0;0m\x1b[48;2;0;0;128m▀\x1b[0m\n"; v != want {
+		t.Errorf("expected %q, got %q", want, v)
+	}
+}
+
+func TestModel(t *testing.T) {
+	if _, err := terminal.New(nil, &terminal.Config{Model: pixel.CBGR15Model}); !errors.Is(err, terminal.ErrModel) {
+		t.Errorf("expected %v, got %v", terminal.ErrModel, err)
+	}
+	d, err := terminal.New(nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, want := d.String(), "terminal 128x64 monochrome"; v != want {
+		t.Errorf("expected %q, got %q", want, v)
+	}
+}
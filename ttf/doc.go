@@ -0,0 +1,20 @@
+// Package ttf renders TrueType and OpenType fonts.
+//
+// Fonts are rasterized with [golang.org/x/image/font/opentype] into faces that implement
+// [golang.org/x/image/font.Face], for the text functions of the draw package. Faces render antialiased
+// glyphs for grayscale and color displays, and hinted glyphs without antialiasing for monochrome displays,
+// where gray edges would be thresholded into uneven strokes.
+//
+// Rendered glyphs are cached by the face, so redrawing the same labels every frame doesn't rasterize their
+// outlines again:
+//
+//	f, err := ttf.Load("DejaVuSans.ttf")
+//	if err != nil {
+//		return err
+//	}
+//	face, err := f.NewFace(&ttf.FaceConfig{Size: 16, Model: d.ColorModel()})
+//	if err != nil {
+//		return err
+//	}
+//	draw.Text(d, image.Pt(0, 16), face, color.White, "Now playing")
+package ttf
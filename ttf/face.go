@@ -0,0 +1,209 @@
+package ttf
+
+import (
+	"container/list"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"sync"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+
+	"github.com/BeatGlow/display/pixel"
+)
+
+// Defaults for zero [FaceConfig] values.
+const (
+	DefaultSize      = 12
+	DefaultDPI       = 72
+	DefaultCacheSize = 256
+)
+
+// subpixel is the horizontal resolution of antialiased glyph positions, a quarter pixel.
+const subpixel = 16
+
+// FaceConfig is the [Face] configuration.
+type FaceConfig struct {
+	// Size is the font size in points, or DefaultSize if zero.
+	Size float64
+
+	// DPI is the resolution in dots per inch, or DefaultDPI if zero, so points are pixels.
+	DPI float64
+
+	// Model is the color model of the images the face is drawn into. Faces for [pixel.MonoModel] are hinted
+	// and not antialiased, faces for other models (or nil) are antialiased.
+	Model color.Model
+
+	// CacheSize is the maximum number of cached glyphs, or DefaultCacheSize if zero. The least recently used
+	// glyphs are dropped from the cache.
+	CacheSize int
+}
+
+// Face is a font face that caches rendered glyphs. It implements [font.Face], and unlike most faces it is
+// safe for concurrent use; the returned glyph masks are never modified.
+//
+// Antialiased glyphs are positioned in quarter pixels, so every glyph is cached for up to four offsets.
+// Hinted glyphs are aligned to whole pixels, with rounded metrics, advances and kerning, and their masks
+// are thresholded to fully opaque or transparent pixels.
+type Face struct {
+	mu    sync.Mutex
+	face  font.Face
+	mono  bool
+	size  int
+	cache map[glyphKey]*list.Element
+	lru   *list.List // of *glyph, most recently used first
+}
+
+type glyphKey struct {
+	r rune
+	x fixed.Int26_6 // subpixel offset
+}
+
+type glyph struct {
+	key     glyphKey
+	mask    *image.Alpha // bounds relative to the dot, nil if the rune can't be drawn
+	advance fixed.Int26_6
+	ok      bool
+}
+
+// NewFace returns a face of the font.
+func (f *Font) NewFace(config *FaceConfig) (*Face, error) {
+	if config == nil {
+		config = new(FaceConfig)
+	}
+	options := &opentype.FaceOptions{
+		Size: config.Size,
+		DPI:  config.DPI,
+	}
+	if options.Size == 0 {
+		options.Size = DefaultSize
+	}
+	if options.DPI == 0 {
+		options.DPI = DefaultDPI
+	}
+	if options.Size < 0 || options.DPI < 0 {
+		return nil, fmt.Errorf("ttf: invalid font size %g at %g dpi", options.Size, options.DPI)
+	}
+	mono := config.Model == pixel.MonoModel
+	if mono {
+		options.Hinting = font.HintingFull
+	}
+	face, err := opentype.NewFace(f.f, options)
+	if err != nil {
+		return nil, fmt.Errorf("ttf: %w", err)
+	}
+
+	size := config.CacheSize
+	if size <= 0 {
+		size = DefaultCacheSize
+	}
+	return &Face{
+		face:  face,
+		mono:  mono,
+		size:  size,
+		cache: make(map[glyphKey]*list.Element),
+		lru:   list.New(),
+	}, nil
+}
+
+// Antialiased reports whether the face renders antialiased glyphs.
+func (f *Face) Antialiased() bool {
+	return !f.mono
+}
+
+// Close drops the cached glyphs.
+func (f *Face) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	clear(f.cache)
+	f.lru.Init()
+	return f.face.Close()
+}
+
+// Glyph returns the cached glyph for r at dot, rendering it if it isn't cached.
+func (f *Face) Glyph(dot fixed.Point26_6, r rune) (dr image.Rectangle, mask image.Image, maskp image.Point, advance fixed.Int26_6, ok bool) {
+	var (
+		p = image.Pt(dot.X.Floor(), dot.Y.Round())
+		x = (dot.X - fixed.I(p.X) + subpixel/2) &^ (subpixel - 1)
+	)
+	if f.mono || x == fixed.I(1) {
+		p.X, x = dot.X.Round(), 0
+	}
+
+	f.mu.Lock()
+	g := f.glyph(glyphKey{r: r, x: x})
+	f.mu.Unlock()
+
+	if g.mask == nil {
+		return image.Rectangle{}, nil, image.Point{}, g.advance, g.ok
+	}
+	return g.mask.Rect.Add(p), g.mask, g.mask.Rect.Min, g.advance, g.ok
+}
+
+// glyph returns the cached glyph for key, f.mu must be held.
+func (f *Face) glyph(key glyphKey) *glyph {
+	if e, ok := f.cache[key]; ok {
+		f.lru.MoveToFront(e)
+		return e.Value.(*glyph)
+	}
+
+	g := &glyph{key: key}
+	dr, mask, mp, advance, ok := f.face.Glyph(fixed.Point26_6{X: key.x}, key.r)
+	g.advance, g.ok = advance, ok
+	if mask != nil {
+		// The mask of the face is reused by the next glyph, so it is copied.
+		g.mask = image.NewAlpha(dr)
+		draw.Draw(g.mask, dr, mask, mp, draw.Src)
+		if f.mono {
+			for i, a := range g.mask.Pix {
+				if a >= 0x80 {
+					g.mask.Pix[i] = 0xff
+				} else {
+					g.mask.Pix[i] = 0
+				}
+			}
+		}
+	}
+
+	if f.lru.Len() >= f.size {
+		delete(f.cache, f.lru.Remove(f.lru.Back()).(*glyph).key)
+	}
+	f.cache[key] = f.lru.PushFront(g)
+	return g
+}
+
+// GlyphBounds returns the bounding box of r, relative to the dot.
+func (f *Face) GlyphBounds(r rune) (bounds fixed.Rectangle26_6, advance fixed.Int26_6, ok bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.face.GlyphBounds(r)
+}
+
+// GlyphAdvance returns the advance width of r.
+func (f *Face) GlyphAdvance(r rune) (advance fixed.Int26_6, ok bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.face.GlyphAdvance(r)
+}
+
+// Kern returns the horizontal adjustment for the kerning pair (r0, r1).
+func (f *Face) Kern(r0, r1 rune) fixed.Int26_6 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.face.Kern(r0, r1)
+}
+
+// Metrics returns the metrics of the face.
+func (f *Face) Metrics() font.Metrics {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.face.Metrics()
+}
+
+// Interface checks
+var (
+	_ font.Face = (*Face)(nil)
+)
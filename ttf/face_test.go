@@ -0,0 +1,200 @@
+package ttf
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+
+	"github.com/BeatGlow/display/draw"
+	"github.com/BeatGlow/display/pixel"
+)
+
+func parse(t testing.TB) *Font {
+	t.Helper()
+	f, err := Parse(goregular.TTF)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return f
+}
+
+func newFace(t testing.TB, config *FaceConfig) *Face {
+	t.Helper()
+	face, err := parse(t).NewFace(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return face
+}
+
+func TestParse(t *testing.T) {
+	if name := parse(t).Name(); name != "Go Regular" {
+		t.Errorf("expected font name Go Regular, got %q", name)
+	}
+	if _, err := Parse([]byte("not a font")); err == nil {
+		t.Error("expected an error for invalid font data")
+	}
+	if _, err := parse(t).NewFace(&FaceConfig{Size: -1}); err == nil {
+		t.Error("expected an error for a negative size")
+	}
+}
+
+// levels returns the distinct alpha values of the masks of the glyphs of s.
+func levels(face font.Face, s string) map[uint8]bool {
+	seen := make(map[uint8]bool)
+	dot := fixed.P(0, 20)
+	for _, r := range s {
+		_, mask, _, advance, _ := face.Glyph(dot, r)
+		for _, a := range mask.(*image.Alpha).Pix {
+			seen[a] = true
+		}
+		dot.X += advance
+	}
+	return seen
+}
+
+func TestFaceMono(t *testing.T) {
+	face := newFace(t, &FaceConfig{Size: 16, Model: pixel.MonoModel})
+	if face.Antialiased() {
+		t.Error("expected a face without antialiasing for a mono model")
+	}
+	for a := range levels(face, "Hello, world") {
+		if a != 0 && a != 0xff {
+			t.Fatalf("expected opaque or transparent pixels, got alpha %#02x", a)
+		}
+	}
+
+	// Hinted glyphs are aligned to whole pixels, so fractional positions don't change the glyphs.
+	var (
+		dr0, mask0, _, advance, _ = face.Glyph(fixed.P(10, 20), 'a')
+		dr1, mask1, _, _, _       = face.Glyph(fixed.Point26_6{X: fixed.I(10) + 20, Y: fixed.I(20)}, 'a')
+	)
+	if dr0 != dr1 || mask0 != mask1 {
+		t.Errorf("expected the same glyph at a fractional position, got %v and %v", dr0, dr1)
+	}
+	if advance&63 != 0 {
+		t.Errorf("expected a whole pixel advance, got %v", advance)
+	}
+}
+
+func TestFaceAntialiased(t *testing.T) {
+	for _, m := range []color.Model{nil, pixel.Gray4Model, pixel.CRGB16Model} {
+		face := newFace(t, &FaceConfig{Size: 16, Model: m})
+		if !face.Antialiased() {
+			t.Errorf("%T: expected an antialiased face", m)
+		}
+		if n := len(levels(face, "Hello, world")); n < 16 {
+			t.Errorf("%T: expected antialiased glyphs, got %d alpha levels", m, n)
+		}
+	}
+
+	// Glyphs at whole pixels are the same as the glyphs of the opentype face.
+	var (
+		face                      = newFace(t, &FaceConfig{Size: 16})
+		ot, _                     = opentype.NewFace(parse(t).f, &opentype.FaceOptions{Size: 16, DPI: 72})
+		dot                       = fixed.P(7, 19)
+		dr, mask, mp, advance, ok = face.Glyph(dot, 'g')
+	)
+	wantr, wantMask, wantp, wantAdvance, wantOk := ot.Glyph(dot, 'g')
+	if dr != wantr || advance != wantAdvance || ok != wantOk {
+		t.Fatalf("expected glyph %v advance %v, got %v advance %v", wantr, wantAdvance, dr, advance)
+	}
+	for y := 0; y < dr.Dy(); y++ {
+		for x := 0; x < dr.Dx(); x++ {
+			got := mask.(*image.Alpha).AlphaAt(mp.X+x, mp.Y+y)
+			if want := wantMask.(*image.Alpha).AlphaAt(wantp.X+x, wantp.Y+y); got != want {
+				t.Fatalf("pixel %d,%d: expected alpha %#02x, got %#02x", x, y, want.A, got.A)
+			}
+		}
+	}
+}
+
+func TestFaceDraw(t *testing.T) {
+	// Antialiased text has gray levels between the background and text colors.
+	var (
+		face = newFace(t, &FaceConfig{Size: 16, Model: pixel.Gray4Model})
+		dst  = pixel.NewGray4Image(128, 24)
+		seen = make(map[pixel.Gray4]bool)
+	)
+	draw.Text(dst, image.Pt(2, 18), face, pixel.Gray4{Y: 15}, "Antialiased")
+	for y := 0; y < 24; y++ {
+		for x := 0; x < 128; x++ {
+			seen[dst.At(x, y).(pixel.Gray4)] = true
+		}
+	}
+	if len(seen) < 8 {
+		t.Errorf("expected gray levels, got %d", len(seen))
+	}
+
+	mono := pixel.NewMonoImage(128, 24)
+	draw.Text(mono, image.Pt(2, 18), newFace(t, &FaceConfig{Size: 16, Model: mono.ColorModel()}), pixel.On, "Hinted")
+	var n int
+	for y := 0; y < 24; y++ {
+		for x := 0; x < 128; x++ {
+			if mono.At(x, y) == pixel.On {
+				n++
+			}
+		}
+	}
+	if n == 0 {
+		t.Error("expected text to be drawn")
+	}
+}
+
+func TestFaceCache(t *testing.T) {
+	face := newFace(t, &FaceConfig{CacheSize: 2})
+	_, a, _, _, _ := face.Glyph(fixed.P(0, 12), 'a')
+	if _, again, _, _, _ := face.Glyph(fixed.P(0, 12), 'a'); again != a {
+		t.Error("expected the cached glyph")
+	}
+
+	// Subpixel positions are cached separately, and rounded to a quarter pixel.
+	_, b, _, _, _ := face.Glyph(fixed.Point26_6{X: 16, Y: fixed.I(12)}, 'a')
+	if b == a {
+		t.Error("expected another glyph at a quarter pixel offset")
+	}
+	if _, again, _, _, _ := face.Glyph(fixed.Point26_6{X: 20, Y: fixed.I(12)}, 'a'); again != b {
+		t.Error("expected the glyph at the nearest quarter pixel offset")
+	}
+
+	// The least recently used glyph is dropped.
+	face.Glyph(fixed.P(0, 12), 'a')
+	face.Glyph(fixed.P(0, 12), 'c')
+	if _, ok := face.cache[glyphKey{r: 'a'}]; !ok {
+		t.Error("expected the recently used glyph to be cached")
+	}
+	if _, ok := face.cache[glyphKey{r: 'a', x: 16}]; ok || face.lru.Len() != 2 {
+		t.Errorf("expected the least recently used glyph to be dropped, cache has %d glyphs", face.lru.Len())
+	}
+
+	// Missing glyphs are cached too.
+	if _, _, _, _, ok := face.Glyph(fixed.P(0, 12), '\U0010fffd'); ok {
+		t.Error("expected a missing glyph")
+	}
+}
+
+func BenchmarkText(b *testing.B) {
+	const label = "Now playing: 03:14"
+
+	var (
+		dst   = pixel.NewGray4Image(256, 32)
+		white = pixel.Gray4{Y: 15}
+		ot, _ = opentype.NewFace(parse(b).f, &opentype.FaceOptions{Size: 16, DPI: 72})
+	)
+	b.Run("cached", func(b *testing.B) {
+		face := newFace(b, &FaceConfig{Size: 16, Model: dst.ColorModel()})
+		for i := 0; i < b.N; i++ {
+			draw.Text(dst, image.Pt(0, 20), face, white, label)
+		}
+	})
+	b.Run("opentype", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			draw.Text(dst, image.Pt(0, 20), ot, white, label)
+		}
+	})
+}
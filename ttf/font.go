@@ -0,0 +1,41 @@
+package ttf
+
+import (
+	"fmt"
+	"os"
+
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/font/sfnt"
+)
+
+// Font is a parsed TrueType or OpenType font.
+type Font struct {
+	f *opentype.Font
+}
+
+// Parse parses a TrueType or OpenType font. The data must not be modified while the font is in use.
+func Parse(data []byte) (*Font, error) {
+	f, err := opentype.Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("ttf: %w", err)
+	}
+	return &Font{f: f}, nil
+}
+
+// Load reads and parses a TrueType or OpenType font file.
+func Load(name string) (*Font, error) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(data)
+}
+
+// Name returns the full name of the font, or an empty string if the font has no name.
+func (f *Font) Name() string {
+	name, err := f.f.Name(nil, sfnt.NameIDFull)
+	if err != nil {
+		return ""
+	}
+	return name
+}